@@ -0,0 +1,445 @@
+package aci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Types of interface policy group.
+const (
+	PolicyGroupAccess      = "access"
+	PolicyGroupPortChannel = "link"
+	PolicyGroupVPC         = "node"
+)
+
+// PolicyGroup is an interface policy group, the AAEP and interface
+// policies of the access ports, port-channels or vPCs using it.
+type PolicyGroup struct {
+	object
+	groupType string
+	aaep      string
+	linkLevel string
+	cdp       string
+	lldp      string
+	lacp      string
+	mcp       string
+	stp       string
+	storm     string
+}
+
+// Type returns the type of the policy group, one of PolicyGroupAccess,
+// PolicyGroupPortChannel or PolicyGroupVPC.
+func (pg *PolicyGroup) Type() string {
+	return pg.groupType
+}
+
+// DN returns the distinguished name of the policy group.
+func (pg *PolicyGroup) DN() string {
+	return policyGroupDN(pg.groupType, pg.name)
+}
+
+// AAEP returns the name of the AAEP of the policy group.
+func (pg *PolicyGroup) AAEP() string {
+	return pg.aaep
+}
+
+// SetAAEP validates and sets the name of the AAEP of the policy group.
+func (pg *PolicyGroup) SetAAEP(aaep string) error {
+	if err := validateName("aaep name", aaep); err != nil {
+		return err
+	}
+	pg.aaep = aaep
+	return nil
+}
+
+// LinkLevelPolicy returns the name of the link level policy of the policy group.
+func (pg *PolicyGroup) LinkLevelPolicy() string {
+	return pg.linkLevel
+}
+
+// SetLinkLevelPolicy validates and sets the name of the link level
+// policy of the policy group.
+func (pg *PolicyGroup) SetLinkLevelPolicy(policy string) error {
+	if err := validateName("link level policy name", policy); err != nil {
+		return err
+	}
+	pg.linkLevel = policy
+	return nil
+}
+
+// CDPPolicy returns the name of the CDP policy of the policy group.
+func (pg *PolicyGroup) CDPPolicy() string {
+	return pg.cdp
+}
+
+// SetCDPPolicy validates and sets the name of the CDP policy of the policy group.
+func (pg *PolicyGroup) SetCDPPolicy(policy string) error {
+	if err := validateName("cdp policy name", policy); err != nil {
+		return err
+	}
+	pg.cdp = policy
+	return nil
+}
+
+// LLDPPolicy returns the name of the LLDP policy of the policy group.
+func (pg *PolicyGroup) LLDPPolicy() string {
+	return pg.lldp
+}
+
+// SetLLDPPolicy validates and sets the name of the LLDP policy of the policy group.
+func (pg *PolicyGroup) SetLLDPPolicy(policy string) error {
+	if err := validateName("lldp policy name", policy); err != nil {
+		return err
+	}
+	pg.lldp = policy
+	return nil
+}
+
+// LACPPolicy returns the name of the LACP policy of the policy group.
+func (pg *PolicyGroup) LACPPolicy() string {
+	return pg.lacp
+}
+
+// SetLACPPolicy validates and sets the name of the LACP policy of the
+// policy group. Only port-channel and vPC policy groups have one.
+func (pg *PolicyGroup) SetLACPPolicy(policy string) error {
+	if pg.groupType == PolicyGroupAccess {
+		return fmt.Errorf("invalid lacp policy: %s for an access policy group", policy)
+	}
+	if err := validateName("lacp policy name", policy); err != nil {
+		return err
+	}
+	pg.lacp = policy
+	return nil
+}
+
+// MCPPolicy returns the name of the MCP policy of the policy group.
+func (pg *PolicyGroup) MCPPolicy() string {
+	return pg.mcp
+}
+
+// SetMCPPolicy validates and sets the name of the MCP policy of the policy group.
+func (pg *PolicyGroup) SetMCPPolicy(policy string) error {
+	if err := validateName("mcp policy name", policy); err != nil {
+		return err
+	}
+	pg.mcp = policy
+	return nil
+}
+
+// STPPolicy returns the name of the STP policy of the policy group.
+func (pg *PolicyGroup) STPPolicy() string {
+	return pg.stp
+}
+
+// SetSTPPolicy validates and sets the name of the STP policy of the policy group.
+func (pg *PolicyGroup) SetSTPPolicy(policy string) error {
+	if err := validateName("stp policy name", policy); err != nil {
+		return err
+	}
+	pg.stp = policy
+	return nil
+}
+
+// StormControlPolicy returns the name of the storm control policy of the policy group.
+func (pg *PolicyGroup) StormControlPolicy() string {
+	return pg.storm
+}
+
+// SetStormControlPolicy validates and sets the name of the storm control
+// policy of the policy group.
+func (pg *PolicyGroup) SetStormControlPolicy(policy string) error {
+	if err := validateName("storm control policy name", policy); err != nil {
+		return err
+	}
+	pg.storm = policy
+	return nil
+}
+
+// String returns the string representation of a policy group
+func (pg *PolicyGroup) String() string {
+	return fmt.Sprintf("%s (%s)", pg.name, pg.groupType)
+}
+
+// LeafInterfaceProfile is a leaf interface profile, selecting the
+// ports of the leaves using it to apply policy groups to.
+type LeafInterfaceProfile struct {
+	object
+	selectors []*PortSelector
+}
+
+// DN returns the distinguished name of the interface profile.
+func (ip *LeafInterfaceProfile) DN() string {
+	return leafInterfaceProfileDN(ip.name)
+}
+
+// Selectors returns the port selectors of the interface profile.
+func (ip *LeafInterfaceProfile) Selectors() []*PortSelector {
+	return ip.selectors
+}
+
+// AddSelector adds a port selector to the interface profile,
+// replacing any selector of the same name.
+func (ip *LeafInterfaceProfile) AddSelector(selector *PortSelector) {
+	for i, s := range ip.selectors {
+		if s.Name() == selector.Name() {
+			ip.selectors[i] = selector
+			return
+		}
+	}
+	ip.selectors = append(ip.selectors, selector)
+}
+
+// String returns the string representation of a leaf interface profile
+func (ip *LeafInterfaceProfile) String() string {
+	return ip.name
+}
+
+// PortSelector selects ports by blocks, applying a policy group to them.
+type PortSelector struct {
+	object
+	policyGroup string
+	blocks      []*PortBlock
+}
+
+// PolicyGroup returns the distinguished name of the policy group
+// applied to the selected ports.
+func (ps *PortSelector) PolicyGroup() string {
+	return ps.policyGroup
+}
+
+// SetPolicyGroup sets the policy group applied to the selected ports.
+func (ps *PortSelector) SetPolicyGroup(pg *PolicyGroup) error {
+	if pg == nil || pg.Name() == "" || pg.Type() == "" {
+		return fmt.Errorf("invalid policy group: policy group must have a name and type")
+	}
+	ps.policyGroup = pg.DN()
+	return nil
+}
+
+// Blocks returns the port blocks of the selector.
+func (ps *PortSelector) Blocks() []*PortBlock {
+	return ps.blocks
+}
+
+// AddBlock adds a port block to the selector,
+// replacing any block of the same name.
+func (ps *PortSelector) AddBlock(block *PortBlock) {
+	for i, b := range ps.blocks {
+		if b.Name() == block.Name() {
+			ps.blocks[i] = block
+			return
+		}
+	}
+	ps.blocks = append(ps.blocks, block)
+}
+
+// PortBlock is a range of ports, on one card or spanning several.
+type PortBlock struct {
+	name     string
+	fromCard int
+	fromPort int
+	toCard   int
+	toPort   int
+	status   string
+}
+
+// Name returns the name of the block.
+func (b *PortBlock) Name() string {
+	return b.name
+}
+
+// SetRange validates and sets the range of ports of the block,
+// from and to front panel ports such as "eth1/10" and "eth1/12".
+// Breakout ports are selected by their parent port.
+func (b *PortBlock) SetRange(from, to string) error {
+	if !portPattern.MatchString(from) || !portPattern.MatchString(to) {
+		return fmt.Errorf("invalid port range: %s-%s", from, to)
+	}
+	fromCard, fromPort := portNumbers(from)
+	toCard, toPort := portNumbers(to)
+	if fromCard > toCard || (fromCard == toCard && fromPort > toPort) {
+		return fmt.Errorf("invalid port range: %s-%s", from, to)
+	}
+	b.fromCard, b.fromPort, b.toCard, b.toPort = fromCard, fromPort, toCard, toPort
+	return nil
+}
+
+// From returns the first port of the block, such as "eth1/10".
+func (b *PortBlock) From() string {
+	return fmt.Sprintf("eth%d/%d", b.fromCard, b.fromPort)
+}
+
+// To returns the last port of the block.
+func (b *PortBlock) To() string {
+	return fmt.Sprintf("eth%d/%d", b.toCard, b.toPort)
+}
+
+// SetCreated sets the status of the block to "created,modified".
+func (b *PortBlock) SetCreated() string {
+	b.status = createdModified
+	return b.status
+}
+
+// SetDeleted sets the status of the block to "deleted".
+func (b *PortBlock) SetDeleted() string {
+	b.status = deleted
+	return b.status
+}
+
+// Status returns the status of the block.
+func (b *PortBlock) Status() string {
+	return b.status
+}
+
+// String returns the string representation of a port block
+func (b *PortBlock) String() string {
+	if b.From() == b.To() {
+		return b.From()
+	}
+	return fmt.Sprintf("%s-%s", b.From(), b.To())
+}
+
+// SwitchProfile is a leaf switch profile, selecting the leaves
+// the interface profiles associated with it apply to.
+type SwitchProfile struct {
+	object
+	selectors         []*SwitchSelector
+	interfaceProfiles []string
+}
+
+// DN returns the distinguished name of the switch profile.
+func (sp *SwitchProfile) DN() string {
+	return switchProfileDN(sp.name)
+}
+
+// Selectors returns the switch selectors of the switch profile.
+func (sp *SwitchProfile) Selectors() []*SwitchSelector {
+	return sp.selectors
+}
+
+// AddSelector adds a switch selector to the switch profile,
+// replacing any selector of the same name.
+func (sp *SwitchProfile) AddSelector(selector *SwitchSelector) {
+	for i, s := range sp.selectors {
+		if s.Name() == selector.Name() {
+			sp.selectors[i] = selector
+			return
+		}
+	}
+	sp.selectors = append(sp.selectors, selector)
+}
+
+// InterfaceProfiles returns the distinguished names of the leaf
+// interface profiles associated with the switch profile.
+func (sp *SwitchProfile) InterfaceProfiles() []string {
+	return sp.interfaceProfiles
+}
+
+// AddInterfaceProfile validates and associates a leaf interface
+// profile with the switch profile.
+func (sp *SwitchProfile) AddInterfaceProfile(profile string) error {
+	if err := validateName("interface profile name", profile); err != nil {
+		return err
+	}
+	sp.interfaceProfiles = appendName(sp.interfaceProfiles, leafInterfaceProfileDN(profile))
+	return nil
+}
+
+// String returns the string representation of a switch profile
+func (sp *SwitchProfile) String() string {
+	return sp.name
+}
+
+// SwitchSelector selects leaves by blocks of node ids.
+type SwitchSelector struct {
+	object
+	blocks []*NodeBlock
+}
+
+// Blocks returns the node blocks of the selector.
+func (ss *SwitchSelector) Blocks() []*NodeBlock {
+	return ss.blocks
+}
+
+// AddBlock adds a node block to the selector,
+// replacing any block of the same name.
+func (ss *SwitchSelector) AddBlock(block *NodeBlock) {
+	for i, b := range ss.blocks {
+		if b.Name() == block.Name() {
+			ss.blocks[i] = block
+			return
+		}
+	}
+	ss.blocks = append(ss.blocks, block)
+}
+
+// Selects reports whether the selector selects a node, by its id.
+func (ss *SwitchSelector) Selects(id string) bool {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return false
+	}
+	for _, b := range ss.blocks {
+		from, _ := strconv.Atoi(b.From())
+		to, _ := strconv.Atoi(b.To())
+		if b.Status() != deleted && n >= from && n <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeBlock is a range of node ids.
+type NodeBlock struct {
+	name   string
+	from   string
+	to     string
+	status string
+}
+
+// Name returns the name of the block.
+func (b *NodeBlock) Name() string {
+	return b.name
+}
+
+// From returns the first node id of the block.
+func (b *NodeBlock) From() string {
+	return b.from
+}
+
+// To returns the last node id of the block.
+func (b *NodeBlock) To() string {
+	return b.to
+}
+
+// SetCreated sets the status of the block to "created,modified".
+func (b *NodeBlock) SetCreated() string {
+	b.status = createdModified
+	return b.status
+}
+
+// SetDeleted sets the status of the block to "deleted".
+func (b *NodeBlock) SetDeleted() string {
+	b.status = deleted
+	return b.status
+}
+
+// Status returns the status of the block.
+func (b *NodeBlock) Status() string {
+	return b.status
+}
+
+// String returns the string representation of a node block
+func (b *NodeBlock) String() string {
+	if b.from == b.to {
+		return b.from
+	}
+	return fmt.Sprintf("%s-%s", b.from, b.to)
+}
+
+// portName returns a port as used in a name, such as "eth1-10" for "eth1/10".
+func portName(port string) string {
+	return strings.Replace(port, "/", "-", -1)
+}
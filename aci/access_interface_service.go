@@ -0,0 +1,646 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// policyGroupDN returns the distinguished name of an interface policy group.
+func policyGroupDN(groupType, name string) string {
+	if groupType == PolicyGroupAccess {
+		return fmt.Sprintf("uni/infra/funcprof/accportgrp-%s", name)
+	}
+	return fmt.Sprintf("uni/infra/funcprof/accbundle-%s", name)
+}
+
+// leafInterfaceProfileDN returns the distinguished name of a leaf interface profile.
+func leafInterfaceProfileDN(name string) string {
+	return fmt.Sprintf("uni/infra/accportprof-%s", name)
+}
+
+// switchProfileDN returns the distinguished name of a leaf switch profile.
+func switchProfileDN(name string) string {
+	return fmt.Sprintf("uni/infra/nprof-%s", name)
+}
+
+// NewPolicyGroup instantiates a valid interface policy group of the given
+// type, PolicyGroupAccess, PolicyGroupPortChannel or PolicyGroupVPC,
+// using the given AAEP.
+func (s *AccessPolicyService) NewPolicyGroup(groupType, name, aaep string) (*PolicyGroup, error) {
+	pg := &PolicyGroup{}
+
+	switch groupType {
+	case PolicyGroupAccess, PolicyGroupPortChannel, PolicyGroupVPC:
+		pg.groupType = groupType
+	default:
+		return pg, fmt.Errorf("invalid policy group type: %s", groupType)
+	}
+
+	if err := pg.SetName(name); err != nil {
+		return pg, err
+	}
+
+	if err := pg.SetAAEP(aaep); err != nil {
+		return pg, err
+	}
+
+	return pg, nil
+}
+
+// NewLeafInterfaceProfile instantiates a valid leaf interface profile.
+func (s *AccessPolicyService) NewLeafInterfaceProfile(name string) (*LeafInterfaceProfile, error) {
+	ip := &LeafInterfaceProfile{}
+	if err := ip.SetName(name); err != nil {
+		return ip, err
+	}
+	return ip, nil
+}
+
+// NewPortSelector instantiates a valid port selector applying the given
+// policy group to the ports of its blocks.
+func (s *AccessPolicyService) NewPortSelector(name string, pg *PolicyGroup) (*PortSelector, error) {
+	ps := &PortSelector{}
+
+	if err := ps.SetName(name); err != nil {
+		return ps, err
+	}
+
+	if err := ps.SetPolicyGroup(pg); err != nil {
+		return ps, err
+	}
+
+	return ps, nil
+}
+
+// NewPortBlock instantiates a valid block of the ports from and to inclusive,
+// such as "eth1/10" and "eth1/12", named after its range.
+func (s *AccessPolicyService) NewPortBlock(from, to string) (*PortBlock, error) {
+	b := &PortBlock{}
+	if err := b.SetRange(from, to); err != nil {
+		return b, err
+	}
+	b.name = portName(b.From())
+	if b.From() != b.To() {
+		b.name += "_" + portName(b.To())
+	}
+	return b, nil
+}
+
+// NewSwitchProfile instantiates a valid leaf switch profile.
+func (s *AccessPolicyService) NewSwitchProfile(name string) (*SwitchProfile, error) {
+	sp := &SwitchProfile{}
+	if err := sp.SetName(name); err != nil {
+		return sp, err
+	}
+	return sp, nil
+}
+
+// NewSwitchSelector instantiates a valid switch selector selecting the
+// given nodes, with a node block for each run of consecutive node ids.
+func (s *AccessPolicyService) NewSwitchSelector(name string, nodes ...*Node) (*SwitchSelector, error) {
+	ss := &SwitchSelector{}
+
+	if err := ss.SetName(name); err != nil {
+		return ss, err
+	}
+
+	if len(nodes) == 0 {
+		return ss, fmt.Errorf("invalid switch selector: no nodes")
+	}
+	var ids []int
+	for _, node := range nodes {
+		if node == nil || node.ID() == "" {
+			return ss, fmt.Errorf("invalid switch selector: nodes must have an id")
+		}
+		id, err := strconv.Atoi(node.ID())
+		if err != nil {
+			return ss, fmt.Errorf("invalid node id: %s", node.ID())
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for i := 0; i < len(ids); {
+		j := i
+		for j+1 < len(ids) && ids[j+1] <= ids[j]+1 {
+			j++
+		}
+		b := &NodeBlock{from: strconv.Itoa(ids[i]), to: strconv.Itoa(ids[j])}
+		b.name = b.String()
+		ss.blocks = append(ss.blocks, b)
+		i = j + 1
+	}
+
+	return ss, nil
+}
+
+func newPolicyGroupContainer(pg *PolicyGroup) AccessContainer {
+	o := &AccessObject{
+		AccessAttrs: AccessAttrs{
+			DN:     pg.DN(),
+			Name:   pg.Name(),
+			Descr:  pg.Description(),
+			Status: pg.Status(),
+		},
+	}
+	if pg.Type() != PolicyGroupAccess {
+		o.LagT = pg.Type()
+	}
+
+	// children are implicitly removed with their policy group
+	if pg.Status() != deleted {
+		o.Children = append(o.Children, AccessContainer{
+			RsAttEntP: newRelation(RelationAttrs{TDN: aaepDN(pg.AAEP())}),
+		})
+		if pg.LinkLevelPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsHIfPol: newRelation(RelationAttrs{TnFabricHIfPolName: pg.LinkLevelPolicy()}),
+			})
+		}
+		if pg.CDPPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsCdpIfPol: newRelation(RelationAttrs{TnCdpIfPolName: pg.CDPPolicy()}),
+			})
+		}
+		if pg.LLDPPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsLldpIfPol: newRelation(RelationAttrs{TnLldpIfPolName: pg.LLDPPolicy()}),
+			})
+		}
+		if pg.LACPPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsLacpPol: newRelation(RelationAttrs{TnLacpLagPolName: pg.LACPPolicy()}),
+			})
+		}
+		if pg.MCPPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsMcpIfPol: newRelation(RelationAttrs{TnMcpIfPolName: pg.MCPPolicy()}),
+			})
+		}
+		if pg.STPPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsStpIfPol: newRelation(RelationAttrs{TnStpIfPolName: pg.STPPolicy()}),
+			})
+		}
+		if pg.StormControlPolicy() != "" {
+			o.Children = append(o.Children, AccessContainer{
+				RsStormctrlIfPol: newRelation(RelationAttrs{TnStormctrlIfPolName: pg.StormControlPolicy()}),
+			})
+		}
+	}
+
+	if pg.Type() == PolicyGroupAccess {
+		return AccessContainer{AccPortGrp: o}
+	}
+	return AccessContainer{AccBndlGrp: o}
+}
+
+// CreatePolicyGroup creates an interface policy group.
+func (s *AccessPolicyService) CreatePolicyGroup(ctx context.Context, pg *PolicyGroup) (AccessResponse, error) {
+	pg.SetCreated()
+	return s.UpdatePolicyGroup(ctx, pg)
+}
+
+// DeletePolicyGroup deletes an interface policy group.
+func (s *AccessPolicyService) DeletePolicyGroup(ctx context.Context, pg *PolicyGroup) (AccessResponse, error) {
+	pg.SetDeleted()
+	return s.UpdatePolicyGroup(ctx, pg)
+}
+
+// UpdatePolicyGroup creates, modifies or deletes an interface policy group
+// according to its status.
+func (s *AccessPolicyService) UpdatePolicyGroup(ctx context.Context, pg *PolicyGroup) (AccessResponse, error) {
+	return s.postAccess(ctx, pg.DN(), newPolicyGroupContainer(pg))
+}
+
+// GetPolicyGroup retrieves an interface policy group by its type,
+// PolicyGroupAccess, PolicyGroupPortChannel or PolicyGroupVPC, and name.
+func (s *AccessPolicyService) GetPolicyGroup(ctx context.Context, groupType, name string) (*PolicyGroup, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", policyGroupDN(groupType, name))
+	ac, err := s.getAccess(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get policy group: %v", err)
+	}
+	for _, c := range ac {
+		// port-channel and vPC policy groups share a distinguished name
+		if pg := policyGroupFromResponse(c); pg != nil && pg.Type() == groupType {
+			return pg, nil
+		}
+	}
+	return nil, fmt.Errorf("get policy group: %s (%s) not found", name, groupType)
+}
+
+// ListPolicyGroups lists all interface policy groups.
+func (s *AccessPolicyService) ListPolicyGroups(ctx context.Context) ([]*PolicyGroup, error) {
+	var pgs []*PolicyGroup
+	for _, class := range []string{"infraAccPortGrp", "infraAccBndlGrp"} {
+		ac, err := s.listClass(ctx, class)
+		if err != nil {
+			return nil, fmt.Errorf("list policy groups: %v", err)
+		}
+		for _, c := range ac {
+			if pg := policyGroupFromResponse(c); pg != nil {
+				pgs = append(pgs, pg)
+			}
+		}
+	}
+	return pgs, nil
+}
+
+// policyGroupFromResponse returns the interface policy group of a
+// container, or nil if it holds none.
+func policyGroupFromResponse(c AccessContainer) *PolicyGroup {
+	pg := &PolicyGroup{groupType: PolicyGroupAccess}
+	o := c.AccPortGrp
+	switch {
+	case c.AccPortGrp != nil:
+	case c.AccBndlGrp != nil:
+		o = c.AccBndlGrp
+		pg.groupType = o.LagT
+	default:
+		return nil
+	}
+	pg.name, pg.description, pg.status = o.Name, o.Descr, o.Status
+	for _, child := range o.Children {
+		switch {
+		case child.RsAttEntP != nil:
+			pg.aaep = strings.TrimPrefix(child.RsAttEntP.TDN, "uni/infra/attentp-")
+		case child.RsHIfPol != nil:
+			pg.linkLevel = child.RsHIfPol.TnFabricHIfPolName
+		case child.RsCdpIfPol != nil:
+			pg.cdp = child.RsCdpIfPol.TnCdpIfPolName
+		case child.RsLldpIfPol != nil:
+			pg.lldp = child.RsLldpIfPol.TnLldpIfPolName
+		case child.RsLacpPol != nil:
+			pg.lacp = child.RsLacpPol.TnLacpLagPolName
+		case child.RsMcpIfPol != nil:
+			pg.mcp = child.RsMcpIfPol.TnMcpIfPolName
+		case child.RsStpIfPol != nil:
+			pg.stp = child.RsStpIfPol.TnStpIfPolName
+		case child.RsStormctrlIfPol != nil:
+			pg.storm = child.RsStormctrlIfPol.TnStormctrlIfPolName
+		}
+	}
+	return pg
+}
+
+func newLeafInterfaceProfileContainer(ip *LeafInterfaceProfile) AccessContainer {
+	o := &AccessObject{
+		AccessAttrs: AccessAttrs{
+			DN:     ip.DN(),
+			Name:   ip.Name(),
+			Descr:  ip.Description(),
+			Status: ip.Status(),
+		},
+	}
+
+	// children are implicitly removed with their interface profile
+	if ip.Status() == deleted {
+		return AccessContainer{AccPortP: o}
+	}
+
+	for _, ps := range ip.Selectors() {
+		sel := &AccessObject{
+			AccessAttrs: AccessAttrs{
+				Name:   ps.Name(),
+				Descr:  ps.Description(),
+				Type:   "range",
+				Status: ps.Status(),
+			},
+		}
+		if ps.Status() != deleted {
+			for _, b := range ps.Blocks() {
+				sel.Children = append(sel.Children, AccessContainer{
+					PortBlk: &AccessObject{
+						AccessAttrs: AccessAttrs{
+							Name:     b.Name(),
+							FromCard: strconv.Itoa(b.fromCard),
+							FromPort: strconv.Itoa(b.fromPort),
+							ToCard:   strconv.Itoa(b.toCard),
+							ToPort:   strconv.Itoa(b.toPort),
+							Status:   b.Status(),
+						},
+					},
+				})
+			}
+			sel.Children = append(sel.Children, AccessContainer{
+				RsAccBaseGrp: newRelation(RelationAttrs{TDN: ps.PolicyGroup()}),
+			})
+		}
+		o.Children = append(o.Children, AccessContainer{HPortS: sel})
+	}
+
+	return AccessContainer{AccPortP: o}
+}
+
+// CreateLeafInterfaceProfile creates a leaf interface profile.
+func (s *AccessPolicyService) CreateLeafInterfaceProfile(ctx context.Context, ip *LeafInterfaceProfile) (AccessResponse, error) {
+	ip.SetCreated()
+	return s.UpdateLeafInterfaceProfile(ctx, ip)
+}
+
+// DeleteLeafInterfaceProfile deletes a leaf interface profile.
+func (s *AccessPolicyService) DeleteLeafInterfaceProfile(ctx context.Context, ip *LeafInterfaceProfile) (AccessResponse, error) {
+	ip.SetDeleted()
+	return s.UpdateLeafInterfaceProfile(ctx, ip)
+}
+
+// UpdateLeafInterfaceProfile creates, modifies or deletes a leaf interface
+// profile according to its status, along with each of its port selectors
+// according to theirs.
+func (s *AccessPolicyService) UpdateLeafInterfaceProfile(ctx context.Context, ip *LeafInterfaceProfile) (AccessResponse, error) {
+	return s.postAccess(ctx, ip.DN(), newLeafInterfaceProfileContainer(ip))
+}
+
+// GetLeafInterfaceProfile retrieves a leaf interface profile.
+func (s *AccessPolicyService) GetLeafInterfaceProfile(ctx context.Context, name string) (*LeafInterfaceProfile, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", leafInterfaceProfileDN(name))
+	ac, err := s.getAccess(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get leaf interface profile: %v", err)
+	}
+	if len(ac) == 0 || ac[0].AccPortP == nil {
+		return nil, fmt.Errorf("get leaf interface profile: %s not found", name)
+	}
+	return leafInterfaceProfileFromResponse(ac[0].AccPortP), nil
+}
+
+// ListLeafInterfaceProfiles lists all leaf interface profiles.
+func (s *AccessPolicyService) ListLeafInterfaceProfiles(ctx context.Context) ([]*LeafInterfaceProfile, error) {
+	ac, err := s.listClass(ctx, "infraAccPortP")
+	if err != nil {
+		return nil, fmt.Errorf("list leaf interface profiles: %v", err)
+	}
+
+	var ips []*LeafInterfaceProfile
+	for _, c := range ac {
+		if c.AccPortP != nil {
+			ips = append(ips, leafInterfaceProfileFromResponse(c.AccPortP))
+		}
+	}
+	return ips, nil
+}
+
+func leafInterfaceProfileFromResponse(o *AccessObject) *LeafInterfaceProfile {
+	ip := &LeafInterfaceProfile{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+	}
+	for _, c := range o.Children {
+		if c.HPortS == nil {
+			continue
+		}
+		ps := &PortSelector{
+			object: object{
+				name:        c.HPortS.Name,
+				description: c.HPortS.Descr,
+				status:      c.HPortS.Status,
+			},
+		}
+		for _, sc := range c.HPortS.Children {
+			switch {
+			case sc.PortBlk != nil:
+				b := &PortBlock{name: sc.PortBlk.Name, status: sc.PortBlk.Status}
+				// we trust the APIC to return valid port numbers
+				b.fromCard, _ = strconv.Atoi(sc.PortBlk.FromCard)
+				b.fromPort, _ = strconv.Atoi(sc.PortBlk.FromPort)
+				b.toCard, _ = strconv.Atoi(sc.PortBlk.ToCard)
+				b.toPort, _ = strconv.Atoi(sc.PortBlk.ToPort)
+				ps.blocks = append(ps.blocks, b)
+			case sc.RsAccBaseGrp != nil:
+				ps.policyGroup = sc.RsAccBaseGrp.TDN
+			}
+		}
+		ip.selectors = append(ip.selectors, ps)
+	}
+	return ip
+}
+
+func newSwitchProfileContainer(sp *SwitchProfile) AccessContainer {
+	o := &AccessObject{
+		AccessAttrs: AccessAttrs{
+			DN:     sp.DN(),
+			Name:   sp.Name(),
+			Descr:  sp.Description(),
+			Status: sp.Status(),
+		},
+	}
+
+	// children are implicitly removed with their switch profile
+	if sp.Status() == deleted {
+		return AccessContainer{NodeP: o}
+	}
+
+	for _, ss := range sp.Selectors() {
+		sel := &AccessObject{
+			AccessAttrs: AccessAttrs{
+				Name:   ss.Name(),
+				Descr:  ss.Description(),
+				Type:   "range",
+				Status: ss.Status(),
+			},
+		}
+		if ss.Status() != deleted {
+			for _, b := range ss.Blocks() {
+				sel.Children = append(sel.Children, AccessContainer{
+					NodeBlk: &AccessObject{
+						AccessAttrs: AccessAttrs{
+							Name:     b.Name(),
+							FromNode: b.From(),
+							ToNode:   b.To(),
+							Status:   b.Status(),
+						},
+					},
+				})
+			}
+		}
+		o.Children = append(o.Children, AccessContainer{LeafS: sel})
+	}
+	for _, ip := range sp.InterfaceProfiles() {
+		o.Children = append(o.Children, AccessContainer{
+			RsAccPortP: newRelation(RelationAttrs{TDN: ip}),
+		})
+	}
+
+	return AccessContainer{NodeP: o}
+}
+
+// CreateSwitchProfile creates a leaf switch profile.
+func (s *AccessPolicyService) CreateSwitchProfile(ctx context.Context, sp *SwitchProfile) (AccessResponse, error) {
+	sp.SetCreated()
+	return s.UpdateSwitchProfile(ctx, sp)
+}
+
+// DeleteSwitchProfile deletes a leaf switch profile.
+func (s *AccessPolicyService) DeleteSwitchProfile(ctx context.Context, sp *SwitchProfile) (AccessResponse, error) {
+	sp.SetDeleted()
+	return s.UpdateSwitchProfile(ctx, sp)
+}
+
+// UpdateSwitchProfile creates, modifies or deletes a leaf switch profile
+// according to its status, along with each of its switch selectors
+// according to theirs.
+func (s *AccessPolicyService) UpdateSwitchProfile(ctx context.Context, sp *SwitchProfile) (AccessResponse, error) {
+	return s.postAccess(ctx, sp.DN(), newSwitchProfileContainer(sp))
+}
+
+// GetSwitchProfile retrieves a leaf switch profile.
+func (s *AccessPolicyService) GetSwitchProfile(ctx context.Context, name string) (*SwitchProfile, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", switchProfileDN(name))
+	ac, err := s.getAccess(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get switch profile: %v", err)
+	}
+	if len(ac) == 0 || ac[0].NodeP == nil {
+		return nil, fmt.Errorf("get switch profile: %s not found", name)
+	}
+	return switchProfileFromResponse(ac[0].NodeP), nil
+}
+
+// ListSwitchProfiles lists all leaf switch profiles.
+func (s *AccessPolicyService) ListSwitchProfiles(ctx context.Context) ([]*SwitchProfile, error) {
+	ac, err := s.listClass(ctx, "infraNodeP")
+	if err != nil {
+		return nil, fmt.Errorf("list switch profiles: %v", err)
+	}
+
+	var sps []*SwitchProfile
+	for _, c := range ac {
+		if c.NodeP != nil {
+			sps = append(sps, switchProfileFromResponse(c.NodeP))
+		}
+	}
+	return sps, nil
+}
+
+func switchProfileFromResponse(o *AccessObject) *SwitchProfile {
+	sp := &SwitchProfile{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+	}
+	for _, c := range o.Children {
+		switch {
+		case c.LeafS != nil:
+			ss := &SwitchSelector{
+				object: object{
+					name:        c.LeafS.Name,
+					description: c.LeafS.Descr,
+					status:      c.LeafS.Status,
+				},
+			}
+			for _, sc := range c.LeafS.Children {
+				if sc.NodeBlk != nil {
+					ss.blocks = append(ss.blocks, &NodeBlock{
+						name:   sc.NodeBlk.Name,
+						from:   sc.NodeBlk.FromNode,
+						to:     sc.NodeBlk.ToNode,
+						status: sc.NodeBlk.Status,
+					})
+				}
+			}
+			sp.selectors = append(sp.selectors, ss)
+		case c.RsAccPortP != nil:
+			sp.interfaceProfiles = appendName(sp.interfaceProfiles, c.RsAccPortP.TDN)
+		}
+	}
+	return sp
+}
+
+// ConfigurePort configures an access port of a leaf, such as "eth1/10",
+// as an individual access port in the given AAEP, generating the chain
+// of access policies that requires:
+//
+//	policy group "access-<aaep>", using the AAEP
+//	leaf interface profile "leaf-<id>", with a port selector for the port
+//	leaf switch profile "leaf-<id>", selecting the leaf and associated
+//	with the interface profile
+//
+// Only the objects of the chain are created or modified, leaving other
+// port selectors of the profiles alone, so ConfigurePort can be called
+// for each port of a leaf, and called again for a port already configured.
+// The leaf must be a registered fabric member, and the AAEP must exist.
+func (s *AccessPolicyService) ConfigurePort(ctx context.Context, node *Node, port, aaep string) error {
+	if !portPattern.MatchString(port) {
+		return fmt.Errorf("configure port: invalid port: %s", port)
+	}
+	if node == nil || node.ID() == "" {
+		return fmt.Errorf("configure port: node must have an id")
+	}
+
+	nodes, err := s.client.FabricMembership.List(ctx)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	var leaf *Node
+	for _, n := range nodes {
+		if n.ID() == node.ID() {
+			leaf = n
+		}
+	}
+	if leaf == nil {
+		return fmt.Errorf("configure port: node %s is not registered", node.ID())
+	}
+	if leaf.Role() != "leaf" {
+		return fmt.Errorf("configure port: node %s is a %s, not a leaf", node.ID(), leaf.Role())
+	}
+
+	if _, err := s.GetAAEP(ctx, aaep); err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+
+	name := fmt.Sprintf("leaf-%s", node.ID())
+
+	pg, err := s.NewPolicyGroup(PolicyGroupAccess, "access-"+aaep, aaep)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	ps, err := s.NewPortSelector(portName(port), pg)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	block, err := s.NewPortBlock(port, port)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	ps.AddBlock(block)
+	ip, err := s.NewLeafInterfaceProfile(name)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	ip.AddSelector(ps)
+
+	ss, err := s.NewSwitchSelector(name, node)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	sp, err := s.NewSwitchProfile(name)
+	if err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	sp.AddSelector(ss)
+	if err := sp.AddInterfaceProfile(ip.Name()); err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+
+	// the chain is created from the AAEP outwards, so that nothing
+	// refers to an object that does not yet exist
+	if _, err := s.CreatePolicyGroup(ctx, pg); err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	if _, err := s.CreateLeafInterfaceProfile(ctx, ip); err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	if _, err := s.CreateSwitchProfile(ctx, sp); err != nil {
+		return fmt.Errorf("configure port: %v", err)
+	}
+	return nil
+}
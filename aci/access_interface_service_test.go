@@ -0,0 +1,118 @@
+package aci
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const (
+	accessPolicyGroupPath = "/api/node/mo/uni/infra/funcprof/accportgrp-access-servers.json"
+	leafInterfacePath     = "/api/node/mo/uni/infra/accportprof-leaf-101.json"
+	switchProfilePath     = "/api/node/mo/uni/infra/nprof-leaf-101.json"
+)
+
+// configurePortFixtures register leaf 101 and spine 201, and the AAEP "servers".
+var configurePortFixtures = map[string]string{
+	"/api/node/class/fabricNode.json": `{"imdata":[
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-101","id":"101","name":"leaf-101","role":"leaf"}}},
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-201","id":"201","name":"spine-201","role":"spine"}}}
+	]}`,
+	"/api/node/mo/uni/infra/attentp-servers.json": `{"imdata":[{"infraAttEntityP":{"attributes":{"name":"servers"}}}]}`,
+}
+
+func TestNewSwitchSelector(t *testing.T) {
+	s := &AccessPolicyService{}
+	var nodes []*Node
+	for _, id := range []string{"103", "101", "102", "105"} {
+		nodes = append(nodes, newTestNode(t, "leaf-"+id, id, "1", "FDO"+id, "leaf"))
+	}
+	ss, err := s.NewSwitchSelector("leaves", nodes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blocks []string
+	for _, b := range ss.Blocks() {
+		blocks = append(blocks, b.String())
+	}
+	if got, want := strings.Join(blocks, ","), "101-103,105"; got != want {
+		t.Errorf("got blocks %s, want %s", got, want)
+	}
+
+	if _, err := s.NewSwitchSelector("leaves"); err == nil {
+		t.Error("expected an error for no nodes")
+	}
+	if _, err := s.NewSwitchSelector("leaves", nil); err == nil {
+		t.Error("expected an error for a nil node")
+	}
+}
+
+func TestConfigurePort(t *testing.T) {
+	f, c := newFakeAPIC(t, configurePortFixtures)
+	leaf := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+
+	if err := c.AccessPolicy.ConfigurePort(context.Background(), leaf, "eth1/10", "servers"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.postCount(); got != 3 {
+		t.Fatalf("got %d posts, want 3", got)
+	}
+	jsonEqual(t, f.posted(accessPolicyGroupPath)[0], `{"infraAccPortGrp":{"attributes":{"dn":"uni/infra/funcprof/accportgrp-access-servers","name":"access-servers","status":"created,modified"},"children":[
+		{"infraRsAttEntP":{"attributes":{"tDn":"uni/infra/attentp-servers"}}}
+	]}}`)
+	jsonEqual(t, f.posted(leafInterfacePath)[0], `{"infraAccPortP":{"attributes":{"dn":"uni/infra/accportprof-leaf-101","name":"leaf-101","status":"created,modified"},"children":[
+		{"infraHPortS":{"attributes":{"name":"eth1-10","type":"range"},"children":[
+			{"infraPortBlk":{"attributes":{"name":"eth1-10","fromCard":"1","fromPort":"10","toCard":"1","toPort":"10"}}},
+			{"infraRsAccBaseGrp":{"attributes":{"tDn":"uni/infra/funcprof/accportgrp-access-servers"}}}
+		]}}
+	]}}`)
+	jsonEqual(t, f.posted(switchProfilePath)[0], `{"infraNodeP":{"attributes":{"dn":"uni/infra/nprof-leaf-101","name":"leaf-101","status":"created,modified"},"children":[
+		{"infraLeafS":{"attributes":{"name":"leaf-101","type":"range"},"children":[
+			{"infraNodeBlk":{"attributes":{"name":"101","from_":"101","to_":"101"}}}
+		]}},
+		{"infraRsAccPortP":{"attributes":{"tDn":"uni/infra/accportprof-leaf-101"}}}
+	]}}`)
+
+	// another port only adds its own selector, leaving the first alone
+	if err := c.AccessPolicy.ConfigurePort(context.Background(), leaf, "eth1/11", "servers"); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted(leafInterfacePath)
+	if len(posts) != 2 {
+		t.Fatalf("got %d interface profile posts, want 2", len(posts))
+	}
+	jsonEqual(t, posts[1], `{"infraAccPortP":{"attributes":{"dn":"uni/infra/accportprof-leaf-101","name":"leaf-101","status":"created,modified"},"children":[
+		{"infraHPortS":{"attributes":{"name":"eth1-11","type":"range"},"children":[
+			{"infraPortBlk":{"attributes":{"name":"eth1-11","fromCard":"1","fromPort":"11","toCard":"1","toPort":"11"}}},
+			{"infraRsAccBaseGrp":{"attributes":{"tDn":"uni/infra/funcprof/accportgrp-access-servers"}}}
+		]}}
+	]}}`)
+	if got := f.postCount(); got != 6 {
+		t.Errorf("got %d posts, want 6", got)
+	}
+}
+
+func TestConfigurePortInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		node *Node
+		port string
+		aaep string
+		want string
+	}{
+		{"invalid port", newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf"), "1/10", "servers", "invalid port: 1/10"},
+		{"unregistered", newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf"), "eth1/10", "servers", "node 102 is not registered"},
+		{"spine", newTestNode(t, "spine-201", "201", "1", "FDO3", "spine"), "eth1/10", "servers", "node 201 is a spine, not a leaf"},
+		{"missing aaep", newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf"), "eth1/10", "routers", "routers not found"},
+	}
+	for _, tt := range tests {
+		f, c := newFakeAPIC(t, configurePortFixtures)
+		err := c.AccessPolicy.ConfigurePort(context.Background(), tt.node, tt.port, tt.aaep)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %s", tt.name, err, tt.want)
+		}
+		if f.postCount() != 0 {
+			t.Errorf("%s: posted despite the error", tt.name)
+		}
+	}
+}
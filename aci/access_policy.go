@@ -0,0 +1,287 @@
+package aci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VLANPool is a pool of VLANs, allocated to EPGs statically or
+// dynamically by the domains using it.
+type VLANPool struct {
+	object
+	allocation string
+	blocks     []*EncapBlock
+}
+
+// Allocation returns the allocation mode of the pool.
+func (p *VLANPool) Allocation() string {
+	return p.allocation
+}
+
+// SetAllocation sets the allocation mode of the pool.
+// Can only be "static" or "dynamic"
+func (p *VLANPool) SetAllocation(allocation string) error {
+	if allocation != "static" && allocation != "dynamic" {
+		return fmt.Errorf("invalid allocation mode: %s", allocation)
+	}
+	p.allocation = allocation
+	return nil
+}
+
+// DN returns the distinguished name of the pool.
+func (p *VLANPool) DN() string {
+	return vlanPoolDN(p.name, p.allocation)
+}
+
+// Blocks returns the encap blocks of the pool.
+func (p *VLANPool) Blocks() []*EncapBlock {
+	return p.blocks
+}
+
+// AddBlock adds an encap block to the pool,
+// replacing any block of the same range.
+func (p *VLANPool) AddBlock(block *EncapBlock) {
+	for i, b := range p.blocks {
+		if b.From() == block.From() && b.To() == block.To() {
+			p.blocks[i] = block
+			return
+		}
+	}
+	p.blocks = append(p.blocks, block)
+}
+
+// Contains reports whether a VLAN is in any block of the pool.
+func (p *VLANPool) Contains(vlan int) bool {
+	return p.Block(vlan) != nil
+}
+
+// Block returns the block containing a VLAN, or nil if there is none.
+func (p *VLANPool) Block(vlan int) *EncapBlock {
+	for _, b := range p.blocks {
+		if b.Status() != deleted && b.Contains(vlan) {
+			return b
+		}
+	}
+	return nil
+}
+
+// String returns the string representation of a VLAN pool
+func (p *VLANPool) String() string {
+	var blocks []string
+	for _, b := range p.blocks {
+		blocks = append(blocks, b.String())
+	}
+	return fmt.Sprintf("%s (%s) %s", p.name, p.allocation, strings.Join(blocks, ","))
+}
+
+// EncapBlock is a range of VLANs of a VLAN pool.
+type EncapBlock struct {
+	from       int
+	to         int
+	allocation string
+	status     string
+}
+
+// From returns the first VLAN of the block.
+func (b *EncapBlock) From() int {
+	return b.from
+}
+
+// To returns the last VLAN of the block.
+func (b *EncapBlock) To() int {
+	return b.to
+}
+
+// SetRange validates and sets the range of VLANs of the block.
+//
+// VLANs must be numbers between 1 and 4094 inclusive,
+// and the range must not run backwards.
+func (b *EncapBlock) SetRange(from, to int) error {
+	if from < 1 || from > 4094 || to < 1 || to > 4094 || from > to {
+		return fmt.Errorf("invalid vlan range: %d-%d", from, to)
+	}
+	b.from, b.to = from, to
+	return nil
+}
+
+// Allocation returns the allocation mode of the block.
+func (b *EncapBlock) Allocation() string {
+	return b.allocation
+}
+
+// SetAllocation sets the allocation mode of the block.
+// Can only be "inherit" (that of its pool), "static" or "dynamic"
+func (b *EncapBlock) SetAllocation(allocation string) error {
+	if allocation != "inherit" && allocation != "static" && allocation != "dynamic" {
+		return fmt.Errorf("invalid allocation mode: %s", allocation)
+	}
+	b.allocation = allocation
+	return nil
+}
+
+// Contains reports whether a VLAN is in the block.
+func (b *EncapBlock) Contains(vlan int) bool {
+	return vlan >= b.from && vlan <= b.to
+}
+
+// Overlaps reports whether any VLAN is in both blocks.
+func (b *EncapBlock) Overlaps(o *EncapBlock) bool {
+	return b.from <= o.to && o.from <= b.to
+}
+
+// SetCreated sets the status of the block to "created,modified".
+func (b *EncapBlock) SetCreated() string {
+	b.status = createdModified
+	return b.status
+}
+
+// SetDeleted sets the status of the block to "deleted".
+func (b *EncapBlock) SetDeleted() string {
+	b.status = deleted
+	return b.status
+}
+
+// Status returns the status of the block.
+func (b *EncapBlock) Status() string {
+	return b.status
+}
+
+// String returns the string representation of an encap block
+func (b *EncapBlock) String() string {
+	if b.from == b.to {
+		return fmt.Sprintf("%d", b.from)
+	}
+	return fmt.Sprintf("%d-%d", b.from, b.to)
+}
+
+// VLANOverlap is a range of VLANs in more than one VLAN pool.
+type VLANOverlap struct {
+	Pool       string // "<name> (<allocation>)"
+	Block      *EncapBlock
+	OtherPool  string
+	OtherBlock *EncapBlock
+}
+
+// String returns the string representation of an overlap
+func (o VLANOverlap) String() string {
+	return fmt.Sprintf("%s %v overlaps %s %v", o.Pool, o.Block, o.OtherPool, o.OtherBlock)
+}
+
+// VLANOverlaps returns every range of VLANs in more than one of the pools,
+// ignoring blocks being deleted.
+func VLANOverlaps(pools ...*VLANPool) []VLANOverlap {
+	var overlaps []VLANOverlap
+	for i, p := range pools {
+		for _, o := range pools[i+1:] {
+			for _, b := range p.Blocks() {
+				for _, ob := range o.Blocks() {
+					if b.Status() == deleted || ob.Status() == deleted || !b.Overlaps(ob) {
+						continue
+					}
+					overlaps = append(overlaps, VLANOverlap{
+						Pool:       fmt.Sprintf("%s (%s)", p.Name(), p.Allocation()),
+						Block:      b,
+						OtherPool:  fmt.Sprintf("%s (%s)", o.Name(), o.Allocation()),
+						OtherBlock: ob,
+					})
+				}
+			}
+		}
+	}
+	return overlaps
+}
+
+// Types of domain.
+const (
+	DomainTypePhysical = "phys"
+	DomainTypeL3       = "l3dom"
+)
+
+// Domain is a physical or L3 domain, the VLANs available to the EPGs
+// and L3Outs associated with it, and the scope of their deployment.
+type Domain struct {
+	object
+	domainType     string
+	pool           string
+	poolAllocation string
+}
+
+// Type returns the type of the domain, one of DomainTypePhysical
+// or DomainTypeL3.
+func (d *Domain) Type() string {
+	return d.domainType
+}
+
+// DN returns the distinguished name of the domain.
+func (d *Domain) DN() string {
+	return domainDN(d.domainType, d.name)
+}
+
+// VLANPool returns the name and allocation mode of the VLAN pool
+// of the domain.
+func (d *Domain) VLANPool() (string, string) {
+	return d.pool, d.poolAllocation
+}
+
+// SetVLANPool sets the VLAN pool of the domain.
+func (d *Domain) SetVLANPool(pool *VLANPool) error {
+	if pool == nil || pool.Name() == "" || pool.Allocation() == "" {
+		return fmt.Errorf("invalid vlan pool: pool must have a name and allocation mode")
+	}
+	d.pool, d.poolAllocation = pool.Name(), pool.Allocation()
+	return nil
+}
+
+// String returns the string representation of a domain
+func (d *Domain) String() string {
+	return d.DN()
+}
+
+// AAEP is an attachable access entity profile, grouping the
+// domains whose VLANs can be deployed on the interfaces using it.
+type AAEP struct {
+	object
+	domains []string
+}
+
+// DN returns the distinguished name of the AAEP.
+func (a *AAEP) DN() string {
+	return aaepDN(a.name)
+}
+
+// Domains returns the distinguished names of the domains of the AAEP.
+func (a *AAEP) Domains() []string {
+	return a.domains
+}
+
+// AddPhysicalDomain validates and adds a physical domain to the AAEP.
+func (a *AAEP) AddPhysicalDomain(domain string) error {
+	if err := validateName("physical domain name", domain); err != nil {
+		return err
+	}
+	a.domains = appendName(a.domains, domainDN(DomainTypePhysical, domain))
+	return nil
+}
+
+// AddL3Domain validates and adds an L3 domain to the AAEP.
+func (a *AAEP) AddL3Domain(domain string) error {
+	if err := validateName("l3 domain name", domain); err != nil {
+		return err
+	}
+	a.domains = appendName(a.domains, domainDN(DomainTypeL3, domain))
+	return nil
+}
+
+// AddVMMDomain validates and adds a VMware VMM domain to the AAEP.
+func (a *AAEP) AddVMMDomain(domain string) error {
+	if err := validateName("vmm domain name", domain); err != nil {
+		return err
+	}
+	a.domains = appendName(a.domains, vmmDomainDN(domain))
+	return nil
+}
+
+// String returns the string representation of an AAEP
+func (a *AAEP) String() string {
+	return a.name
+}
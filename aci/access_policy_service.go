@@ -0,0 +1,789 @@
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AccessContainer is a container for any object of the access policies.
+// Only one of its fields is set.
+type AccessContainer struct {
+	VlanInstP        *AccessObject `json:"fvnsVlanInstP,omitempty"`
+	EncapBlk         *AccessObject `json:"fvnsEncapBlk,omitempty"`
+	PhysDomP         *AccessObject `json:"physDomP,omitempty"`
+	L3extDomP        *AccessObject `json:"l3extDomP,omitempty"`
+	VmmDomP          *AccessObject `json:"vmmDomP,omitempty"`
+	RsVlanNs         *Relation     `json:"infraRsVlanNs,omitempty"`
+	AttEntityP       *AccessObject `json:"infraAttEntityP,omitempty"`
+	RsDomP           *Relation     `json:"infraRsDomP,omitempty"`
+	NodeP            *AccessObject `json:"infraNodeP,omitempty"`
+	LeafS            *AccessObject `json:"infraLeafS,omitempty"`
+	NodeBlk          *AccessObject `json:"infraNodeBlk,omitempty"`
+	RsAccPortP       *Relation     `json:"infraRsAccPortP,omitempty"`
+	AccPortP         *AccessObject `json:"infraAccPortP,omitempty"`
+	HPortS           *AccessObject `json:"infraHPortS,omitempty"`
+	PortBlk          *AccessObject `json:"infraPortBlk,omitempty"`
+	RsAccBaseGrp     *Relation     `json:"infraRsAccBaseGrp,omitempty"`
+	AccPortGrp       *AccessObject `json:"infraAccPortGrp,omitempty"`
+	AccBndlGrp       *AccessObject `json:"infraAccBndlGrp,omitempty"`
+	RsAttEntP        *Relation     `json:"infraRsAttEntP,omitempty"`
+	RsHIfPol         *Relation     `json:"infraRsHIfPol,omitempty"`
+	RsCdpIfPol       *Relation     `json:"infraRsCdpIfPol,omitempty"`
+	RsLldpIfPol      *Relation     `json:"infraRsLldpIfPol,omitempty"`
+	RsLacpPol        *Relation     `json:"infraRsLacpPol,omitempty"`
+	RsMcpIfPol       *Relation     `json:"infraRsMcpIfPol,omitempty"`
+	RsStpIfPol       *Relation     `json:"infraRsStpIfPol,omitempty"`
+	RsStormctrlIfPol *Relation     `json:"infraRsStormctrlIfPol,omitempty"`
+	HIfPol           *AccessObject `json:"fabricHIfPol,omitempty"`
+	LagPol           *AccessObject `json:"lacpLagPol,omitempty"`
+	CdpIfPol         *AccessObject `json:"cdpIfPol,omitempty"`
+	LldpIfPol        *AccessObject `json:"lldpIfPol,omitempty"`
+	McpIfPol         *AccessObject `json:"mcpIfPol,omitempty"`
+	StpIfPol         *STPObject    `json:"stpIfPol,omitempty"`
+	StormctrlIfPol   *AccessObject `json:"stormctrlIfPol,omitempty"`
+}
+
+// AccessObject is any object of the access policies
+type AccessObject struct {
+	AccessAttrs `json:"attributes"`
+	Children    []AccessContainer `json:"children,omitempty"`
+}
+
+// STPObject is a spanning tree interface policy. Its controls are always
+// sent, as an empty ctrl must be sent to clear those set on the APIC.
+type STPObject struct {
+	AccessObject
+}
+
+// MarshalJSON encodes the policy with its ctrl attribute, even when empty.
+func (o STPObject) MarshalJSON() ([]byte, error) {
+	type attrs struct {
+		AccessAttrs
+		Ctrl string `json:"ctrl"`
+	}
+	return json.Marshal(struct {
+		Attributes attrs             `json:"attributes"`
+		Children   []AccessContainer `json:"children,omitempty"`
+	}{attrs{o.AccessAttrs, o.Ctrl}, o.Children})
+}
+
+// AccessAttrs contains the attributes of the access policy objects
+type AccessAttrs struct {
+	AdminRxSt       string `json:"adminRxSt,omitempty"`
+	AdminSt         string `json:"adminSt,omitempty"`
+	AdminTxSt       string `json:"adminTxSt,omitempty"`
+	AllocMode       string `json:"allocMode,omitempty"`
+	AutoNeg         string `json:"autoNeg,omitempty"`
+	BurstRate       string `json:"burstRate,omitempty"`
+	Ctrl            string `json:"ctrl,omitempty"`
+	Descr           string `json:"descr,omitempty"`
+	DN              string `json:"dn,omitempty"`
+	FecMode         string `json:"fecMode,omitempty"`
+	From            string `json:"from,omitempty"`
+	FromCard        string `json:"fromCard,omitempty"`
+	FromNode        string `json:"from_,omitempty"`
+	FromPort        string `json:"fromPort,omitempty"`
+	LagT            string `json:"lagT,omitempty"`
+	LinkDebounce    string `json:"linkDebounce,omitempty"`
+	MaxLinks        string `json:"maxLinks,omitempty"`
+	MinLinks        string `json:"minLinks,omitempty"`
+	Mode            string `json:"mode,omitempty"`
+	Name            string `json:"name,omitempty"`
+	RN              string `json:"rn,omitempty"`
+	Rate            string `json:"rate,omitempty"`
+	Speed           string `json:"speed,omitempty"`
+	Status          string `json:"status,omitempty"`
+	StormCtrlAction string `json:"stormCtrlAction,omitempty"`
+	To              string `json:"to,omitempty"`
+	ToCard          string `json:"toCard,omitempty"`
+	ToNode          string `json:"to_,omitempty"`
+	ToPort          string `json:"toPort,omitempty"`
+	Type            string `json:"type,omitempty"`
+}
+
+// AccessResponse contains the response for access policy requests
+type AccessResponse struct {
+	TotalCount string            `json:"totalCount"`
+	Imdata     []AccessContainer `json:"imdata"`
+}
+
+// AccessPolicyService handles communication with the access policy
+// related methods of the APIC API.
+type AccessPolicyService service
+
+// vlanPoolDN returns the distinguished name of a VLAN pool.
+func vlanPoolDN(name, allocation string) string {
+	return fmt.Sprintf("uni/infra/vlanns-[%s]-%s", name, allocation)
+}
+
+// domainDN returns the distinguished name of a physical or L3 domain.
+func domainDN(domainType, name string) string {
+	return fmt.Sprintf("uni/%s-%s", domainType, name)
+}
+
+// aaepDN returns the distinguished name of an AAEP.
+func aaepDN(name string) string {
+	return fmt.Sprintf("uni/infra/attentp-%s", name)
+}
+
+// NewVLANPool instantiates a valid VLAN pool with the given allocation mode.
+func (s *AccessPolicyService) NewVLANPool(name, allocation string) (*VLANPool, error) {
+	pool := &VLANPool{}
+
+	if err := pool.SetName(name); err != nil {
+		return pool, err
+	}
+
+	if err := pool.SetAllocation(allocation); err != nil {
+		return pool, err
+	}
+
+	return pool, nil
+}
+
+// NewEncapBlock instantiates a valid encap block of the VLANs from and to
+// inclusive, allocated as its pool's are.
+func (s *AccessPolicyService) NewEncapBlock(from, to int) (*EncapBlock, error) {
+	block := &EncapBlock{allocation: "inherit"}
+	if err := block.SetRange(from, to); err != nil {
+		return block, err
+	}
+	return block, nil
+}
+
+// NewPhysicalDomain instantiates a valid physical domain using the given VLAN pool.
+func (s *AccessPolicyService) NewPhysicalDomain(name string, pool *VLANPool) (*Domain, error) {
+	return newDomain(DomainTypePhysical, name, pool)
+}
+
+// NewL3Domain instantiates a valid L3 domain using the given VLAN pool.
+func (s *AccessPolicyService) NewL3Domain(name string, pool *VLANPool) (*Domain, error) {
+	return newDomain(DomainTypeL3, name, pool)
+}
+
+func newDomain(domainType, name string, pool *VLANPool) (*Domain, error) {
+	domain := &Domain{domainType: domainType}
+
+	if err := domain.SetName(name); err != nil {
+		return domain, err
+	}
+
+	if err := domain.SetVLANPool(pool); err != nil {
+		return domain, err
+	}
+
+	return domain, nil
+}
+
+// NewAAEP instantiates a valid AAEP.
+func (s *AccessPolicyService) NewAAEP(name string) (*AAEP, error) {
+	aaep := &AAEP{}
+	if err := aaep.SetName(name); err != nil {
+		return aaep, err
+	}
+	return aaep, nil
+}
+
+// getAccess retrieves the access policy objects of the given path.
+func (s *AccessPolicyService) getAccess(ctx context.Context, path string) ([]AccessContainer, error) {
+	var ar AccessResponse
+	if err := s.client.get(ctx, path, &ar); err != nil {
+		return nil, err
+	}
+	return ar.Imdata, nil
+}
+
+// listClass retrieves every access policy object of a class,
+// with its children.
+func (s *AccessPolicyService) listClass(ctx context.Context, class string) ([]AccessContainer, error) {
+	return s.getAccess(ctx, fmt.Sprintf("api/node/class/%s.json?rsp-subtree=full", class))
+}
+
+// postAccess posts an access policy object to its distinguished name.
+func (s *AccessPolicyService) postAccess(ctx context.Context, dn string, payload AccessContainer) (AccessResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", dn)
+	var ar AccessResponse
+	err := s.client.post(ctx, path, payload, &ar)
+	return ar, err
+}
+
+func newVLANPoolContainer(pool *VLANPool) AccessContainer {
+	o := &AccessObject{
+		AccessAttrs: AccessAttrs{
+			DN:        pool.DN(),
+			Name:      pool.Name(),
+			Descr:     pool.Description(),
+			AllocMode: pool.Allocation(),
+			Status:    pool.Status(),
+		},
+	}
+
+	// children are implicitly removed with their pool
+	if pool.Status() == deleted {
+		return AccessContainer{VlanInstP: o}
+	}
+
+	for _, b := range pool.Blocks() {
+		o.Children = append(o.Children, AccessContainer{
+			EncapBlk: &AccessObject{
+				AccessAttrs: AccessAttrs{
+					From:      fmt.Sprintf("vlan-%d", b.From()),
+					To:        fmt.Sprintf("vlan-%d", b.To()),
+					AllocMode: b.Allocation(),
+					Status:    b.Status(),
+				},
+			},
+		})
+	}
+
+	return AccessContainer{VlanInstP: o}
+}
+
+// CreateVLANPool creates a VLAN pool.
+func (s *AccessPolicyService) CreateVLANPool(ctx context.Context, pool *VLANPool) (AccessResponse, error) {
+	pool.SetCreated()
+	return s.UpdateVLANPool(ctx, pool)
+}
+
+// DeleteVLANPool deletes a VLAN pool.
+func (s *AccessPolicyService) DeleteVLANPool(ctx context.Context, pool *VLANPool) (AccessResponse, error) {
+	pool.SetDeleted()
+	return s.UpdateVLANPool(ctx, pool)
+}
+
+// UpdateVLANPool creates, modifies or deletes a VLAN pool according to
+// its status, along with each of its blocks according to theirs.
+func (s *AccessPolicyService) UpdateVLANPool(ctx context.Context, pool *VLANPool) (AccessResponse, error) {
+	return s.postAccess(ctx, pool.DN(), newVLANPoolContainer(pool))
+}
+
+// GetVLANPool retrieves a VLAN pool by its name and allocation mode.
+func (s *AccessPolicyService) GetVLANPool(ctx context.Context, name, allocation string) (*VLANPool, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", vlanPoolDN(name, allocation))
+	ac, err := s.getAccess(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get vlan pool: %v", err)
+	}
+	if len(ac) == 0 || ac[0].VlanInstP == nil {
+		return nil, fmt.Errorf("get vlan pool: %s (%s) not found", name, allocation)
+	}
+	return vlanPoolFromResponse(ac[0].VlanInstP), nil
+}
+
+// ListVLANPools lists all VLAN pools.
+func (s *AccessPolicyService) ListVLANPools(ctx context.Context) ([]*VLANPool, error) {
+	ac, err := s.listClass(ctx, "fvnsVlanInstP")
+	if err != nil {
+		return nil, fmt.Errorf("list vlan pools: %v", err)
+	}
+
+	var pools []*VLANPool
+	for _, c := range ac {
+		if c.VlanInstP != nil {
+			pools = append(pools, vlanPoolFromResponse(c.VlanInstP))
+		}
+	}
+	return pools, nil
+}
+
+func vlanPoolFromResponse(o *AccessObject) *VLANPool {
+	pool := &VLANPool{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+		allocation: o.AllocMode,
+	}
+	for _, c := range o.Children {
+		if c.EncapBlk == nil {
+			continue
+		}
+		// we trust the APIC to return valid encaps
+		from, _ := strconv.Atoi(strings.TrimPrefix(c.EncapBlk.From, "vlan-"))
+		to, _ := strconv.Atoi(strings.TrimPrefix(c.EncapBlk.To, "vlan-"))
+		pool.blocks = append(pool.blocks, &EncapBlock{
+			from:       from,
+			to:         to,
+			allocation: c.EncapBlk.AllocMode,
+			status:     c.EncapBlk.Status,
+		})
+	}
+	return pool
+}
+
+// Overlaps returns every range of VLANs in more than one VLAN pool, those
+// on the APIC along with the given pools, which take the place of any pool
+// of the same name and allocation mode on the APIC. Overlapping pools can
+// be intended, but usually are mistakes, such as when a VLAN of one pool
+// is deployed with an EPG in the domain of another on the same interface.
+func (s *AccessPolicyService) Overlaps(ctx context.Context, pools ...*VLANPool) ([]VLANOverlap, error) {
+	live, err := s.ListVLANPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("overlaps: %v", err)
+	}
+
+	desired := make(map[string]bool)
+	for _, p := range pools {
+		desired[p.DN()] = true
+	}
+	var all []*VLANPool
+	for _, p := range live {
+		if !desired[p.DN()] {
+			all = append(all, p)
+		}
+	}
+	for _, p := range pools {
+		if p.Status() != deleted {
+			all = append(all, p)
+		}
+	}
+
+	return VLANOverlaps(all...), nil
+}
+
+func newDomainContainer(domain *Domain) AccessContainer {
+	o := &AccessObject{
+		AccessAttrs: AccessAttrs{
+			DN:     domain.DN(),
+			Name:   domain.Name(),
+			Descr:  domain.Description(),
+			Status: domain.Status(),
+		},
+	}
+
+	// children are implicitly removed with their domain
+	if domain.Status() != deleted {
+		pool, allocation := domain.VLANPool()
+		o.Children = append(o.Children, AccessContainer{
+			RsVlanNs: newRelation(RelationAttrs{TDN: vlanPoolDN(pool, allocation)}),
+		})
+	}
+
+	if domain.Type() == DomainTypeL3 {
+		return AccessContainer{L3extDomP: o}
+	}
+	return AccessContainer{PhysDomP: o}
+}
+
+// CreateDomain creates a physical or L3 domain.
+func (s *AccessPolicyService) CreateDomain(ctx context.Context, domain *Domain) (AccessResponse, error) {
+	domain.SetCreated()
+	return s.UpdateDomain(ctx, domain)
+}
+
+// DeleteDomain deletes a physical or L3 domain.
+func (s *AccessPolicyService) DeleteDomain(ctx context.Context, domain *Domain) (AccessResponse, error) {
+	domain.SetDeleted()
+	return s.UpdateDomain(ctx, domain)
+}
+
+// UpdateDomain creates, modifies or deletes a physical or L3 domain
+// according to its status.
+func (s *AccessPolicyService) UpdateDomain(ctx context.Context, domain *Domain) (AccessResponse, error) {
+	return s.postAccess(ctx, domain.DN(), newDomainContainer(domain))
+}
+
+// GetDomain retrieves a physical or L3 domain by its type,
+// DomainTypePhysical or DomainTypeL3, and name.
+func (s *AccessPolicyService) GetDomain(ctx context.Context, domainType, name string) (*Domain, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", domainDN(domainType, name))
+	ac, err := s.getAccess(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get domain: %v", err)
+	}
+	for _, c := range ac {
+		if d := domainFromResponse(c); d != nil {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("get domain: %s not found", domainDN(domainType, name))
+}
+
+// ListDomains lists all physical and L3 domains.
+func (s *AccessPolicyService) ListDomains(ctx context.Context) ([]*Domain, error) {
+	var domains []*Domain
+	for _, class := range []string{"physDomP", "l3extDomP"} {
+		ac, err := s.listClass(ctx, class)
+		if err != nil {
+			return nil, fmt.Errorf("list domains: %v", err)
+		}
+		for _, c := range ac {
+			if d := domainFromResponse(c); d != nil {
+				domains = append(domains, d)
+			}
+		}
+	}
+	return domains, nil
+}
+
+// domainFromResponse returns the physical or L3 domain of a container,
+// or nil if it holds neither.
+func domainFromResponse(c AccessContainer) *Domain {
+	domain := &Domain{}
+	o := c.PhysDomP
+	switch {
+	case c.PhysDomP != nil:
+		domain.domainType = DomainTypePhysical
+	case c.L3extDomP != nil:
+		domain.domainType = DomainTypeL3
+		o = c.L3extDomP
+	default:
+		return nil
+	}
+	domain.name, domain.description, domain.status = o.Name, o.Descr, o.Status
+	for _, child := range o.Children {
+		if child.RsVlanNs != nil {
+			domain.pool, domain.poolAllocation = vlanPoolFromDN(child.RsVlanNs.TDN)
+		}
+	}
+	return domain
+}
+
+// vlanPoolFromDN returns the name and allocation mode of
+// a VLAN pool from its distinguished name.
+func vlanPoolFromDN(dn string) (string, string) {
+	dn = strings.TrimPrefix(dn, "uni/infra/vlanns-[")
+	i := strings.LastIndex(dn, "]-")
+	if i < 0 {
+		return "", ""
+	}
+	return dn[:i], dn[i+len("]-"):]
+}
+
+func newAAEPContainer(aaep *AAEP) AccessContainer {
+	o := &AccessObject{
+		AccessAttrs: AccessAttrs{
+			DN:     aaep.DN(),
+			Name:   aaep.Name(),
+			Descr:  aaep.Description(),
+			Status: aaep.Status(),
+		},
+	}
+
+	// children are implicitly removed with their AAEP
+	if aaep.Status() != deleted {
+		for _, domain := range aaep.Domains() {
+			o.Children = append(o.Children, AccessContainer{
+				RsDomP: newRelation(RelationAttrs{TDN: domain}),
+			})
+		}
+	}
+
+	return AccessContainer{AttEntityP: o}
+}
+
+// CreateAAEP creates an AAEP.
+func (s *AccessPolicyService) CreateAAEP(ctx context.Context, aaep *AAEP) (AccessResponse, error) {
+	aaep.SetCreated()
+	return s.UpdateAAEP(ctx, aaep)
+}
+
+// DeleteAAEP deletes an AAEP.
+func (s *AccessPolicyService) DeleteAAEP(ctx context.Context, aaep *AAEP) (AccessResponse, error) {
+	aaep.SetDeleted()
+	return s.UpdateAAEP(ctx, aaep)
+}
+
+// UpdateAAEP creates, modifies or deletes an AAEP according to its status.
+func (s *AccessPolicyService) UpdateAAEP(ctx context.Context, aaep *AAEP) (AccessResponse, error) {
+	return s.postAccess(ctx, aaep.DN(), newAAEPContainer(aaep))
+}
+
+// GetAAEP retrieves an AAEP.
+func (s *AccessPolicyService) GetAAEP(ctx context.Context, name string) (*AAEP, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", aaepDN(name))
+	ac, err := s.getAccess(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get aaep: %v", err)
+	}
+	if len(ac) == 0 || ac[0].AttEntityP == nil {
+		return nil, fmt.Errorf("get aaep: %s not found", name)
+	}
+	return aaepFromResponse(ac[0].AttEntityP), nil
+}
+
+// ListAAEPs lists all AAEPs.
+func (s *AccessPolicyService) ListAAEPs(ctx context.Context) ([]*AAEP, error) {
+	ac, err := s.listClass(ctx, "infraAttEntityP")
+	if err != nil {
+		return nil, fmt.Errorf("list aaeps: %v", err)
+	}
+
+	var aaeps []*AAEP
+	for _, c := range ac {
+		if c.AttEntityP != nil {
+			aaeps = append(aaeps, aaepFromResponse(c.AttEntityP))
+		}
+	}
+	return aaeps, nil
+}
+
+func aaepFromResponse(o *AccessObject) *AAEP {
+	aaep := &AAEP{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+	}
+	for _, c := range o.Children {
+		if c.RsDomP != nil {
+			aaep.domains = appendName(aaep.domains, c.RsDomP.TDN)
+		}
+	}
+	return aaep
+}
+
+// VLANPermit is the chain of access policies permitting
+// a VLAN to be deployed on an interface.
+type VLANPermit struct {
+	AAEP   string
+	Domain string // distinguished name
+	Pool   string // "<name> (<allocation>)"
+	Block  *EncapBlock
+}
+
+// String returns the string representation of a permit
+func (p VLANPermit) String() string {
+	return fmt.Sprintf("%s -> %s -> %s %v", p.AAEP, p.Domain, p.Pool, p.Block)
+}
+
+// Resolve answers which AAEP, domain and VLAN pool permit a VLAN on an
+// access port of a node, such as "eth1/10", following the switch profiles
+// selecting the node to their interface profiles, the port selectors of
+// those selecting the port to their policy groups, and those to their AAEPs.
+// A breakout port is matched by its parent port. The domains of the AAEPs
+// can be physical, L3 or VMM domains.
+//
+// No permits are returned if nothing permits the VLAN.
+func (s *AccessPolicyService) Resolve(ctx context.Context, vlan int, node *Node, port string) ([]VLANPermit, error) {
+	if node == nil || node.ID() == "" {
+		return nil, fmt.Errorf("resolve: node must have an id")
+	}
+	if !portPattern.MatchString(port) {
+		return nil, fmt.Errorf("resolve: invalid port: %s", port)
+	}
+	aaeps, err := s.interfaceAAEPs(ctx, node.ID(), port)
+	if err != nil {
+		return nil, fmt.Errorf("resolve: %v", err)
+	}
+	if len(aaeps) == 0 {
+		return nil, nil
+	}
+
+	domainPools, err := s.domainPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve: %v", err)
+	}
+
+	pools, err := s.ListVLANPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve: %v", err)
+	}
+	poolsByDN := make(map[string]*VLANPool)
+	for _, p := range pools {
+		poolsByDN[p.DN()] = p
+	}
+
+	var permits []VLANPermit
+	for _, aaep := range aaeps {
+		for _, dn := range aaep.Domains() {
+			pool, ok := poolsByDN[domainPools[dn]]
+			if !ok {
+				continue
+			}
+			if block := pool.Block(vlan); block != nil {
+				permits = append(permits, VLANPermit{
+					AAEP:   aaep.Name(),
+					Domain: dn,
+					Pool:   fmt.Sprintf("%s (%s)", pool.Name(), pool.Allocation()),
+					Block:  block,
+				})
+			}
+		}
+	}
+	return permits, nil
+}
+
+// domainPools returns the distinguished names of the VLAN pools of the
+// physical, L3 and VMM domains, by the distinguished names of the domains.
+func (s *AccessPolicyService) domainPools(ctx context.Context) (map[string]string, error) {
+	domains, err := s.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pools := make(map[string]string)
+	for _, d := range domains {
+		pools[d.DN()] = vlanPoolDN(d.VLANPool())
+	}
+
+	// VMM domains are not access policies themselves, but their VLANs are
+	// deployed through AAEPs all the same
+	vmmDomains, err := s.listClass(ctx, "vmmDomP")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range vmmDomains {
+		if c.VmmDomP == nil {
+			continue
+		}
+		for _, child := range c.VmmDomP.Children {
+			if child.RsVlanNs != nil {
+				pools[c.VmmDomP.DN] = child.RsVlanNs.TDN
+			}
+		}
+	}
+	return pools, nil
+}
+
+// interfaceAAEPs returns the AAEPs of the policy groups applied
+// to an access port of a node.
+func (s *AccessPolicyService) interfaceAAEPs(ctx context.Context, node, port string) ([]*AAEP, error) {
+	id, _ := strconv.Atoi(node)
+	card, number := portNumbers(port)
+
+	// switch profiles selecting the node, to their interface profiles
+	switchProfiles, err := s.listClass(ctx, "infraNodeP")
+	if err != nil {
+		return nil, err
+	}
+	interfaceProfiles := make(map[string]bool)
+	for _, c := range switchProfiles {
+		if c.NodeP == nil {
+			continue
+		}
+		var selected bool
+		var profiles []string
+		for _, child := range c.NodeP.Children {
+			switch {
+			case child.LeafS != nil && selectsNode(child.LeafS, id):
+				selected = true
+			case child.RsAccPortP != nil:
+				profiles = append(profiles, child.RsAccPortP.TDN)
+			}
+		}
+		if selected {
+			for _, p := range profiles {
+				interfaceProfiles[p] = true
+			}
+		}
+	}
+
+	// port selectors of those interface profiles selecting the port,
+	// to their policy groups
+	accPortPs, err := s.listClass(ctx, "infraAccPortP")
+	if err != nil {
+		return nil, err
+	}
+	policyGroups := make(map[string]bool)
+	for _, c := range accPortPs {
+		if c.AccPortP == nil || !interfaceProfiles[c.AccPortP.DN] {
+			continue
+		}
+		for _, child := range c.AccPortP.Children {
+			if child.HPortS == nil || !selectsPort(child.HPortS, card, number) {
+				continue
+			}
+			for _, r := range child.HPortS.Children {
+				if r.RsAccBaseGrp != nil {
+					policyGroups[r.RsAccBaseGrp.TDN] = true
+				}
+			}
+		}
+	}
+
+	// those policy groups, to their AAEPs
+	names := make(map[string]bool)
+	for _, class := range []string{"infraAccPortGrp", "infraAccBndlGrp"} {
+		groups, err := s.listClass(ctx, class)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range groups {
+			g := c.AccPortGrp
+			if g == nil {
+				g = c.AccBndlGrp
+			}
+			if g == nil || !policyGroups[g.DN] {
+				continue
+			}
+			for _, r := range g.Children {
+				if r.RsAttEntP != nil {
+					names[strings.TrimPrefix(r.RsAttEntP.TDN, "uni/infra/attentp-")] = true
+				}
+			}
+		}
+	}
+
+	var aaeps []*AAEP
+	if len(names) == 0 {
+		return aaeps, nil
+	}
+	all, err := s.ListAAEPs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, aaep := range all {
+		if names[aaep.Name()] {
+			aaeps = append(aaeps, aaep)
+		}
+	}
+	sort.Slice(aaeps, func(i, j int) bool { return aaeps[i].Name() < aaeps[j].Name() })
+	return aaeps, nil
+}
+
+// portNumbers returns the card and port numbers of an access port,
+// such as 1 and 10 for "eth1/10".
+func portNumbers(port string) (int, int) {
+	parts := strings.Split(strings.TrimPrefix(port, "eth"), "/")
+	card, _ := strconv.Atoi(parts[0])
+	number, _ := strconv.Atoi(parts[1])
+	return card, number
+}
+
+// selectsNode reports whether a switch selector selects a node.
+func selectsNode(leafS *AccessObject, id int) bool {
+	if leafS.Type == "ALL" {
+		return true
+	}
+	for _, c := range leafS.Children {
+		if c.NodeBlk == nil {
+			continue
+		}
+		from, _ := strconv.Atoi(c.NodeBlk.FromNode)
+		to, _ := strconv.Atoi(c.NodeBlk.ToNode)
+		if id >= from && id <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsPort reports whether a port selector selects a port. A block
+// runs from one card and port to another, so the card and port are
+// compared as a pair: eth1/48 is in the block from eth1/10 to eth2/5.
+func selectsPort(hPortS *AccessObject, card, port int) bool {
+	if hPortS.Type == "ALL" {
+		return true
+	}
+	for _, c := range hPortS.Children {
+		if c.PortBlk == nil {
+			continue
+		}
+		fromCard, _ := strconv.Atoi(c.PortBlk.FromCard)
+		toCard, _ := strconv.Atoi(c.PortBlk.ToCard)
+		fromPort, _ := strconv.Atoi(c.PortBlk.FromPort)
+		toPort, _ := strconv.Atoi(c.PortBlk.ToPort)
+		from := fromCard < card || fromCard == card && fromPort <= port
+		to := card < toCard || card == toCard && port <= toPort
+		if from && to {
+			return true
+		}
+	}
+	return false
+}
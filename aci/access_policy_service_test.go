@@ -0,0 +1,240 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// newTestVLANPool returns a pool with a block of each of the given ranges.
+func newTestVLANPool(t *testing.T, name, allocation string, ranges ...[2]int) *VLANPool {
+	t.Helper()
+	s := &AccessPolicyService{}
+	pool, err := s.NewVLANPool(name, allocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range ranges {
+		b, err := s.NewEncapBlock(r[0], r[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.AddBlock(b)
+	}
+	return pool
+}
+
+func TestVLANOverlaps(t *testing.T) {
+	phys := newTestVLANPool(t, "phys", "static", [2]int{100, 199}, [2]int{300, 300})
+	vmm := newTestVLANPool(t, "vmm", "dynamic", [2]int{150, 250})
+	l3 := newTestVLANPool(t, "l3", "static", [2]int{200, 299}, [2]int{300, 310})
+
+	var got []string
+	for _, o := range VLANOverlaps(phys, vmm, l3) {
+		got = append(got, o.String())
+	}
+	want := []string{
+		"phys (static) 100-199 overlaps vmm (dynamic) 150-250",
+		"phys (static) 300 overlaps l3 (static) 300-310",
+		"vmm (dynamic) 150-250 overlaps l3 (static) 200-299",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// blocks being deleted don't overlap
+	vmm.Blocks()[0].SetDeleted()
+	l3.Blocks()[1].SetDeleted()
+	if overlaps := VLANOverlaps(phys, vmm, l3); len(overlaps) != 0 {
+		t.Errorf("got overlaps %v, want none", overlaps)
+	}
+
+	// nor do pools with themselves
+	if overlaps := VLANOverlaps(phys); len(overlaps) != 0 {
+		t.Errorf("got overlaps %v, want none", overlaps)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/class/fvnsVlanInstP.json": `{"imdata":[
+			{"fvnsVlanInstP":{"attributes":{"name":"phys","allocMode":"static"},"children":[
+				{"fvnsEncapBlk":{"attributes":{"from":"vlan-100","to":"vlan-199","allocMode":"inherit"}}}
+			]}},
+			{"fvnsVlanInstP":{"attributes":{"name":"vmm","allocMode":"dynamic"},"children":[
+				{"fvnsEncapBlk":{"attributes":{"from":"vlan-150","to":"vlan-250","allocMode":"inherit"}}}
+			]}}
+		]}`,
+	})
+
+	// the given pool takes the place of the one on the APIC
+	vmm := newTestVLANPool(t, "vmm", "dynamic", [2]int{1000, 1999})
+	l3 := newTestVLANPool(t, "l3", "static", [2]int{1500, 1500})
+	overlaps, err := c.AccessPolicy.Overlaps(context.Background(), vmm, l3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 1 || overlaps[0].String() != "vmm (dynamic) 1000-1999 overlaps l3 (static) 1500" {
+		t.Errorf("got overlaps %v", overlaps)
+	}
+
+	// as does a pool being deleted, which overlaps nothing
+	vmm.SetDeleted()
+	overlaps, err = c.AccessPolicy.Overlaps(context.Background(), vmm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 0 {
+		t.Errorf("got overlaps %v, want none", overlaps)
+	}
+}
+
+func TestDomainUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	pool := newTestVLANPool(t, "phys", "static")
+	domain, err := c.AccessPolicy.NewPhysicalDomain("phys1", pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := domain.SetDescription("servers"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AccessPolicy.CreateDomain(context.Background(), domain); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted("/api/node/mo/uni/phys-phys1.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"physDomP":{"attributes":{"dn":"uni/phys-phys1","name":"phys1","descr":"servers","status":"created,modified"},"children":[
+		{"infraRsVlanNs":{"attributes":{"tDn":"uni/infra/vlanns-[phys]-static"}}}
+	]}}`)
+}
+
+// resolveFixtures select port eth1/10 of leaf 101, and eth1/48 of leaves
+// 101 and 102 through a block across cards, to the AAEP "servers" of the
+// physical domain "phys1" and the VMM domain "dvs1".
+var resolveFixtures = map[string]string{
+	"/api/node/class/infraNodeP.json": `{"imdata":[
+		{"infraNodeP":{"attributes":{"dn":"uni/infra/nprof-leaf-101"},"children":[
+			{"infraLeafS":{"attributes":{"type":"range"},"children":[{"infraNodeBlk":{"attributes":{"from_":"101","to_":"102"}}}]}},
+			{"infraRsAccPortP":{"attributes":{"tDn":"uni/infra/accportprof-leaf-101"}}}
+		]}},
+		{"infraNodeP":{"attributes":{"dn":"uni/infra/nprof-leaf-201"},"children":[
+			{"infraLeafS":{"attributes":{"type":"range"},"children":[{"infraNodeBlk":{"attributes":{"from_":"201","to_":"201"}}}]}},
+			{"infraRsAccPortP":{"attributes":{"tDn":"uni/infra/accportprof-leaf-201"}}}
+		]}}
+	]}`,
+	"/api/node/class/infraAccPortP.json": `{"imdata":[
+		{"infraAccPortP":{"attributes":{"dn":"uni/infra/accportprof-leaf-101"},"children":[
+			{"infraHPortS":{"attributes":{"type":"range"},"children":[
+				{"infraPortBlk":{"attributes":{"fromCard":"1","toCard":"2","fromPort":"40","toPort":"5"}}},
+				{"infraRsAccBaseGrp":{"attributes":{"tDn":"uni/infra/funcprof/accportgrp-servers"}}}
+			]}},
+			{"infraHPortS":{"attributes":{"type":"range"},"children":[
+				{"infraPortBlk":{"attributes":{"fromCard":"1","toCard":"1","fromPort":"10","toPort":"10"}}},
+				{"infraRsAccBaseGrp":{"attributes":{"tDn":"uni/infra/funcprof/accbundle-vpc-srv1"}}}
+			]}}
+		]}},
+		{"infraAccPortP":{"attributes":{"dn":"uni/infra/accportprof-leaf-201"},"children":[
+			{"infraHPortS":{"attributes":{"type":"ALL"},"children":[
+				{"infraRsAccBaseGrp":{"attributes":{"tDn":"uni/infra/funcprof/accportgrp-routers"}}}
+			]}}
+		]}}
+	]}`,
+	"/api/node/class/infraAccPortGrp.json": `{"imdata":[
+		{"infraAccPortGrp":{"attributes":{"dn":"uni/infra/funcprof/accportgrp-servers"},"children":[
+			{"infraRsAttEntP":{"attributes":{"tDn":"uni/infra/attentp-servers"}}}
+		]}},
+		{"infraAccPortGrp":{"attributes":{"dn":"uni/infra/funcprof/accportgrp-routers"},"children":[
+			{"infraRsAttEntP":{"attributes":{"tDn":"uni/infra/attentp-routers"}}}
+		]}}
+	]}`,
+	"/api/node/class/infraAccBndlGrp.json": `{"imdata":[
+		{"infraAccBndlGrp":{"attributes":{"dn":"uni/infra/funcprof/accbundle-vpc-srv1"},"children":[
+			{"infraRsAttEntP":{"attributes":{"tDn":"uni/infra/attentp-servers"}}}
+		]}}
+	]}`,
+	"/api/node/class/infraAttEntityP.json": `{"imdata":[
+		{"infraAttEntityP":{"attributes":{"name":"servers"},"children":[
+			{"infraRsDomP":{"attributes":{"tDn":"uni/phys-phys1"}}},
+			{"infraRsDomP":{"attributes":{"tDn":"uni/vmmp-VMware/dom-dvs1"}}}
+		]}},
+		{"infraAttEntityP":{"attributes":{"name":"routers"},"children":[
+			{"infraRsDomP":{"attributes":{"tDn":"uni/l3dom-l3dom1"}}}
+		]}}
+	]}`,
+	"/api/node/class/physDomP.json": `{"imdata":[
+		{"physDomP":{"attributes":{"name":"phys1"},"children":[
+			{"infraRsVlanNs":{"attributes":{"tDn":"uni/infra/vlanns-[phys]-static"}}}
+		]}}
+	]}`,
+	"/api/node/class/l3extDomP.json": `{"imdata":[
+		{"l3extDomP":{"attributes":{"name":"l3dom1"},"children":[
+			{"infraRsVlanNs":{"attributes":{"tDn":"uni/infra/vlanns-[l3]-static"}}}
+		]}}
+	]}`,
+	"/api/node/class/vmmDomP.json": `{"imdata":[
+		{"vmmDomP":{"attributes":{"dn":"uni/vmmp-VMware/dom-dvs1","name":"dvs1"},"children":[
+			{"infraRsVlanNs":{"attributes":{"tDn":"uni/infra/vlanns-[vmm]-dynamic"}}}
+		]}}
+	]}`,
+	"/api/node/class/fvnsVlanInstP.json": `{"imdata":[
+		{"fvnsVlanInstP":{"attributes":{"name":"phys","allocMode":"static"},"children":[
+			{"fvnsEncapBlk":{"attributes":{"from":"vlan-100","to":"vlan-199","allocMode":"inherit"}}}
+		]}},
+		{"fvnsVlanInstP":{"attributes":{"name":"vmm","allocMode":"dynamic"},"children":[
+			{"fvnsEncapBlk":{"attributes":{"from":"vlan-150","to":"vlan-250","allocMode":"inherit"}}}
+		]}},
+		{"fvnsVlanInstP":{"attributes":{"name":"l3","allocMode":"static"},"children":[
+			{"fvnsEncapBlk":{"attributes":{"from":"vlan-300","to":"vlan-399","allocMode":"inherit"}}}
+		]}}
+	]}`,
+}
+
+func TestResolve(t *testing.T) {
+	_, c := newFakeAPIC(t, resolveFixtures)
+	leafA := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	leafB := newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf")
+	border := newTestNode(t, "leaf-201", "201", "1", "FDO3", "leaf")
+
+	tests := []struct {
+		name string
+		vlan int
+		node *Node
+		port string
+		want []string
+	}{
+		{"physical domain", 120, leafA, "eth1/10", []string{"servers -> uni/phys-phys1 -> phys (static) 100-199"}},
+		{"both domains", 160, leafA, "eth1/10", []string{
+			"servers -> uni/phys-phys1 -> phys (static) 100-199",
+			"servers -> uni/vmmp-VMware/dom-dvs1 -> vmm (dynamic) 150-250",
+		}},
+		{"vmm domain", 200, leafA, "eth1/10", []string{"servers -> uni/vmmp-VMware/dom-dvs1 -> vmm (dynamic) 150-250"}},
+		{"port across cards", 200, leafB, "eth1/48", []string{"servers -> uni/vmmp-VMware/dom-dvs1 -> vmm (dynamic) 150-250"}},
+		{"breakout port", 200, leafB, "eth2/5/1", []string{"servers -> uni/vmmp-VMware/dom-dvs1 -> vmm (dynamic) 150-250"}},
+		{"port after block", 200, leafB, "eth2/6", nil},
+		{"port before block", 200, leafB, "eth1/39", nil},
+		{"l3 domain", 300, border, "eth1/1", []string{"routers -> uni/l3dom-l3dom1 -> l3 (static) 300-399"}},
+		{"vlan in no pool", 400, leafA, "eth1/10", nil},
+		{"vlan of another aaep", 300, leafA, "eth1/10", nil},
+	}
+	for _, tt := range tests {
+		permits, err := c.AccessPolicy.Resolve(context.Background(), tt.vlan, tt.node, tt.port)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, p := range permits {
+			got = append(got, p.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := c.AccessPolicy.Resolve(context.Background(), 100, leafA, "1/10"); err == nil {
+		t.Error("expected an error for an invalid port")
+	}
+}
@@ -66,8 +66,20 @@ type Client struct {
 	Config     Config
 
 	// Services used for talking to different parts of the APIC API
-	FabricMembership *FabricMembershipService
-	Geolocation      *GeolocationService
+	FabricMembership   *FabricMembershipService
+	Geolocation        *GeolocationService
+	VPC                *VPCService
+	Pod                *PodService
+	Topology           *TopologyService
+	Tenant             *TenantService
+	VRF                *VRFService
+	BridgeDomain       *BridgeDomainService
+	ApplicationProfile *ApplicationProfileService
+	Contract           *ContractService
+	L3Out              *L3OutService
+	AccessPolicy       *AccessPolicyService
+	VMM                *VMMService
+	FabricPolicy       *FabricPolicyService
 }
 
 // Login authenticates a new APIC session, setting the authentication cookie
@@ -153,6 +165,18 @@ func NewClient(cfg Config) (*Client, error) {
 
 	c.FabricMembership = &FabricMembershipService{client: c}
 	c.Geolocation = &GeolocationService{client: c}
+	c.VPC = &VPCService{client: c}
+	c.Pod = &PodService{client: c}
+	c.Topology = &TopologyService{client: c}
+	c.Tenant = &TenantService{client: c}
+	c.VRF = &VRFService{client: c}
+	c.BridgeDomain = &BridgeDomainService{client: c}
+	c.ApplicationProfile = &ApplicationProfileService{client: c}
+	c.Contract = &ContractService{client: c}
+	c.L3Out = &L3OutService{client: c}
+	c.AccessPolicy = &AccessPolicyService{client: c}
+	c.VMM = &VMMService{client: c}
+	c.FabricPolicy = &FabricPolicyService{client: c}
 
 	return c, nil
 }
@@ -222,6 +246,26 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	return resp, err
 }
 
+// get requests the resource at path, decoding the response into v.
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := c.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(ctx, req, v)
+	return err
+}
+
+// post posts the payload to path, decoding the response into v.
+func (c *Client) post(ctx context.Context, path string, payload, v interface{}) error {
+	req, err := c.NewRequest(http.MethodPost, path, payload)
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(ctx, req, v)
+	return err
+}
+
 // loginRequest is the JSON request for authenticating with the APIC
 type loginRequest struct {
 	AAA `json:"aaaUser"`
@@ -269,6 +313,13 @@ type errorAttributes struct {
 }
 
 func (r *ErrorResponse) Error() string {
+	if len(r.Errors) == 0 {
+		return fmt.Sprintf("%v %v: %d",
+			r.Response.Request.Method,
+			r.Response.Request.URL,
+			r.Response.StatusCode,
+		)
+	}
 	return fmt.Sprintf("%v %v: %d %s (%s)",
 		r.Response.Request.Method,
 		r.Response.Request.URL,
@@ -0,0 +1,136 @@
+package aci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAPIC is a local fake of the APIC API, replying to GET requests
+// with the fixture of the longest matching path prefix, and recording
+// the body of every POST request.
+type fakeAPIC struct {
+	mu       sync.Mutex
+	fixtures map[string]string
+	posts    map[string][]string
+
+	// reply, when set, is given every request before the fixtures,
+	// and answers it with the returned status and body, unless the
+	// status is 0.
+	reply func(method, uri, body string) (int, string)
+}
+
+// newFakeAPIC starts a fake APIC with the given fixtures, keyed by the
+// path of the request and optionally its query, and returns a client
+// talking to it.
+func newFakeAPIC(t *testing.T, fixtures map[string]string) (*fakeAPIC, *Client) {
+	t.Helper()
+	if fixtures == nil {
+		fixtures = make(map[string]string)
+	}
+	f := &fakeAPIC{fixtures: fixtures, posts: make(map[string][]string)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{Host: "apic", Username: "admin", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.BaseURL, _ = url.Parse(srv.URL)
+	c.httpClient = srv.Client()
+	return f, c
+}
+
+func (f *fakeAPIC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+	uri := r.URL.Path
+	if r.URL.RawQuery != "" {
+		uri += "?" + r.URL.RawQuery
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.reply != nil {
+		if status, body := f.reply(r.Method, uri, string(b)); status != 0 {
+			w.WriteHeader(status)
+			io.WriteString(w, body)
+			return
+		}
+	}
+	if r.Method == http.MethodPost {
+		f.posts[r.URL.Path] = append(f.posts[r.URL.Path], string(b))
+		io.WriteString(w, `{"totalCount":"0","imdata":[]}`)
+		return
+	}
+	// the longest matching key wins, so a query can select a fixture
+	var match string
+	for key := range f.fixtures {
+		if strings.HasPrefix(uri, key) && len(key) > len(match) {
+			match = key
+		}
+	}
+	if match == "" {
+		io.WriteString(w, `{"totalCount":"0","imdata":[]}`)
+		return
+	}
+	io.WriteString(w, f.fixtures[match])
+}
+
+// setFixture sets the fixture replying to the given path.
+func (f *fakeAPIC) setFixture(path, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fixtures[path] = body
+}
+
+// setReply sets the function given every request before the fixtures.
+func (f *fakeAPIC) setReply(reply func(method, uri, body string) (int, string)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reply = reply
+}
+
+// posted returns the bodies posted to the given path.
+func (f *fakeAPIC) posted(path string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.posts[path]
+}
+
+// postCount returns the number of POST requests made.
+func (f *fakeAPIC) postCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int
+	for _, bodies := range f.posts {
+		n += len(bodies)
+	}
+	return n
+}
+
+// apicError returns the body of an APIC error response.
+func apicError(code, text string) string {
+	return fmt.Sprintf(`{"totalCount":"1","imdata":[{"error":{"attributes":{"code":"%s","text":"%s"}}}]}`, code, text)
+}
+
+// jsonEqual reports an error unless got and want are the same JSON.
+func jsonEqual(t *testing.T, got, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal([]byte(got), &g); err != nil {
+		t.Fatalf("%v: %s", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("%v: %s", err, want)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
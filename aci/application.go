@@ -0,0 +1,283 @@
+package aci
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ApplicationProfile is a tenant application profile, the container
+// for the endpoint groups of an application.
+type ApplicationProfile struct {
+	object
+	tenant string
+}
+
+// Tenant returns the name of the tenant the application profile belongs to.
+func (ap *ApplicationProfile) Tenant() string {
+	return ap.tenant
+}
+
+// String returns the string representation of an application profile
+func (ap *ApplicationProfile) String() string {
+	return fmt.Sprintf("%s/%s", ap.tenant, ap.name)
+}
+
+// EPG is an endpoint group of an application profile.
+type EPG struct {
+	object
+	tenant       string
+	app          string
+	bridgeDomain string
+	domains      []string
+	paths        []*StaticPath
+	provided     []string
+	consumed     []string
+}
+
+// Tenant returns the name of the tenant the EPG belongs to.
+func (epg *EPG) Tenant() string {
+	return epg.tenant
+}
+
+// ApplicationProfile returns the name of the application profile the EPG belongs to.
+func (epg *EPG) ApplicationProfile() string {
+	return epg.app
+}
+
+// BridgeDomain returns the name of the bridge domain the EPG is bound to.
+func (epg *EPG) BridgeDomain() string {
+	return epg.bridgeDomain
+}
+
+// SetBridgeDomain validates and sets the name of the bridge domain the EPG is bound to.
+func (epg *EPG) SetBridgeDomain(bd string) error {
+	if err := validateName("bridge domain name", bd); err != nil {
+		return err
+	}
+	epg.bridgeDomain = bd
+	return nil
+}
+
+// Domains returns the distinguished names of the domains the EPG is associated with.
+func (epg *EPG) Domains() []string {
+	return epg.domains
+}
+
+// AddPhysicalDomain validates and associates the EPG with a physical domain.
+func (epg *EPG) AddPhysicalDomain(domain string) error {
+	if err := validateName("physical domain name", domain); err != nil {
+		return err
+	}
+	epg.domains = appendName(epg.domains, fmt.Sprintf("uni/phys-%s", domain))
+	return nil
+}
+
+// AddVMMDomain validates and associates the EPG with a VMware VMM domain.
+func (epg *EPG) AddVMMDomain(domain string) error {
+	if err := validateName("vmm domain name", domain); err != nil {
+		return err
+	}
+	epg.domains = appendName(epg.domains, vmmDomainDN(domain))
+	return nil
+}
+
+// StaticPaths returns the static path bindings of the EPG.
+func (epg *EPG) StaticPaths() []*StaticPath {
+	return epg.paths
+}
+
+// AddStaticPath adds a static path binding to the EPG,
+// replacing any binding to the same path.
+func (epg *EPG) AddStaticPath(path *StaticPath) {
+	for i, p := range epg.paths {
+		if p.TDN() == path.TDN() {
+			epg.paths[i] = path
+			return
+		}
+	}
+	epg.paths = append(epg.paths, path)
+}
+
+// ProvidedContracts returns the contracts the EPG provides.
+func (epg *EPG) ProvidedContracts() []string {
+	return epg.provided
+}
+
+// ProvideContract validates and adds a contract for the EPG to provide.
+func (epg *EPG) ProvideContract(contract string) error {
+	if err := validateName("contract name", contract); err != nil {
+		return err
+	}
+	epg.provided = appendName(epg.provided, contract)
+	return nil
+}
+
+// ConsumedContracts returns the contracts the EPG consumes.
+func (epg *EPG) ConsumedContracts() []string {
+	return epg.consumed
+}
+
+// ConsumeContract validates and adds a contract for the EPG to consume.
+func (epg *EPG) ConsumeContract(contract string) error {
+	if err := validateName("contract name", contract); err != nil {
+		return err
+	}
+	epg.consumed = appendName(epg.consumed, contract)
+	return nil
+}
+
+// String returns the string representation of an EPG
+func (epg *EPG) String() string {
+	return fmt.Sprintf("%s/%s/%s", epg.tenant, epg.app, epg.name)
+}
+
+// Types of static path.
+const (
+	PathTypePort        = "port"
+	PathTypePortChannel = "pc"
+	PathTypeVPC         = "vpc"
+)
+
+// StaticPath is a static binding of an EPG to an access port,
+// port-channel or vPC, with the VLAN it is encapsulated in.
+type StaticPath struct {
+	pathType string
+	pod      string
+	nodes    []string
+	port     string
+	vlan     int
+	mode     string
+	status   string
+}
+
+// Type returns the type of the path, one of PathTypePort,
+// PathTypePortChannel or PathTypeVPC.
+func (p *StaticPath) Type() string {
+	return p.pathType
+}
+
+// Pod returns the id of the pod of the path.
+func (p *StaticPath) Pod() string {
+	return p.pod
+}
+
+// Nodes returns the ids of the nodes of the path,
+// two for a vPC and one otherwise.
+func (p *StaticPath) Nodes() []string {
+	return p.nodes
+}
+
+// Port returns the interface of an access port, such as "eth1/10",
+// or the interface policy group of a port-channel or vPC.
+func (p *StaticPath) Port() string {
+	return p.port
+}
+
+// VLAN returns the VLAN the EPG is encapsulated in on the path.
+func (p *StaticPath) VLAN() int {
+	return p.vlan
+}
+
+// SetVLAN validates and sets the VLAN the EPG is encapsulated in on the path.
+//
+// A VLAN must be a number between 1 and 4094 inclusive.
+func (p *StaticPath) SetVLAN(vlan int) error {
+	if vlan < 1 || vlan > 4094 {
+		return fmt.Errorf("invalid vlan: %d", vlan)
+	}
+	p.vlan = vlan
+	return nil
+}
+
+// Mode returns the mode of the path.
+func (p *StaticPath) Mode() string {
+	return p.mode
+}
+
+// SetMode sets the mode of the path.
+// Can only be "regular" (trunk), "untagged" (access) or "native" (802.1p)
+func (p *StaticPath) SetMode(mode string) error {
+	if mode != "regular" && mode != "untagged" && mode != "native" {
+		return fmt.Errorf("invalid mode: %s", mode)
+	}
+	p.mode = mode
+	return nil
+}
+
+// TDN returns the distinguished name of the path in the fabric topology.
+func (p *StaticPath) TDN() string {
+	return pathTDN(p.pathType, p.pod, p.nodes, p.port)
+}
+
+// pathTDN returns the distinguished name of an access port, port-channel
+// or vPC in the fabric topology.
+func pathTDN(pathType, pod string, nodes []string, port string) string {
+	switch pathType {
+	case PathTypeVPC:
+		return fmt.Sprintf("topology/pod-%s/protpaths-%s-%s/pathep-[%s]", pod, nodes[0], nodes[1], port)
+	default:
+		return fmt.Sprintf("topology/pod-%s/paths-%s/pathep-[%s]", pod, nodes[0], port)
+	}
+}
+
+// SetCreated sets the status of the path to "created,modified".
+func (p *StaticPath) SetCreated() string {
+	p.status = createdModified
+	return p.status
+}
+
+// SetDeleted sets the status of the path to "deleted".
+func (p *StaticPath) SetDeleted() string {
+	p.status = deleted
+	return p.status
+}
+
+// Status returns the status of the path.
+func (p *StaticPath) Status() string {
+	return p.status
+}
+
+// String returns the string representation of a static path
+func (p *StaticPath) String() string {
+	return fmt.Sprintf("%s vlan-%d %s", p.TDN(), p.vlan, p.mode)
+}
+
+// pathPattern matches the distinguished name of a static path.
+var pathPattern = regexp.MustCompile(`^topology/pod-(\d+)/(paths-(\d+)|protpaths-(\d+)-(\d+))/pathep-\[(.+)\]$`)
+
+// staticPathFromTDN returns a static path from its distinguished name,
+// or nil if it isn't one.
+func staticPathFromTDN(tdn, encap, mode string) *StaticPath {
+	pathType, pod, nodes, port, ok := parsePathTDN(tdn)
+	if !ok {
+		return nil
+	}
+	p := &StaticPath{pathType: pathType, pod: pod, nodes: nodes, port: port, mode: mode}
+	if len(encap) > len("vlan-") {
+		p.vlan, _ = strconv.Atoi(encap[len("vlan-"):])
+	}
+	return p
+}
+
+// parsePathTDN returns the type, pod, nodes and port of a path from its
+// distinguished name. A path to an interface policy group is taken
+// to be a port-channel, unless it is a vPC.
+func parsePathTDN(tdn string) (pathType, pod string, nodes []string, port string, ok bool) {
+	m := pathPattern.FindStringSubmatch(tdn)
+	if m == nil {
+		return "", "", nil, "", false
+	}
+	switch {
+	case m[3] != "" && portPattern.MatchString(m[6]):
+		return PathTypePort, m[1], []string{m[3]}, m[6], true
+	case m[3] != "":
+		return PathTypePortChannel, m[1], []string{m[3]}, m[6], true
+	default:
+		return PathTypeVPC, m[1], []string{m[4], m[5]}, m[6], true
+	}
+}
+
+// portPattern matches a front panel port, such as "eth1/10",
+// or a breakout port, such as "eth1/49/1".
+var portPattern = regexp.MustCompile(`^eth[0-9]+/[0-9]+(/[0-9]+)?$`)
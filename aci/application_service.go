@@ -0,0 +1,426 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ApplicationProfileContainer is a container for an application profile
+type ApplicationProfileContainer struct {
+	FvAp `json:"fvAp"`
+}
+
+// FvAp is an application profile
+type FvAp struct {
+	ApplicationAttrs `json:"attributes"`
+}
+
+// EPGContainer is a container for an endpoint group
+type EPGContainer struct {
+	FvAEPg `json:"fvAEPg"`
+}
+
+// FvAEPg is an endpoint group
+type FvAEPg struct {
+	ApplicationAttrs `json:"attributes"`
+	Children         []EPGChild `json:"children,omitempty"`
+}
+
+// ApplicationAttrs contains the attributes of an application
+// profile or endpoint group
+type ApplicationAttrs struct {
+	Descr  string `json:"descr,omitempty"`
+	DN     string `json:"dn,omitempty"`
+	Name   string `json:"name,omitempty"`
+	RN     string `json:"rn,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// EPGChild is a child of an endpoint group. Only one of its fields is set.
+type EPGChild struct {
+	RsBd      *Relation `json:"fvRsBd,omitempty"`
+	RsDomAtt  *Relation `json:"fvRsDomAtt,omitempty"`
+	RsPathAtt *Relation `json:"fvRsPathAtt,omitempty"`
+	RsProv    *Relation `json:"fvRsProv,omitempty"`
+	RsCons    *Relation `json:"fvRsCons,omitempty"`
+}
+
+// ApplicationProfileResponse contains the response for application profile requests
+type ApplicationProfileResponse struct {
+	TotalCount string                        `json:"totalCount"`
+	Imdata     []ApplicationProfileContainer `json:"imdata"`
+}
+
+// EPGResponse contains the response for endpoint group requests
+type EPGResponse struct {
+	TotalCount string         `json:"totalCount"`
+	Imdata     []EPGContainer `json:"imdata"`
+}
+
+// ApplicationProfileService handles communication with the application
+// profile and endpoint group related methods of the APIC API.
+type ApplicationProfileService service
+
+// applicationProfileDN returns the distinguished name of an application profile.
+func applicationProfileDN(tenant, ap string) string {
+	return fmt.Sprintf("%s/ap-%s", tenantDN(tenant), ap)
+}
+
+// epgDN returns the distinguished name of an endpoint group.
+func epgDN(tenant, ap, epg string) string {
+	return fmt.Sprintf("%s/epg-%s", applicationProfileDN(tenant, ap), epg)
+}
+
+// NewApplicationProfile instantiates a valid application profile within a tenant.
+func (s *ApplicationProfileService) NewApplicationProfile(tenant, name string) (*ApplicationProfile, error) {
+	ap := &ApplicationProfile{}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return ap, err
+	}
+	ap.tenant = tenant
+
+	if err := ap.SetName(name); err != nil {
+		return ap, err
+	}
+
+	return ap, nil
+}
+
+// Create creates an application profile.
+func (s *ApplicationProfileService) Create(ctx context.Context, ap *ApplicationProfile) (ApplicationProfileResponse, error) {
+	ap.SetCreated()
+	return s.Update(ctx, ap)
+}
+
+// Delete deletes an application profile and every EPG within it.
+func (s *ApplicationProfileService) Delete(ctx context.Context, ap *ApplicationProfile) (ApplicationProfileResponse, error) {
+	ap.SetDeleted()
+	return s.Update(ctx, ap)
+}
+
+// Update creates, modifies or deletes an application profile according to its status.
+func (s *ApplicationProfileService) Update(ctx context.Context, ap *ApplicationProfile) (ApplicationProfileResponse, error) {
+	dn := applicationProfileDN(ap.Tenant(), ap.Name())
+	path := fmt.Sprintf("api/node/mo/%s.json", dn)
+	payload := ApplicationProfileContainer{
+		FvAp: FvAp{
+			ApplicationAttrs: ApplicationAttrs{
+				DN:     dn,
+				Name:   ap.Name(),
+				Descr:  ap.Description(),
+				Status: ap.Status(),
+			},
+		},
+	}
+
+	var ar ApplicationProfileResponse
+	err := s.client.post(ctx, path, payload, &ar)
+	return ar, err
+}
+
+// Get retrieves an application profile of a tenant.
+func (s *ApplicationProfileService) Get(ctx context.Context, tenant, name string) (*ApplicationProfile, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", applicationProfileDN(tenant, name))
+
+	var ar ApplicationProfileResponse
+	if err := s.client.get(ctx, path, &ar); err != nil {
+		return nil, fmt.Errorf("get application profile: %v", err)
+	}
+	if len(ar.Imdata) == 0 {
+		return nil, fmt.Errorf("get application profile: %s/%s not found", tenant, name)
+	}
+	a := ar.Imdata[0]
+	return &ApplicationProfile{
+		object: object{name: a.Name, description: a.Descr, status: a.Status},
+		tenant: tenant,
+	}, nil
+}
+
+// List lists all application profiles of a tenant.
+func (s *ApplicationProfileService) List(ctx context.Context, tenant string) ([]*ApplicationProfile, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=fvAp", tenantDN(tenant))
+
+	var ar ApplicationProfileResponse
+	if err := s.client.get(ctx, path, &ar); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var aps []*ApplicationProfile
+	for _, a := range ar.Imdata {
+		aps = append(aps, &ApplicationProfile{
+			object: object{name: a.Name, description: a.Descr, status: a.Status},
+			tenant: tenant,
+		})
+	}
+	return aps, nil
+}
+
+// NewEPG instantiates a valid EPG within an application profile,
+// bound to the given bridge domain.
+func (s *ApplicationProfileService) NewEPG(tenant, ap, name, bd string) (*EPG, error) {
+	epg := &EPG{}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return epg, err
+	}
+	epg.tenant = tenant
+
+	if err := validateName("application profile name", ap); err != nil {
+		return epg, err
+	}
+	epg.app = ap
+
+	if err := epg.SetName(name); err != nil {
+		return epg, err
+	}
+
+	if err := epg.SetBridgeDomain(bd); err != nil {
+		return epg, err
+	}
+
+	return epg, nil
+}
+
+// NewPortPath instantiates a valid static path to an access port of a node,
+// such as "eth1/10".
+func (s *ApplicationProfileService) NewPortPath(node *Node, port string, vlan int, mode string) (*StaticPath, error) {
+	p := &StaticPath{pathType: PathTypePort}
+	if !portPattern.MatchString(port) {
+		return p, fmt.Errorf("invalid port: %s", port)
+	}
+	p.port = port
+	return p, p.setNodes(vlan, mode, node)
+}
+
+// NewPortChannelPath instantiates a valid static path to a port-channel of a
+// node, identified by its interface policy group.
+func (s *ApplicationProfileService) NewPortChannelPath(node *Node, policyGroup string, vlan int, mode string) (*StaticPath, error) {
+	p := &StaticPath{pathType: PathTypePortChannel}
+	if err := validateName("policy group name", policyGroup); err != nil {
+		return p, err
+	}
+	p.port = policyGroup
+	return p, p.setNodes(vlan, mode, node)
+}
+
+// NewVPCPath instantiates a valid static path to a vPC across a pair of
+// nodes, identified by its interface policy group.
+func (s *ApplicationProfileService) NewVPCPath(a, b *Node, policyGroup string, vlan int, mode string) (*StaticPath, error) {
+	p := &StaticPath{pathType: PathTypeVPC}
+	if err := validateName("policy group name", policyGroup); err != nil {
+		return p, err
+	}
+	p.port = policyGroup
+	return p, p.setNodes(vlan, mode, a, b)
+}
+
+// setNodes validates and sets the nodes, VLAN and mode of a static path.
+// The nodes of a vPC are ordered by node id, as the APIC expects.
+func (p *StaticPath) setNodes(vlan int, mode string, nodes ...*Node) error {
+	for _, node := range nodes {
+		if node == nil || node.ID() == "" || node.Pod() == "" {
+			return fmt.Errorf("invalid static path: nodes must have an id and pod")
+		}
+	}
+	if len(nodes) == 2 {
+		if nodes[0].Pod() != nodes[1].Pod() {
+			return fmt.Errorf("invalid static path: nodes %s and %s are in different pods", nodes[0].ID(), nodes[1].ID())
+		}
+		a, _ := strconv.Atoi(nodes[0].ID())
+		b, _ := strconv.Atoi(nodes[1].ID())
+		if a == b {
+			return fmt.Errorf("invalid static path: node %s is paired with itself", nodes[0].ID())
+		}
+		if a > b {
+			nodes[0], nodes[1] = nodes[1], nodes[0]
+		}
+	}
+	p.pod = nodes[0].Pod()
+	for _, node := range nodes {
+		p.nodes = append(p.nodes, node.ID())
+	}
+	if err := p.SetVLAN(vlan); err != nil {
+		return err
+	}
+	return p.SetMode(mode)
+}
+
+func newEPGContainer(epg *EPG) EPGContainer {
+	c := EPGContainer{
+		FvAEPg: FvAEPg{
+			ApplicationAttrs: ApplicationAttrs{
+				DN:     epgDN(epg.Tenant(), epg.ApplicationProfile(), epg.Name()),
+				Name:   epg.Name(),
+				Descr:  epg.Description(),
+				Status: epg.Status(),
+			},
+		},
+	}
+
+	// children are implicitly removed with their EPG
+	if epg.Status() == deleted {
+		return c
+	}
+
+	c.Children = append(c.Children, EPGChild{
+		RsBd: newRelation(RelationAttrs{TnFvBDName: epg.BridgeDomain()}),
+	})
+	for _, domain := range epg.Domains() {
+		c.Children = append(c.Children, EPGChild{
+			RsDomAtt: newRelation(RelationAttrs{TDN: domain}),
+		})
+	}
+	for _, contract := range epg.ProvidedContracts() {
+		c.Children = append(c.Children, EPGChild{
+			RsProv: newRelation(RelationAttrs{TnVzBrCPName: contract}),
+		})
+	}
+	for _, contract := range epg.ConsumedContracts() {
+		c.Children = append(c.Children, EPGChild{
+			RsCons: newRelation(RelationAttrs{TnVzBrCPName: contract}),
+		})
+	}
+	for _, p := range epg.StaticPaths() {
+		c.Children = append(c.Children, EPGChild{
+			RsPathAtt: newRelation(RelationAttrs{
+				TDN:         p.TDN(),
+				Encap:       fmt.Sprintf("vlan-%d", p.VLAN()),
+				Mode:        p.Mode(),
+				InstrImedcy: "immediate",
+				Status:      p.Status(),
+			}),
+		})
+	}
+
+	return c
+}
+
+// CreateEPG creates an EPG.
+func (s *ApplicationProfileService) CreateEPG(ctx context.Context, epg *EPG) (EPGResponse, error) {
+	epg.SetCreated()
+	return s.UpdateEPG(ctx, epg)
+}
+
+// DeleteEPG deletes an EPG.
+func (s *ApplicationProfileService) DeleteEPG(ctx context.Context, epg *EPG) (EPGResponse, error) {
+	epg.SetDeleted()
+	return s.UpdateEPG(ctx, epg)
+}
+
+// UpdateEPG creates, modifies or deletes an EPG according to its status,
+// along with each of its static paths according to theirs.
+//
+// The nodes of every static path not being deleted must be registered
+// fabric members, so that a mistyped node id fails before it is posted.
+func (s *ApplicationProfileService) UpdateEPG(ctx context.Context, epg *EPG) (EPGResponse, error) {
+	var er EPGResponse
+
+	if epg.Status() != deleted {
+		if err := s.checkPaths(ctx, epg.StaticPaths()); err != nil {
+			return er, err
+		}
+	}
+
+	path := fmt.Sprintf("api/node/mo/%s.json", epgDN(epg.Tenant(), epg.ApplicationProfile(), epg.Name()))
+	payload := newEPGContainer(epg)
+
+	err := s.client.post(ctx, path, payload, &er)
+	return er, err
+}
+
+// checkPaths returns an error if any node of the static paths
+// not being deleted is not a registered fabric member.
+func (s *ApplicationProfileService) checkPaths(ctx context.Context, paths []*StaticPath) error {
+	var check []*StaticPath
+	for _, p := range paths {
+		if p.Status() != deleted {
+			check = append(check, p)
+		}
+	}
+	if len(check) == 0 {
+		return nil
+	}
+
+	nodes, err := s.client.FabricMembership.List(ctx)
+	if err != nil {
+		return fmt.Errorf("check static paths: %v", err)
+	}
+	registered := make(map[string]*Node)
+	for _, node := range nodes {
+		registered[node.ID()] = node
+	}
+
+	for _, p := range check {
+		for _, id := range p.Nodes() {
+			node, ok := registered[id]
+			if !ok {
+				return fmt.Errorf("static path %s: node %s is not registered", p.TDN(), id)
+			}
+			if node.Pod() != p.Pod() {
+				return fmt.Errorf("static path %s: node %s is in pod %s", p.TDN(), id, node.Pod())
+			}
+		}
+	}
+	return nil
+}
+
+// GetEPG retrieves an EPG of an application profile.
+func (s *ApplicationProfileService) GetEPG(ctx context.Context, tenant, ap, name string) (*EPG, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", epgDN(tenant, ap, name))
+
+	var er EPGResponse
+	if err := s.client.get(ctx, path, &er); err != nil {
+		return nil, fmt.Errorf("get epg: %v", err)
+	}
+	if len(er.Imdata) == 0 {
+		return nil, fmt.Errorf("get epg: %s/%s/%s not found", tenant, ap, name)
+	}
+	return epgFromResponse(tenant, ap, er.Imdata[0]), nil
+}
+
+// ListEPGs lists all EPGs of an application profile.
+func (s *ApplicationProfileService) ListEPGs(ctx context.Context, tenant, ap string) ([]*EPG, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=fvAEPg&rsp-subtree=full", applicationProfileDN(tenant, ap))
+
+	var er EPGResponse
+	if err := s.client.get(ctx, path, &er); err != nil {
+		return nil, fmt.Errorf("list epgs: %v", err)
+	}
+
+	var epgs []*EPG
+	for _, e := range er.Imdata {
+		epgs = append(epgs, epgFromResponse(tenant, ap, e))
+	}
+	return epgs, nil
+}
+
+func epgFromResponse(tenant, ap string, e EPGContainer) *EPG {
+	epg := &EPG{
+		object: object{
+			name:        e.Name,
+			description: e.Descr,
+			status:      e.Status,
+		},
+		tenant: tenant,
+		app:    ap,
+	}
+	for _, c := range e.Children {
+		switch {
+		case c.RsBd != nil:
+			epg.bridgeDomain = c.RsBd.TnFvBDName
+		case c.RsDomAtt != nil:
+			epg.domains = appendName(epg.domains, c.RsDomAtt.TDN)
+		case c.RsProv != nil:
+			epg.provided = appendName(epg.provided, c.RsProv.TnVzBrCPName)
+		case c.RsCons != nil:
+			epg.consumed = appendName(epg.consumed, c.RsCons.TnVzBrCPName)
+		case c.RsPathAtt != nil:
+			if p := staticPathFromTDN(c.RsPathAtt.TDN, c.RsPathAtt.Encap, c.RsPathAtt.Mode); p != nil {
+				epg.paths = append(epg.paths, p)
+			}
+		}
+	}
+	return epg
+}
@@ -0,0 +1,198 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// epgFixtures register leaves 101 and 102 in pod 1, and leaf 201 in pod 2.
+var epgFixtures = map[string]string{
+	"/api/node/class/fabricNode.json": `{"imdata":[
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-101","id":"101","name":"leaf-101","role":"leaf"}}},
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-102","id":"102","name":"leaf-102","role":"leaf"}}},
+		{"fabricNode":{"attributes":{"dn":"topology/pod-2/node-201","id":"201","name":"leaf-201","role":"leaf"}}}
+	]}`,
+}
+
+const epgPath = "/api/node/mo/uni/tn-t1/ap-app1/epg-web.json"
+
+func TestStaticPathFromTDN(t *testing.T) {
+	tests := []struct {
+		tdn  string
+		want *StaticPath // nil if invalid
+		name string
+	}{
+		{
+			"topology/pod-1/paths-101/pathep-[eth1/10]",
+			&StaticPath{pathType: PathTypePort, pod: "1", nodes: []string{"101"}, port: "eth1/10", vlan: 10, mode: "regular"},
+			"port",
+		},
+		{
+			"topology/pod-1/paths-101/pathep-[eth1/49/1]",
+			&StaticPath{pathType: PathTypePort, pod: "1", nodes: []string{"101"}, port: "eth1/49/1", vlan: 10, mode: "regular"},
+			"breakout port",
+		},
+		{
+			"topology/pod-2/paths-201/pathep-[pc-srv1]",
+			&StaticPath{pathType: PathTypePortChannel, pod: "2", nodes: []string{"201"}, port: "pc-srv1", vlan: 10, mode: "regular"},
+			"port-channel",
+		},
+		{
+			"topology/pod-1/protpaths-101-102/pathep-[vpc-srv1]",
+			&StaticPath{pathType: PathTypeVPC, pod: "1", nodes: []string{"101", "102"}, port: "vpc-srv1", vlan: 10, mode: "regular"},
+			"vpc",
+		},
+		{"topology/pod-1/node-101", nil, "node"},
+		{"uni/phys-phys1", nil, "domain"},
+	}
+	for _, tt := range tests {
+		got := staticPathFromTDN(tt.tdn, "vlan-10", "regular")
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		if got != nil && got.TDN() != tt.tdn {
+			t.Errorf("%s: got tdn %s, want %s", tt.name, got.TDN(), tt.tdn)
+		}
+	}
+}
+
+func TestNewStaticPath(t *testing.T) {
+	s := &ApplicationProfileService{}
+	a := newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf")
+	b := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	other := newTestNode(t, "leaf-201", "201", "2", "FDO3", "leaf")
+
+	p, err := s.NewVPCPath(a, b, "vpc-srv1", 10, "regular")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "topology/pod-1/protpaths-101-102/pathep-[vpc-srv1]"; p.TDN() != want {
+		t.Errorf("got %s, want %s", p.TDN(), want)
+	}
+
+	invalid := []struct {
+		name string
+		call func() error
+	}{
+		{"vpc across pods", func() error { _, err := s.NewVPCPath(a, other, "vpc-srv1", 10, "regular"); return err }},
+		{"vpc to itself", func() error { _, err := s.NewVPCPath(a, a, "vpc-srv1", 10, "regular"); return err }},
+		{"invalid port", func() error { _, err := s.NewPortPath(a, "1/10", 10, "regular"); return err }},
+		{"invalid vlan", func() error { _, err := s.NewPortPath(a, "eth1/10", 4095, "regular"); return err }},
+		{"invalid mode", func() error { _, err := s.NewPortPath(a, "eth1/10", 10, "trunk"); return err }},
+		{"no node", func() error { _, err := s.NewPortChannelPath(nil, "pc-srv1", 10, "regular"); return err }},
+	}
+	for _, tt := range invalid {
+		if err := tt.call(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestUpdateEPG(t *testing.T) {
+	f, c := newFakeAPIC(t, epgFixtures)
+	s := c.ApplicationProfile
+	epg, err := s.NewEPG("t1", "app1", "web", "bd1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := epg.AddPhysicalDomain("phys1"); err != nil {
+		t.Fatal(err)
+	}
+	leaf := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	port, err := s.NewPortPath(leaf, "eth1/10", 10, "untagged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	epg.AddStaticPath(port)
+
+	if _, err := s.CreateEPG(context.Background(), epg); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted(epgPath)
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"fvAEPg":{"attributes":{"dn":"uni/tn-t1/ap-app1/epg-web","name":"web","status":"created,modified"},"children":[
+		{"fvRsBd":{"attributes":{"tnFvBDName":"bd1"}}},
+		{"fvRsDomAtt":{"attributes":{"tDn":"uni/phys-phys1"}}},
+		{"fvRsPathAtt":{"attributes":{"tDn":"topology/pod-1/paths-101/pathep-[eth1/10]","encap":"vlan-10","mode":"untagged","instrImedcy":"immediate"}}}
+	]}}`)
+
+	tests := []struct {
+		name string
+		node *Node
+		want string
+	}{
+		{"unregistered", newTestNode(t, "leaf-103", "103", "1", "FDO3", "leaf"), "node 103 is not registered"},
+		{"wrong pod", newTestNode(t, "leaf-201", "201", "1", "FDO4", "leaf"), "node 201 is in pod 2"},
+	}
+	for _, tt := range tests {
+		p, err := s.NewPortPath(tt.node, "eth1/11", 10, "regular")
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.SetCreated()
+		epg.AddStaticPath(p)
+		_, err = s.UpdateEPG(context.Background(), epg)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %s", tt.name, err, tt.want)
+		}
+
+		// a path being deleted is not checked
+		p.SetDeleted()
+		if _, err := s.UpdateEPG(context.Background(), epg); err != nil {
+			t.Errorf("%s: deleting path: %v", tt.name, err)
+		}
+	}
+	if got := len(f.posted(epgPath)); got != 3 {
+		t.Errorf("got %d posts, want 3", got)
+	}
+
+	// nor are the paths of a deleted EPG
+	p, err := s.NewPortPath(newTestNode(t, "leaf-104", "104", "1", "FDO5", "leaf"), "eth1/12", 10, "regular")
+	if err != nil {
+		t.Fatal(err)
+	}
+	epg.AddStaticPath(p)
+	if _, err := s.DeleteEPG(context.Background(), epg); err != nil {
+		t.Fatal(err)
+	}
+	posts = f.posted(epgPath)
+	jsonEqual(t, posts[len(posts)-1], `{"fvAEPg":{"attributes":{"dn":"uni/tn-t1/ap-app1/epg-web","name":"web","status":"deleted"}}}`)
+}
+
+func TestGetEPG(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		epgPath: `{"imdata":[{"fvAEPg":{"attributes":{"name":"web","descr":"web servers"},"children":[
+			{"fvRsBd":{"attributes":{"tnFvBDName":"bd1"}}},
+			{"fvRsDomAtt":{"attributes":{"tDn":"uni/vmmp-VMware/dom-dvs1"}}},
+			{"fvRsPathAtt":{"attributes":{"tDn":"topology/pod-1/protpaths-101-102/pathep-[vpc-srv1]","encap":"vlan-20","mode":"regular"}}},
+			{"fvRsPathAtt":{"attributes":{"tDn":"topology/pod-1/node-101","encap":"vlan-20","mode":"regular"}}}
+		]}}]}`,
+	})
+
+	epg, err := c.ApplicationProfile.GetEPG(context.Background(), "t1", "app1", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epg.String() != "t1/app1/web" || epg.Description() != "web servers" || epg.BridgeDomain() != "bd1" {
+		t.Errorf("got %s %q bd %s", epg, epg.Description(), epg.BridgeDomain())
+	}
+	if want := []string{"uni/vmmp-VMware/dom-dvs1"}; !reflect.DeepEqual(epg.Domains(), want) {
+		t.Errorf("got domains %v, want %v", epg.Domains(), want)
+	}
+	// the path that isn't one is skipped
+	if len(epg.StaticPaths()) != 1 {
+		t.Fatalf("got %d paths, want 1", len(epg.StaticPaths()))
+	}
+	if p := epg.StaticPaths()[0]; p.Type() != PathTypeVPC || p.VLAN() != 20 {
+		t.Errorf("got %s path vlan %d", p.Type(), p.VLAN())
+	}
+
+	if _, err := c.ApplicationProfile.GetEPG(context.Background(), "t1", "app1", "db"); err == nil {
+		t.Error("expected an error for a missing epg")
+	}
+}
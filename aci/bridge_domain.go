@@ -0,0 +1,235 @@
+package aci
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// BridgeDomain is a tenant bridge domain, a layer 2
+// forwarding domain within a VRF.
+type BridgeDomain struct {
+	object
+	tenant         string
+	vrf            string
+	unknownUnicast string
+	arpFlooding    bool
+	unicastRouting bool
+	l3Outs         []string
+	subnets        []*Subnet
+}
+
+// Tenant returns the name of the tenant the bridge domain belongs to.
+func (bd *BridgeDomain) Tenant() string {
+	return bd.tenant
+}
+
+// VRF returns the name of the VRF the bridge domain is bound to.
+func (bd *BridgeDomain) VRF() string {
+	return bd.vrf
+}
+
+// SetVRF validates and sets the name of the VRF the bridge domain is bound to.
+func (bd *BridgeDomain) SetVRF(vrf string) error {
+	if err := validateName("vrf name", vrf); err != nil {
+		return err
+	}
+	bd.vrf = vrf
+	return nil
+}
+
+// UnknownUnicast returns the L2 unknown unicast forwarding of the bridge domain.
+func (bd *BridgeDomain) UnknownUnicast() string {
+	return bd.unknownUnicast
+}
+
+// SetUnknownUnicast sets the L2 unknown unicast forwarding of the bridge domain.
+// Can only be "proxy" or "flood"
+func (bd *BridgeDomain) SetUnknownUnicast(action string) error {
+	if action != "proxy" && action != "flood" {
+		return fmt.Errorf("invalid unknown unicast action: %s", action)
+	}
+	bd.unknownUnicast = action
+	return nil
+}
+
+// ARPFlooding returns whether ARP flooding is enabled in the bridge domain.
+func (bd *BridgeDomain) ARPFlooding() bool {
+	return bd.arpFlooding
+}
+
+// SetARPFlooding sets whether ARP flooding is enabled in the bridge domain.
+func (bd *BridgeDomain) SetARPFlooding(enabled bool) {
+	bd.arpFlooding = enabled
+}
+
+// UnicastRouting returns whether unicast routing is enabled in the bridge domain.
+func (bd *BridgeDomain) UnicastRouting() bool {
+	return bd.unicastRouting
+}
+
+// SetUnicastRouting sets whether unicast routing is enabled in the bridge domain.
+func (bd *BridgeDomain) SetUnicastRouting(enabled bool) {
+	bd.unicastRouting = enabled
+}
+
+// L3Outs returns the names of the L3Outs the bridge domain is associated with.
+func (bd *BridgeDomain) L3Outs() []string {
+	return bd.l3Outs
+}
+
+// AddL3Out validates and associates the bridge domain with an L3Out,
+// so that its public subnets are advertised through it.
+func (bd *BridgeDomain) AddL3Out(l3Out string) error {
+	if err := validateName("l3out name", l3Out); err != nil {
+		return err
+	}
+	bd.l3Outs = appendName(bd.l3Outs, l3Out)
+	return nil
+}
+
+// Subnets returns the subnets of the bridge domain.
+func (bd *BridgeDomain) Subnets() []*Subnet {
+	return bd.subnets
+}
+
+// Subnet returns the subnet with the given gateway address,
+// or nil if there is none.
+func (bd *BridgeDomain) Subnet(gateway string) *Subnet {
+	for _, subnet := range bd.subnets {
+		if subnet.Gateway() == gateway {
+			return subnet
+		}
+	}
+	return nil
+}
+
+// AddSubnet adds a subnet to the bridge domain,
+// replacing any subnet with the same gateway address.
+func (bd *BridgeDomain) AddSubnet(subnet *Subnet) {
+	for i, sn := range bd.subnets {
+		if sn.Gateway() == subnet.Gateway() {
+			bd.subnets[i] = subnet
+			return
+		}
+	}
+	bd.subnets = append(bd.subnets, subnet)
+}
+
+// String returns the string representation of a bridge domain
+func (bd *BridgeDomain) String() string {
+	return fmt.Sprintf("%s/%s", bd.tenant, bd.name)
+}
+
+// Subnet is a gateway subnet of a bridge domain.
+type Subnet struct {
+	gateway     string
+	description string
+	scope       []string
+	preferred   bool
+	status      string
+}
+
+// Gateway returns the gateway address of the subnet, in CIDR notation.
+func (sn *Subnet) Gateway() string {
+	return sn.gateway
+}
+
+// SetGateway validates and sets the gateway address of the subnet.
+//
+// A gateway must be an IPv4 or IPv6 address with a prefix length,
+// such as "10.0.0.1/24". It can not be the network address of a
+// subnet, other than of a host route.
+func (sn *Subnet) SetGateway(gateway string) error {
+	ip, ipNet, err := net.ParseCIDR(gateway)
+	if err != nil {
+		return fmt.Errorf("invalid gateway: %s", gateway)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if ones < bits && ip.Equal(ipNet.IP) {
+		return fmt.Errorf("invalid gateway: %s", gateway)
+	}
+	sn.gateway = gateway
+	return nil
+}
+
+// Description returns the description of the subnet.
+func (sn *Subnet) Description() string {
+	return sn.description
+}
+
+// SetDescription validates and sets the description of the subnet.
+func (sn *Subnet) SetDescription(description string) error {
+	var o object
+	if err := o.SetDescription(description); err != nil {
+		return err
+	}
+	sn.description = description
+	return nil
+}
+
+// Scope returns the scope of the subnet.
+func (sn *Subnet) Scope() []string {
+	return sn.scope
+}
+
+// SetScope validates and sets the scope of the subnet.
+//
+// A scope is either "public" or "private", advertised externally
+// or not, optionally along with "shared" to leak it between VRFs.
+func (sn *Subnet) SetScope(scope ...string) error {
+	var public, private bool
+	for _, s := range scope {
+		switch s {
+		case "public":
+			public = true
+		case "private":
+			private = true
+		case "shared":
+		default:
+			return fmt.Errorf("invalid scope: %s", strings.Join(scope, ","))
+		}
+	}
+	if public && private {
+		return fmt.Errorf("invalid scope: %s", strings.Join(scope, ","))
+	}
+	if !public && !private {
+		scope = append([]string{"private"}, scope...)
+	}
+	sn.scope = scope
+	return nil
+}
+
+// Preferred returns whether the subnet is the preferred subnet
+// of the bridge domain.
+func (sn *Subnet) Preferred() bool {
+	return sn.preferred
+}
+
+// SetPreferred sets whether the subnet is the preferred subnet
+// of the bridge domain.
+func (sn *Subnet) SetPreferred(preferred bool) {
+	sn.preferred = preferred
+}
+
+// SetCreated sets the status of the subnet to "created,modified".
+func (sn *Subnet) SetCreated() string {
+	sn.status = createdModified
+	return sn.status
+}
+
+// SetDeleted sets the status of the subnet to "deleted".
+func (sn *Subnet) SetDeleted() string {
+	sn.status = deleted
+	return sn.status
+}
+
+// Status returns the status of the subnet.
+func (sn *Subnet) Status() string {
+	return sn.status
+}
+
+// String returns the string representation of a subnet
+func (sn *Subnet) String() string {
+	return fmt.Sprintf("%s %s", sn.gateway, strings.Join(sn.scope, ","))
+}
@@ -0,0 +1,236 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BridgeDomainContainer is a container for a bridge domain
+type BridgeDomainContainer struct {
+	FvBD `json:"fvBD"`
+}
+
+// FvBD is a bridge domain
+type FvBD struct {
+	BridgeDomainAttrs `json:"attributes"`
+	Children          []BridgeDomainChild `json:"children,omitempty"`
+}
+
+// BridgeDomainAttrs contains the attributes of a bridge domain
+type BridgeDomainAttrs struct {
+	ArpFlood       string `json:"arpFlood,omitempty"`
+	Descr          string `json:"descr,omitempty"`
+	DN             string `json:"dn,omitempty"`
+	Name           string `json:"name,omitempty"`
+	RN             string `json:"rn,omitempty"`
+	Status         string `json:"status,omitempty"`
+	UnicastRoute   string `json:"unicastRoute,omitempty"`
+	UnkMacUcastAct string `json:"unkMacUcastAct,omitempty"`
+}
+
+// BridgeDomainChild is a child of a bridge domain. Only one of its fields is set.
+type BridgeDomainChild struct {
+	RsCtx     *Relation `json:"fvRsCtx,omitempty"`
+	RsBDToOut *Relation `json:"fvRsBDToOut,omitempty"`
+	FvSubnet  *FvSubnet `json:"fvSubnet,omitempty"`
+}
+
+// FvSubnet is a bridge domain subnet
+type FvSubnet struct {
+	SubnetAttrs `json:"attributes"`
+}
+
+// SubnetAttrs contains the attributes of a subnet
+type SubnetAttrs struct {
+	Descr     string `json:"descr,omitempty"`
+	DN        string `json:"dn,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	Preferred string `json:"preferred,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// BridgeDomainResponse contains the response for bridge domain requests
+type BridgeDomainResponse struct {
+	TotalCount string                  `json:"totalCount"`
+	Imdata     []BridgeDomainContainer `json:"imdata"`
+}
+
+// BridgeDomainService handles communication with the bridge domain related
+// methods of the APIC API.
+type BridgeDomainService service
+
+// bridgeDomainDN returns the distinguished name of a bridge domain.
+func bridgeDomainDN(tenant, bd string) string {
+	return fmt.Sprintf("%s/BD-%s", tenantDN(tenant), bd)
+}
+
+// NewBridgeDomain instantiates a valid bridge domain within a tenant,
+// bound to the given VRF, with unicast routing enabled and unknown
+// unicast traffic sent to the spine proxy.
+func (s *BridgeDomainService) NewBridgeDomain(tenant, name, vrf string) (*BridgeDomain, error) {
+	bd := &BridgeDomain{unknownUnicast: "proxy", unicastRouting: true}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return bd, err
+	}
+	bd.tenant = tenant
+
+	if err := bd.SetName(name); err != nil {
+		return bd, err
+	}
+
+	if err := bd.SetVRF(vrf); err != nil {
+		return bd, err
+	}
+
+	return bd, nil
+}
+
+// NewSubnet instantiates a valid bridge domain subnet with the given
+// gateway address and scope. The scope defaults to "private".
+func (s *BridgeDomainService) NewSubnet(gateway string, scope ...string) (*Subnet, error) {
+	subnet := &Subnet{}
+
+	if err := subnet.SetGateway(gateway); err != nil {
+		return subnet, err
+	}
+
+	if err := subnet.SetScope(scope...); err != nil {
+		return subnet, err
+	}
+
+	return subnet, nil
+}
+
+func newBridgeDomainContainer(bd *BridgeDomain) BridgeDomainContainer {
+	dn := bridgeDomainDN(bd.Tenant(), bd.Name())
+	c := BridgeDomainContainer{
+		FvBD: FvBD{
+			BridgeDomainAttrs: BridgeDomainAttrs{
+				DN:             dn,
+				Name:           bd.Name(),
+				Descr:          bd.Description(),
+				ArpFlood:       yesNo(bd.ARPFlooding()),
+				UnicastRoute:   yesNo(bd.UnicastRouting()),
+				UnkMacUcastAct: bd.UnknownUnicast(),
+				Status:         bd.Status(),
+			},
+		},
+	}
+
+	// children are implicitly removed with their bridge domain
+	if bd.Status() == deleted {
+		return c
+	}
+
+	c.Children = append(c.Children, BridgeDomainChild{
+		RsCtx: newRelation(RelationAttrs{TnFvCtxName: bd.VRF()}),
+	})
+	for _, l3Out := range bd.L3Outs() {
+		c.Children = append(c.Children, BridgeDomainChild{
+			RsBDToOut: newRelation(RelationAttrs{TnL3extOutName: l3Out}),
+		})
+	}
+	for _, subnet := range bd.Subnets() {
+		c.Children = append(c.Children, BridgeDomainChild{
+			FvSubnet: &FvSubnet{
+				SubnetAttrs: SubnetAttrs{
+					DN:        fmt.Sprintf("%s/subnet-[%s]", dn, subnet.Gateway()),
+					IP:        subnet.Gateway(),
+					Descr:     subnet.Description(),
+					Scope:     strings.Join(subnet.Scope(), ","),
+					Preferred: yesNo(subnet.Preferred()),
+					Status:    subnet.Status(),
+				},
+			},
+		})
+	}
+
+	return c
+}
+
+// Create creates a bridge domain.
+func (s *BridgeDomainService) Create(ctx context.Context, bd *BridgeDomain) (BridgeDomainResponse, error) {
+	bd.SetCreated()
+	return s.Update(ctx, bd)
+}
+
+// Delete deletes a bridge domain.
+func (s *BridgeDomainService) Delete(ctx context.Context, bd *BridgeDomain) (BridgeDomainResponse, error) {
+	bd.SetDeleted()
+	return s.Update(ctx, bd)
+}
+
+// Update creates, modifies or deletes a bridge domain according to its
+// status, along with each of its subnets according to theirs.
+func (s *BridgeDomainService) Update(ctx context.Context, bd *BridgeDomain) (BridgeDomainResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", bridgeDomainDN(bd.Tenant(), bd.Name()))
+	payload := newBridgeDomainContainer(bd)
+
+	var br BridgeDomainResponse
+	err := s.client.post(ctx, path, payload, &br)
+	return br, err
+}
+
+// Get retrieves a bridge domain of a tenant.
+func (s *BridgeDomainService) Get(ctx context.Context, tenant, name string) (*BridgeDomain, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", bridgeDomainDN(tenant, name))
+
+	var br BridgeDomainResponse
+	if err := s.client.get(ctx, path, &br); err != nil {
+		return nil, fmt.Errorf("get bridge domain: %v", err)
+	}
+	if len(br.Imdata) == 0 {
+		return nil, fmt.Errorf("get bridge domain: %s/%s not found", tenant, name)
+	}
+	return bridgeDomainFromResponse(tenant, br.Imdata[0]), nil
+}
+
+// List lists all bridge domains of a tenant.
+func (s *BridgeDomainService) List(ctx context.Context, tenant string) ([]*BridgeDomain, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=fvBD&rsp-subtree=full", tenantDN(tenant))
+
+	var br BridgeDomainResponse
+	if err := s.client.get(ctx, path, &br); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var bds []*BridgeDomain
+	for _, b := range br.Imdata {
+		bds = append(bds, bridgeDomainFromResponse(tenant, b))
+	}
+	return bds, nil
+}
+
+func bridgeDomainFromResponse(tenant string, b BridgeDomainContainer) *BridgeDomain {
+	bd := &BridgeDomain{
+		object: object{
+			name:        b.Name,
+			description: b.Descr,
+			status:      b.Status,
+		},
+		tenant:         tenant,
+		unknownUnicast: b.UnkMacUcastAct,
+		arpFlooding:    b.ArpFlood == "yes",
+		unicastRouting: b.UnicastRoute == "yes",
+	}
+	for _, c := range b.Children {
+		switch {
+		case c.RsCtx != nil:
+			bd.vrf = c.RsCtx.TnFvCtxName
+		case c.RsBDToOut != nil:
+			bd.l3Outs = appendName(bd.l3Outs, c.RsBDToOut.TnL3extOutName)
+		case c.FvSubnet != nil:
+			bd.subnets = append(bd.subnets, &Subnet{
+				gateway:     c.FvSubnet.IP,
+				description: c.FvSubnet.Descr,
+				scope:       strings.Split(c.FvSubnet.Scope, ","),
+				preferred:   c.FvSubnet.Preferred == "yes",
+				status:      c.FvSubnet.Status,
+			})
+		}
+	}
+	return bd
+}
@@ -0,0 +1,105 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewSubnet(t *testing.T) {
+	tests := []struct {
+		gateway string
+		scope   []string
+		want    []string // nil if invalid
+	}{
+		{"10.0.0.1/24", nil, []string{"private"}},
+		{"10.0.0.1/24", []string{"public"}, []string{"public"}},
+		{"10.0.0.1/24", []string{"shared"}, []string{"private", "shared"}},
+		{"10.0.0.1/24", []string{"public", "shared"}, []string{"public", "shared"}},
+		{"10.0.0.1/32", nil, []string{"private"}},
+		{"10.0.0.0/32", nil, []string{"private"}},
+		{"2001:db8::1/64", nil, []string{"private"}},
+		{"10.0.0.0/24", nil, nil},
+		{"2001:db8::/64", nil, nil},
+		{"10.0.0.1", nil, nil},
+		{"10.0.0.256/24", nil, nil},
+		{"10.0.0.1/24", []string{"public", "private"}, nil},
+		{"10.0.0.1/24", []string{"global"}, nil},
+	}
+	s := &BridgeDomainService{}
+	for _, tt := range tests {
+		subnet, err := s.NewSubnet(tt.gateway, tt.scope...)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%s %v: expected an error", tt.gateway, tt.scope)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %v: %v", tt.gateway, tt.scope, err)
+			continue
+		}
+		if !reflect.DeepEqual(subnet.Scope(), tt.want) {
+			t.Errorf("%s %v: got scope %v, want %v", tt.gateway, tt.scope, subnet.Scope(), tt.want)
+		}
+	}
+}
+
+func TestBridgeDomainUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	bd, err := c.BridgeDomain.NewBridgeDomain("t1", "bd1", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bd.AddL3Out("out1"); err != nil {
+		t.Fatal(err)
+	}
+	web, err := c.BridgeDomain.NewSubnet("10.0.0.1/24", "public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	web.SetPreferred(true)
+	old, err := c.BridgeDomain.NewSubnet("10.1.0.1/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.SetDeleted()
+	bd.AddSubnet(web)
+	bd.AddSubnet(old)
+
+	if _, err := c.BridgeDomain.Create(context.Background(), bd); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted("/api/node/mo/uni/tn-t1/BD-bd1.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"fvBD":{"attributes":{"dn":"uni/tn-t1/BD-bd1","name":"bd1","arpFlood":"no","unicastRoute":"yes","unkMacUcastAct":"proxy","status":"created,modified"},"children":[
+		{"fvRsCtx":{"attributes":{"tnFvCtxName":"v1"}}},
+		{"fvRsBDToOut":{"attributes":{"tnL3extOutName":"out1"}}},
+		{"fvSubnet":{"attributes":{"dn":"uni/tn-t1/BD-bd1/subnet-[10.0.0.1/24]","ip":"10.0.0.1/24","scope":"public","preferred":"yes"}}},
+		{"fvSubnet":{"attributes":{"dn":"uni/tn-t1/BD-bd1/subnet-[10.1.0.1/24]","ip":"10.1.0.1/24","scope":"private","preferred":"no","status":"deleted"}}}
+	]}}`)
+}
+
+func TestBridgeDomainGet(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/tn-t1/BD-bd1.json": `{"imdata":[{"fvBD":{"attributes":{"name":"bd1","arpFlood":"yes","unicastRoute":"no","unkMacUcastAct":"flood"},"children":[
+			{"fvRsCtx":{"attributes":{"tnFvCtxName":"v1"}}},
+			{"fvSubnet":{"attributes":{"ip":"10.0.0.1/24","scope":"public,shared","preferred":"yes"}}}
+		]}}]}`,
+	})
+
+	bd, err := c.BridgeDomain.Get(context.Background(), "t1", "bd1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bd.String() != "t1/bd1" || bd.VRF() != "v1" || !bd.ARPFlooding() || bd.UnicastRouting() || bd.UnknownUnicast() != "flood" {
+		t.Errorf("got bridge domain %s vrf %s arp %t routing %t unknown unicast %s",
+			bd, bd.VRF(), bd.ARPFlooding(), bd.UnicastRouting(), bd.UnknownUnicast())
+	}
+	subnet := bd.Subnet("10.0.0.1/24")
+	if subnet == nil || subnet.String() != "10.0.0.1/24 public,shared" || !subnet.Preferred() {
+		t.Errorf("got subnet %v", subnet)
+	}
+}
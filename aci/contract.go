@@ -0,0 +1,282 @@
+package aci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Contract is a tenant contract, governing the traffic allowed
+// between the EPGs that provide and consume it.
+type Contract struct {
+	object
+	tenant   string
+	scope    string
+	subjects []*Subject
+}
+
+// Tenant returns the name of the tenant the contract belongs to.
+func (c *Contract) Tenant() string {
+	return c.tenant
+}
+
+// Scope returns the scope of the contract.
+func (c *Contract) Scope() string {
+	return c.scope
+}
+
+// SetScope sets the scope of the contract.
+// Can only be "context" (VRF), "tenant", "global" or "application-profile"
+func (c *Contract) SetScope(scope string) error {
+	switch scope {
+	case "context", "tenant", "global", "application-profile":
+	default:
+		return fmt.Errorf("invalid scope: %s", scope)
+	}
+	c.scope = scope
+	return nil
+}
+
+// Subjects returns the subjects of the contract.
+func (c *Contract) Subjects() []*Subject {
+	return c.subjects
+}
+
+// Subject returns the subject with the given name, or nil if there is none.
+func (c *Contract) Subject(name string) *Subject {
+	for _, subject := range c.subjects {
+		if subject.Name() == name {
+			return subject
+		}
+	}
+	return nil
+}
+
+// AddSubject adds a subject to the contract,
+// replacing any subject of the same name.
+func (c *Contract) AddSubject(subject *Subject) {
+	for i, s := range c.subjects {
+		if s.Name() == subject.Name() {
+			c.subjects[i] = subject
+			return
+		}
+	}
+	c.subjects = append(c.subjects, subject)
+}
+
+// String returns the string representation of a contract
+func (c *Contract) String() string {
+	return fmt.Sprintf("%s/%s", c.tenant, c.name)
+}
+
+// Subject is a contract subject, applying filters to the
+// traffic of a contract.
+type Subject struct {
+	object
+	reverseFilterPorts bool
+	filters            []string
+}
+
+// ReverseFilterPorts returns whether the filters of the subject are
+// also applied in reverse, to the return traffic.
+func (s *Subject) ReverseFilterPorts() bool {
+	return s.reverseFilterPorts
+}
+
+// SetReverseFilterPorts sets whether the filters of the subject are
+// also applied in reverse, to the return traffic.
+func (s *Subject) SetReverseFilterPorts(reverse bool) {
+	s.reverseFilterPorts = reverse
+}
+
+// Filters returns the names of the filters of the subject.
+func (s *Subject) Filters() []string {
+	return s.filters
+}
+
+// AddFilter validates and adds a filter to the subject.
+func (s *Subject) AddFilter(filter string) error {
+	if err := validateName("filter name", filter); err != nil {
+		return err
+	}
+	s.filters = appendName(s.filters, filter)
+	return nil
+}
+
+// Filter is a tenant filter, a set of entries classifying traffic.
+type Filter struct {
+	object
+	tenant  string
+	entries []*FilterEntry
+}
+
+// Tenant returns the name of the tenant the filter belongs to.
+func (f *Filter) Tenant() string {
+	return f.tenant
+}
+
+// Entries returns the entries of the filter.
+func (f *Filter) Entries() []*FilterEntry {
+	return f.entries
+}
+
+// AddEntry adds an entry to the filter,
+// replacing any entry of the same name.
+func (f *Filter) AddEntry(entry *FilterEntry) {
+	for i, e := range f.entries {
+		if e.Name() == entry.Name() {
+			f.entries[i] = entry
+			return
+		}
+	}
+	f.entries = append(f.entries, entry)
+}
+
+// String returns the string representation of a filter
+func (f *Filter) String() string {
+	return fmt.Sprintf("%s/%s", f.tenant, f.name)
+}
+
+// namedPorts are the port names the APIC accepts in place of port numbers.
+var namedPorts = map[string]int{
+	"ftpData": 20,
+	"ssh":     22,
+	"smtp":    25,
+	"dns":     53,
+	"http":    80,
+	"pop3":    110,
+	"https":   443,
+	"rtsp":    554,
+}
+
+// FilterEntry is an entry of a filter, matching traffic by ethertype,
+// IP protocol and layer 4 port ranges.
+type FilterEntry struct {
+	object
+	etherType string
+	protocol  string
+	srcFrom   string
+	srcTo     string
+	dstFrom   string
+	dstTo     string
+}
+
+// EtherType returns the ethertype matched by the entry.
+func (e *FilterEntry) EtherType() string {
+	return e.etherType
+}
+
+// SetEtherType sets the ethertype matched by the entry.
+// Can only be "unspecified", "ip", "ipv4", "ipv6", "arp", "fcoe",
+// "mpls_ucast", "mac_security" or "trill"
+func (e *FilterEntry) SetEtherType(etherType string) error {
+	switch etherType {
+	case "unspecified", "ip", "ipv4", "ipv6", "arp", "fcoe", "mpls_ucast", "mac_security", "trill":
+	default:
+		return fmt.Errorf("invalid ethertype: %s", etherType)
+	}
+	e.etherType = etherType
+	return nil
+}
+
+// Protocol returns the IP protocol matched by the entry.
+func (e *FilterEntry) Protocol() string {
+	return e.protocol
+}
+
+// SetProtocol sets the IP protocol matched by the entry.
+// Can only be "unspecified", "tcp", "udp", "icmp", "icmpv6",
+// "igmp", "eigrp", "ospfigp", "pim" or "l2tp", and only with
+// an IP ethertype.
+func (e *FilterEntry) SetProtocol(protocol string) error {
+	switch protocol {
+	case "unspecified":
+	case "tcp", "udp", "icmp", "icmpv6", "igmp", "eigrp", "ospfigp", "pim", "l2tp":
+		if e.etherType != "ip" && e.etherType != "ipv4" && e.etherType != "ipv6" {
+			return fmt.Errorf("invalid protocol: %s with ethertype %s", protocol, e.etherType)
+		}
+	default:
+		return fmt.Errorf("invalid protocol: %s", protocol)
+	}
+	e.protocol = protocol
+	return nil
+}
+
+// SourcePorts returns the source port range matched by the entry.
+func (e *FilterEntry) SourcePorts() (string, string) {
+	return e.srcFrom, e.srcTo
+}
+
+// SetSourcePorts validates and sets the source port range matched by the entry.
+func (e *FilterEntry) SetSourcePorts(from, to string) error {
+	if err := e.validatePorts(from, to); err != nil {
+		return err
+	}
+	e.srcFrom, e.srcTo = from, to
+	return nil
+}
+
+// DestinationPorts returns the destination port range matched by the entry.
+func (e *FilterEntry) DestinationPorts() (string, string) {
+	return e.dstFrom, e.dstTo
+}
+
+// SetDestinationPorts validates and sets the destination port range
+// matched by the entry.
+func (e *FilterEntry) SetDestinationPorts(from, to string) error {
+	if err := e.validatePorts(from, to); err != nil {
+		return err
+	}
+	e.dstFrom, e.dstTo = from, to
+	return nil
+}
+
+// validatePorts validates a port range. Ports are numbers between 0 and
+// 65535, or one of the port names the APIC accepts, such as "https".
+// Ports can only be matched for the TCP and UDP protocols, and the
+// range must not run backwards.
+func (e *FilterEntry) validatePorts(from, to string) error {
+	if e.protocol != "tcp" && e.protocol != "udp" {
+		return fmt.Errorf("invalid port range: %s-%s with protocol %s", from, to, e.protocol)
+	}
+	f, err := portNumber(from)
+	if err != nil {
+		return err
+	}
+	t, err := portNumber(to)
+	if err != nil {
+		return err
+	}
+	if f > t {
+		return fmt.Errorf("invalid port range: %s-%s", from, to)
+	}
+	return nil
+}
+
+// portNumber returns the number of a named or numbered port.
+func portNumber(port string) (int, error) {
+	if n, ok := namedPorts[port]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 0 || n > 65535 {
+		return 0, fmt.Errorf("invalid port: %s", port)
+	}
+	return n, nil
+}
+
+// String returns the string representation of a filter entry
+func (e *FilterEntry) String() string {
+	s := []string{e.etherType}
+	if e.protocol != "" && e.protocol != "unspecified" {
+		s = append(s, e.protocol)
+	}
+	if e.dstFrom != "" {
+		if e.dstFrom == e.dstTo {
+			s = append(s, e.dstFrom)
+		} else {
+			s = append(s, e.dstFrom+"-"+e.dstTo)
+		}
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,524 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ContractContainer is a container for a contract
+type ContractContainer struct {
+	VzBrCP `json:"vzBrCP"`
+}
+
+// VzBrCP is a contract
+type VzBrCP struct {
+	ContractAttrs `json:"attributes"`
+	Children      []SubjectContainer `json:"children,omitempty"`
+}
+
+// SubjectContainer is a child of a contract. Only its subject is decoded,
+// so that the reverse relations of the EPGs providing and consuming the
+// contract, also returned as its children, are left nil.
+type SubjectContainer struct {
+	VzSubj *VzSubj `json:"vzSubj,omitempty"`
+}
+
+// VzSubj is a contract subject
+type VzSubj struct {
+	ContractAttrs `json:"attributes"`
+	Children      []SubjectChild `json:"children,omitempty"`
+}
+
+// SubjectChild is a child of a contract subject. Only one of its fields is set.
+type SubjectChild struct {
+	RsSubjFiltAtt *Relation `json:"vzRsSubjFiltAtt,omitempty"`
+}
+
+// FilterContainer is a container for a filter
+type FilterContainer struct {
+	VzFilter `json:"vzFilter"`
+}
+
+// VzFilter is a filter
+type VzFilter struct {
+	ContractAttrs `json:"attributes"`
+	Children      []FilterEntryContainer `json:"children,omitempty"`
+}
+
+// FilterEntryContainer is a child of a filter. Only its entry is decoded,
+// so that the reverse relations of the subjects using the filter, also
+// returned as its children, are left nil.
+type FilterEntryContainer struct {
+	VzEntry *VzEntry `json:"vzEntry,omitempty"`
+}
+
+// VzEntry is a filter entry
+type VzEntry struct {
+	ContractAttrs `json:"attributes"`
+}
+
+// ContractAttrs contains the attributes of contracts, subjects,
+// filters and filter entries
+type ContractAttrs struct {
+	DFromPort   string `json:"dFromPort,omitempty"`
+	DToPort     string `json:"dToPort,omitempty"`
+	Descr       string `json:"descr,omitempty"`
+	DN          string `json:"dn,omitempty"`
+	EtherT      string `json:"etherT,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Prot        string `json:"prot,omitempty"`
+	RevFltPorts string `json:"revFltPorts,omitempty"`
+	RN          string `json:"rn,omitempty"`
+	SFromPort   string `json:"sFromPort,omitempty"`
+	SToPort     string `json:"sToPort,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Status      string `json:"status,omitempty"`
+}
+
+// ContractResponse contains the response for contract requests
+type ContractResponse struct {
+	TotalCount string              `json:"totalCount"`
+	Imdata     []ContractContainer `json:"imdata"`
+}
+
+// FilterResponse contains the response for filter requests
+type FilterResponse struct {
+	TotalCount string            `json:"totalCount"`
+	Imdata     []FilterContainer `json:"imdata"`
+}
+
+// ContractService handles communication with the contract and filter
+// related methods of the APIC API.
+type ContractService service
+
+// contractDN returns the distinguished name of a contract.
+func contractDN(tenant, contract string) string {
+	return fmt.Sprintf("%s/brc-%s", tenantDN(tenant), contract)
+}
+
+// filterDN returns the distinguished name of a filter.
+func filterDN(tenant, filter string) string {
+	return fmt.Sprintf("%s/flt-%s", tenantDN(tenant), filter)
+}
+
+// NewContract instantiates a valid contract within a tenant,
+// scoped to the VRF.
+func (s *ContractService) NewContract(tenant, name string) (*Contract, error) {
+	contract := &Contract{scope: "context"}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return contract, err
+	}
+	contract.tenant = tenant
+
+	if err := contract.SetName(name); err != nil {
+		return contract, err
+	}
+
+	return contract, nil
+}
+
+// NewSubject instantiates a valid contract subject applying the given
+// filters, both to traffic and, with reversed ports, to its return traffic.
+func (s *ContractService) NewSubject(name string, filters ...string) (*Subject, error) {
+	subject := &Subject{reverseFilterPorts: true}
+
+	if err := subject.SetName(name); err != nil {
+		return subject, err
+	}
+
+	for _, filter := range filters {
+		if err := subject.AddFilter(filter); err != nil {
+			return subject, err
+		}
+	}
+
+	return subject, nil
+}
+
+// NewFilter instantiates a valid filter within a tenant.
+func (s *ContractService) NewFilter(tenant, name string) (*Filter, error) {
+	filter := &Filter{}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return filter, err
+	}
+	filter.tenant = tenant
+
+	if err := filter.SetName(name); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// NewFilterEntry instantiates a valid filter entry matching the given
+// ethertype and IP protocol.
+func (s *ContractService) NewFilterEntry(name, etherType, protocol string) (*FilterEntry, error) {
+	entry := &FilterEntry{}
+
+	if err := entry.SetName(name); err != nil {
+		return entry, err
+	}
+
+	if err := entry.SetEtherType(etherType); err != nil {
+		return entry, err
+	}
+
+	if err := entry.SetProtocol(protocol); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+func newContractContainer(contract *Contract) ContractContainer {
+	dn := contractDN(contract.Tenant(), contract.Name())
+	c := ContractContainer{
+		VzBrCP: VzBrCP{
+			ContractAttrs: ContractAttrs{
+				DN:     dn,
+				Name:   contract.Name(),
+				Descr:  contract.Description(),
+				Scope:  contract.Scope(),
+				Status: contract.Status(),
+			},
+		},
+	}
+
+	// children are implicitly removed with their contract
+	if contract.Status() == deleted {
+		return c
+	}
+
+	for _, subject := range contract.Subjects() {
+		subj := &VzSubj{
+			ContractAttrs: ContractAttrs{
+				DN:          fmt.Sprintf("%s/subj-%s", dn, subject.Name()),
+				Name:        subject.Name(),
+				Descr:       subject.Description(),
+				RevFltPorts: yesNo(subject.ReverseFilterPorts()),
+				Status:      subject.Status(),
+			},
+		}
+		for _, filter := range subject.Filters() {
+			subj.Children = append(subj.Children, SubjectChild{
+				RsSubjFiltAtt: newRelation(RelationAttrs{TnVzFilterName: filter}),
+			})
+		}
+		c.Children = append(c.Children, SubjectContainer{VzSubj: subj})
+	}
+
+	return c
+}
+
+// Create creates a contract.
+func (s *ContractService) Create(ctx context.Context, contract *Contract) (ContractResponse, error) {
+	contract.SetCreated()
+	return s.Update(ctx, contract)
+}
+
+// Delete deletes a contract.
+func (s *ContractService) Delete(ctx context.Context, contract *Contract) (ContractResponse, error) {
+	contract.SetDeleted()
+	return s.Update(ctx, contract)
+}
+
+// Update creates, modifies or deletes a contract according to its
+// status, along with each of its subjects according to theirs.
+func (s *ContractService) Update(ctx context.Context, contract *Contract) (ContractResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", contractDN(contract.Tenant(), contract.Name()))
+	payload := newContractContainer(contract)
+
+	var cr ContractResponse
+	err := s.client.post(ctx, path, payload, &cr)
+	return cr, err
+}
+
+// Get retrieves a contract of a tenant.
+func (s *ContractService) Get(ctx context.Context, tenant, name string) (*Contract, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", contractDN(tenant, name))
+
+	var cr ContractResponse
+	if err := s.client.get(ctx, path, &cr); err != nil {
+		return nil, fmt.Errorf("get contract: %v", err)
+	}
+	if len(cr.Imdata) == 0 {
+		return nil, fmt.Errorf("get contract: %s/%s not found", tenant, name)
+	}
+	return contractFromResponse(tenant, cr.Imdata[0]), nil
+}
+
+// List lists all contracts of a tenant.
+func (s *ContractService) List(ctx context.Context, tenant string) ([]*Contract, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=vzBrCP&rsp-subtree=full", tenantDN(tenant))
+
+	var cr ContractResponse
+	if err := s.client.get(ctx, path, &cr); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var contracts []*Contract
+	for _, c := range cr.Imdata {
+		contracts = append(contracts, contractFromResponse(tenant, c))
+	}
+	return contracts, nil
+}
+
+func contractFromResponse(tenant string, c ContractContainer) *Contract {
+	contract := &Contract{
+		object: object{
+			name:        c.Name,
+			description: c.Descr,
+			status:      c.Status,
+		},
+		tenant: tenant,
+		scope:  c.Scope,
+	}
+	for _, child := range c.Children {
+		sc := child.VzSubj
+		if sc == nil {
+			continue
+		}
+		subject := &Subject{
+			object: object{
+				name:        sc.Name,
+				description: sc.Descr,
+				status:      sc.Status,
+			},
+			reverseFilterPorts: sc.RevFltPorts == "yes",
+		}
+		for _, r := range sc.Children {
+			if r.RsSubjFiltAtt != nil {
+				subject.filters = appendName(subject.filters, r.RsSubjFiltAtt.TnVzFilterName)
+			}
+		}
+		contract.subjects = append(contract.subjects, subject)
+	}
+	return contract
+}
+
+func newFilterContainer(filter *Filter) FilterContainer {
+	dn := filterDN(filter.Tenant(), filter.Name())
+	c := FilterContainer{
+		VzFilter: VzFilter{
+			ContractAttrs: ContractAttrs{
+				DN:     dn,
+				Name:   filter.Name(),
+				Descr:  filter.Description(),
+				Status: filter.Status(),
+			},
+		},
+	}
+
+	// children are implicitly removed with their filter
+	if filter.Status() == deleted {
+		return c
+	}
+
+	for _, entry := range filter.Entries() {
+		sFrom, sTo := entry.SourcePorts()
+		dFrom, dTo := entry.DestinationPorts()
+		c.Children = append(c.Children, FilterEntryContainer{
+			VzEntry: &VzEntry{
+				ContractAttrs: ContractAttrs{
+					DN:        fmt.Sprintf("%s/e-%s", dn, entry.Name()),
+					Name:      entry.Name(),
+					Descr:     entry.Description(),
+					EtherT:    entry.EtherType(),
+					Prot:      entry.Protocol(),
+					SFromPort: sFrom,
+					SToPort:   sTo,
+					DFromPort: dFrom,
+					DToPort:   dTo,
+					Status:    entry.Status(),
+				},
+			},
+		})
+	}
+
+	return c
+}
+
+// CreateFilter creates a filter.
+func (s *ContractService) CreateFilter(ctx context.Context, filter *Filter) (FilterResponse, error) {
+	filter.SetCreated()
+	return s.UpdateFilter(ctx, filter)
+}
+
+// DeleteFilter deletes a filter.
+func (s *ContractService) DeleteFilter(ctx context.Context, filter *Filter) (FilterResponse, error) {
+	filter.SetDeleted()
+	return s.UpdateFilter(ctx, filter)
+}
+
+// UpdateFilter creates, modifies or deletes a filter according to its
+// status, along with each of its entries according to theirs.
+func (s *ContractService) UpdateFilter(ctx context.Context, filter *Filter) (FilterResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", filterDN(filter.Tenant(), filter.Name()))
+	payload := newFilterContainer(filter)
+
+	var fr FilterResponse
+	err := s.client.post(ctx, path, payload, &fr)
+	return fr, err
+}
+
+// GetFilter retrieves a filter of a tenant.
+func (s *ContractService) GetFilter(ctx context.Context, tenant, name string) (*Filter, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", filterDN(tenant, name))
+
+	var fr FilterResponse
+	if err := s.client.get(ctx, path, &fr); err != nil {
+		return nil, fmt.Errorf("get filter: %v", err)
+	}
+	if len(fr.Imdata) == 0 {
+		return nil, fmt.Errorf("get filter: %s/%s not found", tenant, name)
+	}
+	return filterFromResponse(tenant, fr.Imdata[0]), nil
+}
+
+// ListFilters lists all filters of a tenant.
+func (s *ContractService) ListFilters(ctx context.Context, tenant string) ([]*Filter, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=vzFilter&rsp-subtree=full", tenantDN(tenant))
+
+	var fr FilterResponse
+	if err := s.client.get(ctx, path, &fr); err != nil {
+		return nil, fmt.Errorf("list filters: %v", err)
+	}
+
+	var filters []*Filter
+	for _, f := range fr.Imdata {
+		filters = append(filters, filterFromResponse(tenant, f))
+	}
+	return filters, nil
+}
+
+func filterFromResponse(tenant string, f FilterContainer) *Filter {
+	filter := &Filter{
+		object: object{
+			name:        f.Name,
+			description: f.Descr,
+			status:      f.Status,
+		},
+		tenant: tenant,
+	}
+	for _, child := range f.Children {
+		e := child.VzEntry
+		if e == nil {
+			continue
+		}
+		filter.entries = append(filter.entries, &FilterEntry{
+			object: object{
+				name:        e.Name,
+				description: e.Descr,
+				status:      e.Status,
+			},
+			etherType: e.EtherT,
+			protocol:  e.Prot,
+			srcFrom:   e.SFromPort,
+			srcTo:     e.SToPort,
+			dstFrom:   e.DFromPort,
+			dstTo:     e.DToPort,
+		})
+	}
+	return filter
+}
+
+// Flow is traffic permitted by a contract, from the EPGs
+// that consume it to the EPGs that provide it.
+type Flow struct {
+	Consumer string // "<ap>/<epg>", or "<vrf>/any" for a vzAny
+	Provider string
+	Contract string
+	Entries  []*FilterEntry
+}
+
+// String returns the string representation of a flow
+func (f Flow) String() string {
+	return fmt.Sprintf("%s -> %s (%s): %v", f.Consumer, f.Provider, f.Contract, f.Entries)
+}
+
+// Flows returns the traffic permitted between the EPGs of a tenant,
+// which EPGs can talk to which on what ports, by the contracts they
+// provide and consume, including those of each VRF's vzAny.
+func (s *ContractService) Flows(ctx context.Context, tenant string) ([]Flow, error) {
+	filters, err := s.ListFilters(ctx, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("flows: %v", err)
+	}
+	entries := make(map[string][]*FilterEntry)
+	for _, filter := range filters {
+		entries[filter.Name()] = filter.Entries()
+	}
+
+	contracts, err := s.List(ctx, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("flows: %v", err)
+	}
+
+	providers := make(map[string][]string)
+	consumers := make(map[string][]string)
+
+	aps, err := s.client.ApplicationProfile.List(ctx, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("flows: %v", err)
+	}
+	for _, ap := range aps {
+		epgs, err := s.client.ApplicationProfile.ListEPGs(ctx, tenant, ap.Name())
+		if err != nil {
+			return nil, fmt.Errorf("flows: %v", err)
+		}
+		for _, epg := range epgs {
+			name := fmt.Sprintf("%s/%s", ap.Name(), epg.Name())
+			for _, c := range epg.ProvidedContracts() {
+				providers[c] = append(providers[c], name)
+			}
+			for _, c := range epg.ConsumedContracts() {
+				consumers[c] = append(consumers[c], name)
+			}
+		}
+	}
+
+	vrfs, err := s.client.VRF.List(ctx, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("flows: %v", err)
+	}
+	for _, vrf := range vrfs {
+		name := fmt.Sprintf("%s/any", vrf.Name())
+		for _, c := range vrf.ProvidedContracts() {
+			providers[c] = append(providers[c], name)
+		}
+		for _, c := range vrf.ConsumedContracts() {
+			consumers[c] = append(consumers[c], name)
+		}
+	}
+
+	var flows []Flow
+	for _, contract := range contracts {
+		var es []*FilterEntry
+		for _, subject := range contract.Subjects() {
+			for _, filter := range subject.Filters() {
+				es = append(es, entries[filter]...)
+			}
+		}
+		for _, consumer := range consumers[contract.Name()] {
+			for _, provider := range providers[contract.Name()] {
+				flows = append(flows, Flow{
+					Consumer: consumer,
+					Provider: provider,
+					Contract: contract.Name(),
+					Entries:  es,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool {
+		if flows[i].Consumer != flows[j].Consumer {
+			return flows[i].Consumer < flows[j].Consumer
+		}
+		return flows[i].Provider < flows[j].Provider
+	})
+	return flows, nil
+}
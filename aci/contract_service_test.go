@@ -0,0 +1,91 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// The fixtures below are responses of the APIC to queries with
+// rsp-subtree=full, which include the reverse relations of the EPGs,
+// vzAny and subjects using a contract or filter.
+
+const contractFixture = `{"totalCount":"1","imdata":[{"vzBrCP":{"attributes":{
+	"annotation":"","childAction":"","descr":"web traffic","dn":"uni/tn-t1/brc-web",
+	"intent":"install","lcOwn":"local","modTs":"2021-03-04T10:12:31.882+00:00","name":"web",
+	"nameAlias":"","ownerKey":"","ownerTag":"","prio":"unspecified","scope":"tenant",
+	"status":"","targetDscp":"unspecified","uid":"15374"},"children":[
+	{"vzRtProv":{"attributes":{"childAction":"","lcOwn":"local","modTs":"2021-03-04T10:14:02.120+00:00",
+		"rn":"rtfvProv-[uni/tn-t1/ap-app/epg-web]","status":"","tCl":"fvAEPg","tDn":"uni/tn-t1/ap-app/epg-web"}}},
+	{"vzRtCons":{"attributes":{"childAction":"","lcOwn":"local","modTs":"2021-03-04T10:14:09.415+00:00",
+		"rn":"rtfvCons-[uni/tn-t1/ap-app/epg-app]","status":"","tCl":"fvAEPg","tDn":"uni/tn-t1/ap-app/epg-app"}}},
+	{"vzSubj":{"attributes":{"annotation":"","childAction":"","consMatchT":"AtleastOne","descr":"",
+		"lcOwn":"local","matchT":"AtleastOne","modTs":"2021-03-04T10:12:31.882+00:00","name":"http",
+		"nameAlias":"","prio":"unspecified","provMatchT":"AtleastOne","revFltPorts":"yes","rn":"subj-http",
+		"status":"","targetDscp":"unspecified","uid":"15374"},"children":[
+		{"vzRsSubjFiltAtt":{"attributes":{"action":"permit","annotation":"","childAction":"",
+			"directives":"","forceResolve":"yes","lcOwn":"local","modTs":"2021-03-04T10:12:31.882+00:00",
+			"monPolDn":"uni/tn-common/monepg-default","priorityOverride":"default","rType":"mo",
+			"rn":"rssubjFiltAtt-http","state":"formed","stateQual":"none","status":"","tCl":"vzFilter",
+			"tContextDn":"","tDn":"uni/tn-t1/flt-http","tRn":"flt-http","tType":"name","tnVzFilterName":"http",
+			"uid":"15374"}}}
+	]}},
+	{"vzRtAnyToProv":{"attributes":{"childAction":"","lcOwn":"local","modTs":"2021-03-04T10:15:44.871+00:00",
+		"rn":"rtanyToProv-[uni/tn-t1/ctx-vrf1/any]","status":"","tCl":"vzAny","tDn":"uni/tn-t1/ctx-vrf1/any"}}}
+]}}]}`
+
+const filterFixture = `{"totalCount":"1","imdata":[{"vzFilter":{"attributes":{
+	"annotation":"","childAction":"","descr":"","dn":"uni/tn-t1/flt-http","fwdId":"27",
+	"id":"implicit","lcOwn":"local","modTs":"2021-03-04T10:12:31.882+00:00","name":"http",
+	"nameAlias":"","ownerKey":"","ownerTag":"","revId":"28","status":"","txId":"7493989779944",
+	"uid":"15374"},"children":[
+	{"vzRtSubjFiltAtt":{"attributes":{"childAction":"","lcOwn":"local","modTs":"2021-03-04T10:12:31.882+00:00",
+		"rn":"rtsubjFiltAtt-[uni/tn-t1/brc-web/subj-http]","status":"","tCl":"vzSubj",
+		"tDn":"uni/tn-t1/brc-web/subj-http"}}},
+	{"vzEntry":{"attributes":{"annotation":"","applyToFrag":"no","arpOpc":"unspecified",
+		"childAction":"","dFromPort":"http","dToPort":"http","descr":"","etherT":"ip","icmpv4T":"unspecified",
+		"icmpv6T":"unspecified","lcOwn":"local","matchDscp":"unspecified","modTs":"2021-03-04T10:12:31.882+00:00",
+		"name":"http","nameAlias":"","prot":"tcp","rn":"e-http","sFromPort":"unspecified",
+		"sToPort":"unspecified","stateful":"no","status":"","tcpRules":"","uid":"15374"}}}
+]}}]}`
+
+func TestGetContract(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{"/api/node/mo/uni/tn-t1/brc-web.json": contractFixture})
+
+	contract, err := c.Contract.Get(context.Background(), "t1", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contract.Name() != "web" || contract.Description() != "web traffic" || contract.Scope() != "tenant" {
+		t.Errorf("got contract %s (%s), scope %s", contract.Name(), contract.Description(), contract.Scope())
+	}
+	subjects := contract.Subjects()
+	if len(subjects) != 1 {
+		t.Fatalf("got %d subjects, want 1: %v", len(subjects), subjects)
+	}
+	subject := subjects[0]
+	if subject.Name() != "http" || !subject.ReverseFilterPorts() {
+		t.Errorf("got subject %s, reverse filter ports %v", subject.Name(), subject.ReverseFilterPorts())
+	}
+	if got, want := subject.Filters(), []string{"http"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got filters %v, want %v", got, want)
+	}
+}
+
+func TestGetFilter(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{"/api/node/mo/uni/tn-t1/flt-http.json": filterFixture})
+
+	filter, err := c.Contract.GetFilter(context.Background(), "t1", "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := filter.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %v", len(entries), entries)
+	}
+	e := entries[0]
+	from, to := e.DestinationPorts()
+	if e.Name() != "http" || e.EtherType() != "ip" || e.Protocol() != "tcp" || from != "http" || to != "http" {
+		t.Errorf("got entry %s", e)
+	}
+}
@@ -0,0 +1,380 @@
+package aci
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// NodeLocation is the geolocation of a fabric node,
+// the path to the rack it is installed in.
+type NodeLocation struct {
+	Site     string
+	Building string
+	Floor    string
+	Room     string
+	Row      string
+	Rack     string
+}
+
+// String returns the string representation of a node location
+func (l NodeLocation) String() string {
+	var parts []string
+	for _, p := range []struct{ prefix, name string }{
+		{"site", l.Site},
+		{"building", l.Building},
+		{"floor", l.Floor},
+		{"room", l.Room},
+		{"row", l.Row},
+		{"rack", l.Rack},
+	} {
+		if p.name != "" {
+			parts = append(parts, fmt.Sprintf("%s-%s", p.prefix, p.name))
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Diagram is a drawing of the ACI fabric. Nodes are clustered by pod,
+// site, room and rack, and coloured by role and fabric state.
+type Diagram struct {
+	Nodes []*Node
+	Links []*Link
+
+	// Locations holds the geolocation of each node, keyed by node id.
+	// Nodes without a location are clustered by pod only.
+	Locations map[string]NodeLocation
+}
+
+// NewDiagram returns a diagram of the nodes and links of a topology.
+// Each link between two fabric nodes is drawn once.
+func NewDiagram(t *Topology) *Diagram {
+	d := &Diagram{
+		Nodes:     t.Nodes(),
+		Locations: make(map[string]NodeLocation),
+	}
+	seen := make(map[string]bool)
+	for _, node := range d.Nodes {
+		for _, l := range t.Links(node.ID()) {
+			key := []string{l.Local.String(), l.Remote.String()}
+			sort.Strings(key)
+			if seen[strings.Join(key, " ")] {
+				continue
+			}
+			seen[strings.Join(key, " ")] = true
+			d.Links = append(d.Links, l)
+		}
+	}
+	return d
+}
+
+// Diagram discovers the topology of the ACI fabric and returns a diagram
+// of it, with nodes located in the racks they are installed in.
+func (s *TopologyService) Diagram(ctx context.Context) (*Diagram, error) {
+	t, err := s.Discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("diagram: %v", err)
+	}
+	d := NewDiagram(t)
+	d.Locations, err = s.client.Geolocation.NodeLocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("diagram: %v", err)
+	}
+	return d, nil
+}
+
+// roleColors are the fill colours of nodes by role.
+var roleColors = map[string]string{
+	"spine":      "#aec7e8",
+	"leaf":       "#98df8a",
+	"controller": "#c5b0d5",
+}
+
+// stateColors are the border colours of nodes by fabric state.
+var stateColors = map[string]string{
+	FabricStateActive:         "#2ca02c",
+	FabricStateInactive:       "#d62728",
+	FabricStateDecommissioned: "#7f7f7f",
+	FabricStateDisabled:       "#7f7f7f",
+	FabricStateDiscovering:    "#ff7f0e",
+	FabricStateUndiscovered:   "#ff7f0e",
+}
+
+func roleColor(n *Node) string {
+	if c, ok := roleColors[n.Role()]; ok {
+		return c
+	}
+	return "#dddddd"
+}
+
+func stateColor(n *Node) string {
+	if c, ok := stateColors[n.FabricState()]; ok {
+		return c
+	}
+	return "#000000"
+}
+
+// diagramCluster is a group of nodes drawn together,
+// such as a pod or a rack.
+type diagramCluster struct {
+	id       string // valid as a diagram identifier
+	label    string
+	clusters []*diagramCluster
+	nodes    []*Node
+}
+
+func (c *diagramCluster) cluster(id, label string) *diagramCluster {
+	for _, cc := range c.clusters {
+		if cc.id == id {
+			return cc
+		}
+	}
+	cc := &diagramCluster{id: id, label: label}
+	c.clusters = append(c.clusters, cc)
+	return cc
+}
+
+// clusters groups the nodes of the diagram by pod, site, room and rack.
+func (d *Diagram) clusters() *diagramCluster {
+	root := &diagramCluster{}
+	nodes := append([]*Node(nil), d.Nodes...)
+	sortNodes(nodes)
+
+	for _, node := range nodes {
+		c := root
+		id := "pod_" + node.Pod()
+		c = c.cluster(id, "pod-"+node.Pod())
+
+		loc, ok := d.Locations[node.ID()]
+		if ok && loc.Site != "" {
+			id += "_site_" + sanitizeID(loc.Site)
+			c = c.cluster(id, "site "+loc.Site)
+			if loc.Room != "" {
+				id += "_" + sanitizeID(loc.Building) + "_" + sanitizeID(loc.Floor) + "_" + sanitizeID(loc.Room)
+				c = c.cluster(id, fmt.Sprintf("room %s/%s/%s", loc.Building, loc.Floor, loc.Room))
+				if loc.Rack != "" {
+					id += "_" + sanitizeID(loc.Row) + "_" + sanitizeID(loc.Rack)
+					c = c.cluster(id, fmt.Sprintf("rack %s/%s", loc.Row, loc.Rack))
+				}
+			}
+		}
+		c.nodes = append(c.nodes, node)
+	}
+
+	var sortClusters func(c *diagramCluster)
+	sortClusters = func(c *diagramCluster) {
+		sort.Slice(c.clusters, func(i, j int) bool {
+			return c.clusters[i].id < c.clusters[j].id
+		})
+		for _, cc := range c.clusters {
+			sortClusters(cc)
+		}
+	}
+	sortClusters(root)
+	return root
+}
+
+// externals returns the external endpoints of the diagram links, keyed by
+// diagram id, in the order they are first linked.
+func (d *Diagram) externals() ([]string, map[string]Endpoint) {
+	var ids []string
+	eps := make(map[string]Endpoint)
+	for _, l := range d.Links {
+		if !l.Remote.External() {
+			continue
+		}
+		id := externalID(l.Remote)
+		if _, ok := eps[id]; !ok {
+			ids = append(ids, id)
+			eps[id] = l.Remote
+		}
+	}
+	return ids, eps
+}
+
+// sanitizeID makes s valid as part of a diagram identifier, replacing
+// every character other than a letter or digit, underscores included,
+// with its hex code between underscores. Distinct names, such as "srv-1"
+// and "srv_1", always give distinct identifiers, and underscores can be
+// used to join sanitized names.
+func sanitizeID(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			continue
+		}
+		fmt.Fprintf(&b, "_%x_", r)
+	}
+	return b.String()
+}
+
+func nodeID(n *Node) string {
+	return "node_" + sanitizeID(n.ID())
+}
+
+func endpointID(e Endpoint) string {
+	if e.External() {
+		return externalID(e)
+	}
+	return "node_" + sanitizeID(e.NodeID)
+}
+
+func externalID(e Endpoint) string {
+	return "ext_" + sanitizeID(e.Name)
+}
+
+func nodeLabel(n *Node) string {
+	if n.Name() == "" {
+		return n.ID()
+	}
+	return fmt.Sprintf("%s (%s)", n.Name(), n.ID())
+}
+
+// WriteDOT writes the diagram in the Graphviz DOT language.
+func (d *Diagram) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph fabric {")
+	fmt.Fprintln(bw, "\tnode [shape=box, style=\"filled,bold\"];")
+
+	var write func(c *diagramCluster, indent string)
+	write = func(c *diagramCluster, indent string) {
+		for _, cc := range c.clusters {
+			fmt.Fprintf(bw, "%ssubgraph cluster_%s {\n", indent, cc.id)
+			fmt.Fprintf(bw, "%s\tlabel=%q;\n", indent, cc.label)
+			write(cc, indent+"\t")
+			fmt.Fprintf(bw, "%s}\n", indent)
+		}
+		for _, n := range c.nodes {
+			fmt.Fprintf(bw, "%s%s [label=%q, fillcolor=%q, color=%q];\n",
+				indent, nodeID(n), nodeLabel(n), roleColor(n), stateColor(n))
+		}
+	}
+	write(d.clusters(), "\t")
+
+	ids, eps := d.externals()
+	for _, id := range ids {
+		fmt.Fprintf(bw, "\t%s [label=%q, shape=ellipse, style=dashed];\n", id, eps[id].Name)
+	}
+
+	for _, l := range d.Links {
+		fmt.Fprintf(bw, "\t%s -- %s [taillabel=%q, headlabel=%q];\n",
+			endpointID(l.Local), endpointID(l.Remote), l.Local.Interface, l.Remote.Interface)
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// mermaidLabel quotes s as a Mermaid label. Mermaid has no backslash
+// escapes, so double quotes are written as the #quot; entity code.
+func mermaidLabel(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// WriteMermaid writes the diagram as a Mermaid flowchart.
+func (d *Diagram) WriteMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart TB")
+
+	var write func(c *diagramCluster, indent string)
+	write = func(c *diagramCluster, indent string) {
+		for _, cc := range c.clusters {
+			fmt.Fprintf(bw, "%ssubgraph %s [%s]\n", indent, cc.id, mermaidLabel(cc.label))
+			write(cc, indent+"    ")
+			fmt.Fprintf(bw, "%send\n", indent)
+		}
+		for _, n := range c.nodes {
+			fmt.Fprintf(bw, "%s%s[%s]\n", indent, nodeID(n), mermaidLabel(nodeLabel(n)))
+		}
+	}
+	write(d.clusters(), "    ")
+
+	ids, eps := d.externals()
+	for _, id := range ids {
+		fmt.Fprintf(bw, "    %s([%s])\n", id, mermaidLabel(eps[id].Name))
+	}
+
+	for _, l := range d.Links {
+		fmt.Fprintf(bw, "    %s ---|%s| %s\n",
+			endpointID(l.Local), mermaidLabel(l.Local.Interface+" - "+l.Remote.Interface), endpointID(l.Remote))
+	}
+
+	for _, n := range d.Nodes {
+		fmt.Fprintf(bw, "    style %s fill:%s,stroke:%s,stroke-width:2px\n", nodeID(n), roleColor(n), stateColor(n))
+	}
+
+	return bw.Flush()
+}
+
+// WriteGraphML writes the diagram as GraphML, with clusters
+// as nested graphs.
+func (d *Diagram) WriteGraphML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	esc := func(s string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	fmt.Fprintln(bw, xml.Header+`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	for _, key := range []struct{ id, target string }{
+		{"label", "all"},
+		{"role", "node"},
+		{"fabricState", "node"},
+		{"fill", "node"},
+		{"stroke", "node"},
+		{"localInterface", "edge"},
+		{"remoteInterface", "edge"},
+		{"source", "edge"},
+	} {
+		fmt.Fprintf(bw, "  <key id=%q for=%q attr.name=%q attr.type=\"string\"/>\n", key.id, key.target, key.id)
+	}
+	fmt.Fprintln(bw, `  <graph id="fabric" edgedefault="undirected">`)
+
+	var write func(c *diagramCluster, indent string)
+	write = func(c *diagramCluster, indent string) {
+		for _, cc := range c.clusters {
+			id := "cluster_" + cc.id
+			fmt.Fprintf(bw, "%s<node id=%q>\n", indent, id)
+			fmt.Fprintf(bw, "%s  <data key=\"label\">%s</data>\n", indent, esc(cc.label))
+			fmt.Fprintf(bw, "%s  <graph id=\"%s:\" edgedefault=\"undirected\">\n", indent, id)
+			write(cc, indent+"    ")
+			fmt.Fprintf(bw, "%s  </graph>\n", indent)
+			fmt.Fprintf(bw, "%s</node>\n", indent)
+		}
+		for _, n := range c.nodes {
+			fmt.Fprintf(bw, "%s<node id=%q>\n", indent, nodeID(n))
+			fmt.Fprintf(bw, "%s  <data key=\"label\">%s</data>\n", indent, esc(nodeLabel(n)))
+			fmt.Fprintf(bw, "%s  <data key=\"role\">%s</data>\n", indent, esc(n.Role()))
+			fmt.Fprintf(bw, "%s  <data key=\"fabricState\">%s</data>\n", indent, esc(n.FabricState()))
+			fmt.Fprintf(bw, "%s  <data key=\"fill\">%s</data>\n", indent, roleColor(n))
+			fmt.Fprintf(bw, "%s  <data key=\"stroke\">%s</data>\n", indent, stateColor(n))
+			fmt.Fprintf(bw, "%s</node>\n", indent)
+		}
+	}
+	write(d.clusters(), "    ")
+
+	ids, eps := d.externals()
+	for _, id := range ids {
+		fmt.Fprintf(bw, "    <node id=%q>\n", id)
+		fmt.Fprintf(bw, "      <data key=\"label\">%s</data>\n", esc(eps[id].Name))
+		fmt.Fprintln(bw, "    </node>")
+	}
+
+	for i, l := range d.Links {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=%q target=%q>\n", i, endpointID(l.Local), endpointID(l.Remote))
+		fmt.Fprintf(bw, "      <data key=\"localInterface\">%s</data>\n", esc(l.Local.Interface))
+		fmt.Fprintf(bw, "      <data key=\"remoteInterface\">%s</data>\n", esc(l.Remote.Interface))
+		fmt.Fprintf(bw, "      <data key=\"source\">%s</data>\n", esc(l.Source))
+		fmt.Fprintln(bw, "    </edge>")
+	}
+
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
@@ -0,0 +1,134 @@
+package aci
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// testDiagram returns a diagram of a leaf in a rack and a spine without
+// a location, with external neighbors whose names need escaping.
+func testDiagram(t *testing.T) *Diagram {
+	t.Helper()
+	leaf := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	spine := newTestNode(t, "spine-201", "201", "1", "FDO2", "spine")
+	return &Diagram{
+		Nodes: []*Node{spine, leaf},
+		Links: []*Link{
+			{Local: Endpoint{NodeID: "101", Interface: "eth1/49"}, Remote: Endpoint{NodeID: "201", Interface: "eth1/1"}, Source: LinkSourceFabric},
+			{Local: Endpoint{NodeID: "101", Interface: "eth1/1"}, Remote: Endpoint{Name: "srv-1", Interface: "eth0"}, Source: LinkSourceLLDP},
+			{Local: Endpoint{NodeID: "101", Interface: "eth1/2"}, Remote: Endpoint{Name: "srv_1", Interface: "eth0"}, Source: LinkSourceLLDP},
+			{Local: Endpoint{NodeID: "101", Interface: "eth1/3"}, Remote: Endpoint{Name: `srv "a"`, Interface: "eth0"}, Source: LinkSourceLLDP},
+		},
+		Locations: map[string]NodeLocation{
+			"101": {Site: "dc-1", Building: "b1", Floor: "f1", Room: "r1", Row: "row1", Rack: "rack1"},
+		},
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDiagram(t).WriteMermaid(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `flowchart TB
+    subgraph pod_1 ["pod-1"]
+        subgraph pod_1_site_dc_2d_1 ["site dc-1"]
+            subgraph pod_1_site_dc_2d_1_b1_f1_r1 ["room b1/f1/r1"]
+                subgraph pod_1_site_dc_2d_1_b1_f1_r1_row1_rack1 ["rack row1/rack1"]
+                    node_101["leaf-101 (101)"]
+                end
+            end
+        end
+        node_201["spine-201 (201)"]
+    end
+    ext_srv_2d_1(["srv-1"])
+    ext_srv_5f_1(["srv_1"])
+    ext_srv_20__22_a_22_(["srv #quot;a#quot;"])
+    node_101 ---|"eth1/49 - eth1/1"| node_201
+    node_101 ---|"eth1/1 - eth0"| ext_srv_2d_1
+    node_101 ---|"eth1/2 - eth0"| ext_srv_5f_1
+    node_101 ---|"eth1/3 - eth0"| ext_srv_20__22_a_22_
+    style node_201 fill:#aec7e8,stroke:#000000,stroke-width:2px
+    style node_101 fill:#98df8a,stroke:#000000,stroke-width:2px
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDiagram(t).WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\tsubgraph cluster_pod_1 {\n",
+		"\t\t\t\t\tnode_101 [label=\"leaf-101 (101)\", fillcolor=\"#98df8a\", color=\"#000000\"];\n",
+		"\text_srv_20__22_a_22_ [label=\"srv \\\"a\\\"\", shape=ellipse, style=dashed];\n",
+		"\tnode_101 -- ext_srv_5f_1 [taillabel=\"eth1/2\", headlabel=\"eth0\"];\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDiagram(t).WriteGraphML(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// every node and edge is found, and the document is well formed
+	var doc struct {
+		Nodes []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+		} `xml:"graph>edge"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, n := range doc.Nodes {
+		ids = append(ids, n.ID)
+	}
+	if got, want := strings.Join(ids, " "), "cluster_pod_1 ext_srv_2d_1 ext_srv_5f_1 ext_srv_20__22_a_22_"; got != want {
+		t.Errorf("got top level nodes %s, want %s", got, want)
+	}
+	if len(doc.Edges) != 4 || doc.Edges[2].Target != "ext_srv_5f_1" {
+		t.Errorf("got edges %+v", doc.Edges)
+	}
+}
+
+func TestTopologyDiagram(t *testing.T) {
+	_, c := newFakeAPIC(t, topologyFixtures)
+
+	d, err := c.Topology.Diagram(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// links between fabric nodes are drawn once
+	var links []string
+	for _, l := range d.Links {
+		links = append(links, l.String())
+	}
+	want := []string{
+		"node-101 eth1/10 -> srv-1 eth0 (lldp)",
+		"node-101 eth1/49 -> node-201 eth1/1 (fabric)",
+		"node-102 eth1/10 -> router-1 Gi0/1 (cdp)",
+		"node-102 eth1/49 -> node-201 eth1/2 (fabric)",
+	}
+	if strings.Join(links, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got links:\n%s\nwant:\n%s", strings.Join(links, "\n"), strings.Join(want, "\n"))
+	}
+	if len(d.Nodes) != 4 {
+		t.Errorf("got %d nodes, want 4", len(d.Nodes))
+	}
+}
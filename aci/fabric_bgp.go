@@ -0,0 +1,182 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// bgpInstPolDN is the distinguished name of the fabric BGP policy.
+const bgpInstPolDN = "uni/fabric/bgpInstP-default"
+
+// GetBGPASN retrieves the autonomous system number of the fabric,
+// or 0 if it has not been configured.
+func (s *FabricPolicyService) GetBGPASN(ctx context.Context) (uint32, error) {
+	fc, err := s.getFabric(ctx, fmt.Sprintf("api/node/mo/%s/as.json", bgpInstPolDN))
+	if err != nil {
+		return 0, fmt.Errorf("get bgp asn: %v", err)
+	}
+	if len(fc) == 0 || fc[0].BgpAsP == nil {
+		return 0, nil
+	}
+	asn, err := strconv.ParseUint(fc[0].BgpAsP.Asn, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("get bgp asn: %v", err)
+	}
+	return uint32(asn), nil
+}
+
+// ListRouteReflectors lists the spine nodes that are route reflectors
+// of the fabric, by node ID. Their names are those of the fabric
+// membership, for the nodes that have been discovered.
+func (s *FabricPolicyService) ListRouteReflectors(ctx context.Context) ([]*Node, error) {
+	rrs, err := s.routeReflectors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list route reflectors: %v", err)
+	}
+
+	members, err := s.client.FabricMembership.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list route reflectors: %v", err)
+	}
+	names := make(map[string]string)
+	for _, m := range members {
+		names[m.ID()] = m.Name()
+	}
+
+	var nodes []*Node
+	for _, rr := range rrs {
+		nodes = append(nodes, &Node{
+			id:   rr.ID,
+			name: names[rr.ID],
+			pod:  rr.PodID,
+			role: "spine",
+		})
+	}
+	return nodes, nil
+}
+
+// routeReflectors retrieves the route reflector node endpoints
+// of the fabric, sorted by node ID.
+func (s *FabricPolicyService) routeReflectors(ctx context.Context) ([]FabricAttrs, error) {
+	path := fmt.Sprintf("api/node/mo/%s/rr.json?query-target=children&target-subtree-class=bgpRRNodePEp", bgpInstPolDN)
+	fc, err := s.getFabric(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var rrs []FabricAttrs
+	for _, c := range fc {
+		if c.BgpRRNodePEp != nil {
+			rrs = append(rrs, c.BgpRRNodePEp.FabricAttrs)
+		}
+	}
+	sort.Slice(rrs, func(i, j int) bool {
+		a, _ := strconv.Atoi(rrs[i].ID)
+		b, _ := strconv.Atoi(rrs[j].ID)
+		return a < b
+	})
+	return rrs, nil
+}
+
+// UpdateRouteReflectors sets the autonomous system number of the fabric,
+// and makes the given spines, and only them, its route reflectors, each
+// in the pod it is registered in. Spines already route reflectors in that
+// pod are left as they are, and nothing is posted when the ASN and route
+// reflectors are already those given.
+//
+// At least one spine must be given. Every spine must be a member of the
+// fabric with the role "spine", and if it has a pod, that of its
+// membership. The ASN cannot be 0.
+func (s *FabricPolicyService) UpdateRouteReflectors(ctx context.Context, asn uint32, spines ...*Node) (FabricResponse, error) {
+	if asn == 0 {
+		return FabricResponse{}, fmt.Errorf("update route reflectors: invalid asn: %d", asn)
+	}
+	if len(spines) == 0 {
+		return FabricResponse{}, fmt.Errorf("update route reflectors: no spines")
+	}
+
+	members, err := s.client.FabricMembership.List(ctx)
+	if err != nil {
+		return FabricResponse{}, fmt.Errorf("update route reflectors: %v", err)
+	}
+	registered := make(map[string]*Node)
+	for _, m := range members {
+		registered[m.ID()] = m
+	}
+
+	want := make(map[string]string)
+	var ids []string
+	for _, spine := range spines {
+		if spine == nil {
+			return FabricResponse{}, fmt.Errorf("update route reflectors: nil node")
+		}
+		m, ok := registered[spine.ID()]
+		if !ok {
+			return FabricResponse{}, fmt.Errorf("update route reflectors: node %s: not a fabric member", spine.ID())
+		}
+		if m.Role() != "spine" {
+			return FabricResponse{}, fmt.Errorf("update route reflectors: node %s: invalid role: %s", spine.ID(), m.Role())
+		}
+		if m.Pod() == "" {
+			return FabricResponse{}, fmt.Errorf("update route reflectors: node %s: no pod", spine.ID())
+		}
+		if spine.Pod() != "" && spine.Pod() != m.Pod() {
+			return FabricResponse{}, fmt.Errorf("update route reflectors: node %s: in pod %s, not %s", spine.ID(), m.Pod(), spine.Pod())
+		}
+		if _, ok := want[spine.ID()]; !ok {
+			ids = append(ids, spine.ID())
+		}
+		want[spine.ID()] = m.Pod()
+	}
+
+	current, err := s.routeReflectors(ctx)
+	if err != nil {
+		return FabricResponse{}, fmt.Errorf("update route reflectors: %v", err)
+	}
+	have := make(map[string]string)
+	for _, rr := range current {
+		have[rr.ID] = rr.PodID
+	}
+
+	rrp := &FabricObject{FabricAttrs: FabricAttrs{Status: createdModified}}
+	for _, rr := range current {
+		if _, ok := want[rr.ID]; !ok {
+			rrp.Children = append(rrp.Children, FabricContainer{
+				BgpRRNodePEp: &FabricObject{
+					FabricAttrs: FabricAttrs{ID: rr.ID, PodID: rr.PodID, Status: deleted},
+				},
+			})
+		}
+	}
+	for _, id := range ids {
+		if have[id] == want[id] {
+			continue
+		}
+		rrp.Children = append(rrp.Children, FabricContainer{
+			BgpRRNodePEp: &FabricObject{
+				FabricAttrs: FabricAttrs{ID: id, PodID: want[id], Status: createdModified},
+			},
+		})
+	}
+
+	if len(rrp.Children) == 0 {
+		current, err := s.GetBGPASN(ctx)
+		if err != nil {
+			return FabricResponse{}, fmt.Errorf("update route reflectors: %v", err)
+		}
+		if current == asn {
+			return FabricResponse{}, nil
+		}
+	}
+
+	return s.postFabric(ctx, bgpInstPolDN, FabricContainer{
+		BgpInstPol: &FabricObject{
+			FabricAttrs: FabricAttrs{DN: bgpInstPolDN, Status: createdModified},
+			Children: []FabricContainer{
+				{BgpAsP: &FabricObject{FabricAttrs: FabricAttrs{Asn: strconv.FormatUint(uint64(asn), 10)}}},
+				{BgpRRP: rrp},
+			},
+		},
+	})
+}
@@ -0,0 +1,122 @@
+package aci
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUpdateRouteReflectors(t *testing.T) {
+	fm := &FabricMembershipService{}
+	leaf, _ := fm.NewNode("leaf-101", "101", "1", "FDO1", "leaf")
+	spine, _ := fm.NewNode("spine-201", "201", "1", "FDO2", "spine")
+	// a leaf the caller believes is a spine
+	notSpine, _ := fm.NewNode("leaf-102", "102", "1", "FDO3", "spine")
+	unknown, _ := fm.NewNode("spine-202", "202", "1", "FDO4", "spine")
+	// a spine the caller believes is in another pod
+	wrongPod, _ := fm.NewNode("spine-201", "201", "2", "FDO2", "spine")
+
+	tests := []struct {
+		asn    uint32
+		spines []*Node
+		err    string
+	}{
+		{0, []*Node{spine}, "update route reflectors: invalid asn: 0"},
+		{65001, nil, "update route reflectors: no spines"},
+		{65001, []*Node{leaf}, "update route reflectors: node 101: invalid role: leaf"},
+		{65001, []*Node{notSpine}, "update route reflectors: node 102: invalid role: leaf"},
+		{65001, []*Node{unknown}, "update route reflectors: node 202: not a fabric member"},
+		{65001, []*Node{wrongPod}, "update route reflectors: node 201: in pod 1, not 2"},
+		{65001, []*Node{spine}, ""},
+	}
+	for _, tt := range tests {
+		f, c := newFakeAPIC(t, map[string]string{
+			"/api/node/class/fabricNode.json": `{"imdata":[
+				{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-101","id":"101","name":"leaf-101","role":"leaf"}}},
+				{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-102","id":"102","name":"leaf-102","role":"leaf"}}},
+				{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-201","id":"201","name":"spine-201","role":"spine"}}}
+			]}`,
+		})
+		_, err := c.FabricPolicy.UpdateRouteReflectors(context.Background(), tt.asn, tt.spines...)
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("%v: %v", tt.spines, err)
+			}
+			posts := f.posted("/api/node/mo/" + bgpInstPolDN + ".json")
+			if len(posts) != 1 || !strings.Contains(posts[0], `"id":"201"`) {
+				t.Errorf("posted %v, want spine 201 as a route reflector", posts)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("%v: got error %v, want %s", tt.spines, err, tt.err)
+		}
+		if n := f.postCount(); n != 0 {
+			t.Errorf("%v: got %d posts, want none", tt.spines, n)
+		}
+	}
+}
+
+func TestUpdateRouteReflectorsChanges(t *testing.T) {
+	const (
+		bgpPath = "/api/node/mo/uni/fabric/bgpInstP-default.json"
+		asPath  = "/api/node/mo/uni/fabric/bgpInstP-default/as.json"
+		rrPath  = "/api/node/mo/uni/fabric/bgpInstP-default/rr.json"
+	)
+	f, c := newFakeAPIC(t, map[string]string{
+		"/api/node/class/fabricNode.json": `{"imdata":[
+			{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-201","id":"201","name":"spine-201","role":"spine"}}},
+			{"fabricNode":{"attributes":{"dn":"topology/pod-2/node-202","id":"202","name":"spine-202","role":"spine"}}},
+			{"fabricNode":{"attributes":{"dn":"topology/pod-2/node-203","id":"203","name":"spine-203","role":"spine"}}}
+		]}`,
+		asPath: `{"imdata":[{"bgpAsP":{"attributes":{"asn":"65001"}}}]}`,
+		rrPath: `{"imdata":[
+			{"bgpRRNodePEp":{"attributes":{"id":"201","podId":"1"}}},
+			{"bgpRRNodePEp":{"attributes":{"id":"203","podId":"2"}}}
+		]}`,
+	})
+	fm := &FabricMembershipService{}
+	spine201, _ := fm.NewNode("spine-201", "201", "1", "FDO1", "spine")
+	spine202, _ := fm.NewNode("spine-202", "202", "2", "FDO2", "spine")
+
+	// 203 is no longer a route reflector, 201 is left alone and 202 is added
+	if _, err := c.FabricPolicy.UpdateRouteReflectors(context.Background(), 65001, spine201, spine202); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted(bgpPath)
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"bgpInstPol":{"attributes":{"dn":"uni/fabric/bgpInstP-default","status":"created,modified"},"children":[
+		{"bgpAsP":{"attributes":{"asn":"65001"}}},
+		{"bgpRRP":{"attributes":{"status":"created,modified"},"children":[
+			{"bgpRRNodePEp":{"attributes":{"id":"203","podId":"2","status":"deleted"}}},
+			{"bgpRRNodePEp":{"attributes":{"id":"202","podId":"2","status":"created,modified"}}}
+		]}}
+	]}}`)
+
+	// updating again with the same asn and spines posts nothing
+	f.setFixture(rrPath, `{"imdata":[
+		{"bgpRRNodePEp":{"attributes":{"id":"201","podId":"1"}}},
+		{"bgpRRNodePEp":{"attributes":{"id":"202","podId":"2"}}}
+	]}`)
+	if _, err := c.FabricPolicy.UpdateRouteReflectors(context.Background(), 65001, spine201, spine202); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.posted(bgpPath)); n != 1 {
+		t.Errorf("got %d posts, want no more than the first", n)
+	}
+
+	// a new asn is posted even if the route reflectors are unchanged
+	if _, err := c.FabricPolicy.UpdateRouteReflectors(context.Background(), 65002, spine201, spine202); err != nil {
+		t.Fatal(err)
+	}
+	posts = f.posted(bgpPath)
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	jsonEqual(t, posts[1], `{"bgpInstPol":{"attributes":{"dn":"uni/fabric/bgpInstP-default","status":"created,modified"},"children":[
+		{"bgpAsP":{"attributes":{"asn":"65002"}}},
+		{"bgpRRP":{"attributes":{"status":"created,modified"}}}
+	]}}`)
+}
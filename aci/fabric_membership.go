@@ -4,8 +4,25 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Fabric states of a node, as reported by the fabricSt attribute of fabricNode.
+const (
+	FabricStateActive         = "active"
+	FabricStateInactive       = "inactive"
+	FabricStateDecommissioned = "decommissioned"
+	FabricStateDisabled       = "disabled"
+	FabricStateDiscovering    = "discovering"
+	FabricStateUndiscovered   = "undiscovered"
+	FabricStateUnknown        = "unknown"
+)
+
+// statePollInterval is how often WaitForState polls the APIC.
+var statePollInterval = 10 * time.Second
+
 // NodesResponse contains the response for ACI fabric nodes requests
 type NodesResponse struct {
 	NodesImdata []NodesImdata `json:"imdata"`
@@ -99,7 +116,39 @@ func (s *FabricMembershipService) NewNode(name, ID, pod, serial, role string) (*
 	return node, nil
 }
 
-// Update ...
+// CheckPods returns an error if the pod of any node that is not being
+// deleted does not exist in the fabric, so that nodes can be validated
+// before they are registered with Update.
+func (s *FabricMembershipService) CheckPods(ctx context.Context, nodes ...*Node) error {
+	var check []*Node
+	for _, node := range nodes {
+		if node.Status() != deleted && node.Pod() != "" {
+			check = append(check, node)
+		}
+	}
+	if len(check) == 0 {
+		return nil
+	}
+
+	pods, err := s.client.Pod.List(ctx)
+	if err != nil {
+		return fmt.Errorf("check pods: %v", err)
+	}
+	exists := make(map[string]bool)
+	for _, pod := range pods {
+		exists[pod.ID()] = true
+	}
+	for _, node := range check {
+		if !exists[node.Pod()] {
+			return fmt.Errorf("node %s: pod %s does not exist", node.ID(), node.Pod())
+		}
+	}
+	return nil
+}
+
+// Update registers or removes fabric membership nodes according to each
+// node's status. Use CheckPods first to make sure the pods of the nodes
+// exist.
 func (s *FabricMembershipService) Update(ctx context.Context, nodes ...*Node) (NodesResponse, error) {
 
 	path := "api/node/mo/uni/controller/nodeidentpol.json"
@@ -171,11 +220,13 @@ func (s *FabricMembershipService) List(ctx context.Context) ([]*Node, error) {
 		// returned by the APIC server, as APIC
 		// should have already done this.
 		ns = append(ns, &Node{
-			name:   n.Name,
-			id:     n.ID,
-			role:   n.Role,
-			serial: n.Serial,
-			status: n.Status,
+			name:        n.Name,
+			id:          n.ID,
+			role:        n.Role,
+			serial:      n.Serial,
+			status:      n.Status,
+			pod:         podFromDN(n.DN),
+			fabricState: n.FabricSt,
 		})
 	}
 	return ns, nil
@@ -199,21 +250,44 @@ type DecommissionAttributes struct {
 	RemoveFromController string `json:"removeFromController"` // "true"
 }
 
-// DecommissionNode decommisions a fabric membership node
+// DecommissionNode decommisions a fabric membership node,
+// removing it from the controller.
 func (s *FabricMembershipService) DecommissionNode(ctx context.Context, node *Node) (NodesResponse, error) {
+	return s.Decommission(ctx, node, true)
+}
 
-	path := "api/node/mo/uni/fabric/outofsvc.json"
+// Decommission decommissions a fabric membership node.
+//
+// If removeFromController is true the node is removed from the controller
+// entirely, otherwise it is only taken out of service for maintenance and
+// can later be brought back with Recommission.
+func (s *FabricMembershipService) Decommission(ctx context.Context, node *Node, removeFromController bool) (NodesResponse, error) {
+	payload := newNodeDecommissionContainer(node, createdModified, removeFromController)
+	return s.postOutOfService(ctx, payload)
+}
+
+// Recommission brings a decommissioned fabric membership node back into service.
+func (s *FabricMembershipService) Recommission(ctx context.Context, node *Node) (NodesResponse, error) {
+	payload := newNodeDecommissionContainer(node, deleted, false)
+	return s.postOutOfService(ctx, payload)
+}
 
+func newNodeDecommissionContainer(node *Node, status string, removeFromController bool) NodeDecommissionContainer {
 	tdn := fmt.Sprintf("topology/pod-%s/node-%s", node.pod, node.id)
-	payload := NodeDecommissionContainer{
+	return NodeDecommissionContainer{
 		NodeDecommission: NodeDecommission{
 			DecommissionAttributes: DecommissionAttributes{
 				TDN:                  tdn,
-				Status:               createdModified,
-				RemoveFromController: "true",
+				Status:               status,
+				RemoveFromController: strconv.FormatBool(removeFromController),
 			},
 		},
 	}
+}
+
+func (s *FabricMembershipService) postOutOfService(ctx context.Context, payload NodeDecommissionContainer) (NodesResponse, error) {
+
+	path := "api/node/mo/uni/fabric/outofsvc.json"
 
 	var nr NodesResponse
 
@@ -228,3 +302,243 @@ func (s *FabricMembershipService) DecommissionNode(ctx context.Context, node *No
 	}
 	return nr, nil
 }
+
+// State returns the current fabric state of a node, as reported by
+// the fabricSt attribute of its fabricNode object.
+//
+// A node that is no longer known to the fabric, for example after it has
+// been decommissioned and removed from the controller, is reported as
+// FabricStateUnknown.
+func (s *FabricMembershipService) State(ctx context.Context, node *Node) (string, error) {
+
+	path := fmt.Sprintf("api/node/mo/topology/pod-%s/node-%s.json", node.pod, node.id)
+
+	var nr NodesResponse
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return "", fmt.Errorf("state: %v", err)
+	}
+
+	_, err = s.client.Do(ctx, req, &nr)
+	if err != nil {
+		return "", fmt.Errorf("state: %v", err)
+	}
+
+	if len(nr.NodesImdata) == 0 || nr.NodesImdata[0].FabricSt == "" {
+		return FabricStateUnknown, nil
+	}
+	return nr.NodesImdata[0].FabricSt, nil
+}
+
+// WaitForState polls the fabric state of a node until it reaches the given
+// state, or the context is done, whichever happens first.
+//
+// The node's fabric state is updated each time it is polled.
+func (s *FabricMembershipService) WaitForState(ctx context.Context, node *Node, state string) error {
+	ticker := time.NewTicker(statePollInterval)
+	defer ticker.Stop()
+
+	for {
+		st, err := s.State(ctx, node)
+		if err != nil {
+			return err
+		}
+		node.fabricState = st
+		if st == state {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for node %s to be %s: %v (currently %s)", node.id, state, ctx.Err(), st)
+		case <-ticker.C:
+		}
+	}
+}
+
+// podFromDN returns the pod id from a fabric node distinguished name
+// of the form "topology/pod-<pod>/node-<nodeID>".
+func podFromDN(dn string) string {
+	for _, rn := range strings.Split(dn, "/") {
+		if strings.HasPrefix(rn, "pod-") {
+			return strings.TrimPrefix(rn, "pod-")
+		}
+	}
+	return ""
+}
+
+// Steps of a node replacement, as reported to a ReplaceProgressFunc.
+const (
+	ReplaceStepDecommission   = "decommission"
+	ReplaceStepRemoveIdentity = "remove identity"
+	ReplaceStepRegister       = "register"
+	ReplaceStepDiscover       = "discover"
+	ReplaceStepRollback       = "rollback"
+)
+
+// ReplaceProgressFunc is called as each step of a node replacement begins,
+// with the step and the node it acts upon.
+type ReplaceProgressFunc func(step string, node *Node)
+
+// Replace replaces a failed fabric node with a new switch, returning the
+// newly registered node.
+//
+// The old node is decommissioned and removed from the controller, its node
+// identity profile is deleted, and the new serial number is registered with
+// the same name, node id, pod and role. Replace then waits for the new node
+// to be discovered and become active.
+//
+// If registering the new node or its discovery fails, its registration is
+// removed and the node identity of the old node is registered again, so the
+// node id is left registered to the old serial number. The old switch stays
+// decommissioned, and is only rediscovered once it is recommissioned. The
+// steps before registration are not rolled back: if decommissioning the old
+// node or removing its identity fails, the old node is left as it is.
+//
+// The old node given is not modified.
+func (s *FabricMembershipService) Replace(ctx context.Context, old *Node, newSerial string, progress ...ReplaceProgressFunc) (*Node, error) {
+	report := func(step string, node *Node) {
+		for _, fn := range progress {
+			fn(step, node)
+		}
+	}
+
+	node, err := s.NewNode(old.Name(), old.ID(), old.Pod(), newSerial, old.Role())
+	if err != nil {
+		return nil, fmt.Errorf("replace %s: %v", old, err)
+	}
+
+	// the old node is acted upon through a copy, leaving the caller's as it is
+	identity := *old
+
+	report(ReplaceStepDecommission, old)
+	if _, err := s.DecommissionNode(ctx, &identity); err != nil {
+		return nil, fmt.Errorf("replace %s: decommission: %v", old, err)
+	}
+	if err := s.WaitForState(ctx, &identity, FabricStateUnknown); err != nil {
+		return nil, fmt.Errorf("replace %s: decommission: %v", old, err)
+	}
+
+	report(ReplaceStepRemoveIdentity, old)
+	identity.SetDeleted()
+	if _, err := s.Update(ctx, &identity); err != nil {
+		return nil, fmt.Errorf("replace %s: remove identity: %v", old, err)
+	}
+
+	// rollback removes the registration of the new node after the given
+	// step failed, and registers the old node identity again, returning
+	// the error of that step.
+	rollback := func(step string, err error) error {
+		report(ReplaceStepRollback, node)
+		// the original context may well have expired,
+		// so the rollback is given its own.
+		rctx, cancel := context.WithTimeout(context.Background(), clientTimeout)
+		defer cancel()
+		node.SetDeleted()
+		if _, rerr := s.Update(rctx, node); rerr != nil {
+			return fmt.Errorf("replace %s: %s: %v (rollback failed: %v)", old, step, err, rerr)
+		}
+		identity.SetCreated()
+		if _, rerr := s.Update(rctx, &identity); rerr != nil {
+			return fmt.Errorf("replace %s: %s: %v (rollback failed to restore identity: %v)", old, step, err, rerr)
+		}
+		return fmt.Errorf("replace %s: %s: %v", old, step, err)
+	}
+
+	report(ReplaceStepRegister, node)
+	node.SetCreated()
+	if _, err := s.Update(ctx, node); err != nil {
+		return nil, rollback(ReplaceStepRegister, err)
+	}
+
+	report(ReplaceStepDiscover, node)
+	if err := s.WaitForState(ctx, node, FabricStateActive); err != nil {
+		return nil, rollback(ReplaceStepDiscover, err)
+	}
+
+	return node, nil
+}
+
+// defaultChunkSize is the number of nodes sent in each request
+// by BatchUpdate when no chunk size is given.
+const defaultChunkSize = 50
+
+// NodeResult is the outcome of updating a single node with BatchUpdate.
+type NodeResult struct {
+	Node *Node
+	Err  error  // nil if the node was updated successfully
+	Code string // APIC specific error code, if any
+	Text string // APIC error text, if any
+}
+
+// OK reports whether the node was updated successfully.
+func (r NodeResult) OK() bool {
+	return r.Err == nil
+}
+
+// BatchUpdate updates the fabric membership nodes in chunks of chunkSize
+// nodes at a time, returning a result for each node in the order given.
+//
+// If the APIC rejects a chunk, it is split in half and each half retried,
+// until the nodes responsible are pinpointed. A chunkSize less than 1 uses
+// the default chunk size.
+//
+// An error is only returned if the batch could not be completed, such as
+// when the context is cancelled; errors for individual nodes are reported
+// in their results.
+func (s *FabricMembershipService) BatchUpdate(ctx context.Context, chunkSize int, nodes ...*Node) ([]NodeResult, error) {
+	if chunkSize < 1 {
+		chunkSize = defaultChunkSize
+	}
+
+	results := make([]NodeResult, len(nodes))
+	for i, node := range nodes {
+		results[i].Node = node
+	}
+
+	for start := 0; start < len(nodes); start += chunkSize {
+		end := start + chunkSize
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		if err := s.updateChunk(ctx, results[start:end]); err != nil {
+			return results, fmt.Errorf("batch update: %v", err)
+		}
+	}
+	return results, nil
+}
+
+// updateChunk updates the nodes of the given results, bisecting the chunk
+// whenever the APIC rejects it. Any error that did not come from the APIC
+// is returned.
+func (s *FabricMembershipService) updateChunk(ctx context.Context, results []NodeResult) error {
+	var nodes []*Node
+	for _, r := range results {
+		nodes = append(nodes, r.Node)
+	}
+
+	_, err := s.Update(ctx, nodes...)
+	if err == nil {
+		return nil
+	}
+	apicErr, ok := err.(*ErrorResponse)
+	if !ok {
+		return err
+	}
+
+	if len(results) == 1 {
+		results[0].Err = err
+		if len(apicErr.Errors) > 0 {
+			results[0].Code = apicErr.Errors[0].Code
+			results[0].Text = apicErr.Errors[0].Text
+		}
+		return nil
+	}
+
+	mid := len(results) / 2
+	if err := s.updateChunk(ctx, results[:mid]); err != nil {
+		return err
+	}
+	return s.updateChunk(ctx, results[mid:])
+}
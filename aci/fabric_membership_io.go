@@ -0,0 +1,266 @@
+package aci
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Formats supported for importing and exporting ACI objects.
+const (
+	FormatCSV  = "csv"
+	FormatYAML = "yaml"
+)
+
+// nodeColumns are the CSV header columns for fabric membership nodes,
+// in the order they are exported.
+var nodeColumns = []string{"name", "id", "pod", "serial", "role"}
+
+// LineError is a validation error for a single line of imported data.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ImportErrors reports every line that failed validation during an import.
+type ImportErrors []*LineError
+
+func (e ImportErrors) Error() string {
+	var lines []string
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nodeRecord is a fabric membership node as it is imported and exported.
+type nodeRecord struct {
+	Name   string `yaml:"name"`
+	ID     string `yaml:"id"`
+	Pod    string `yaml:"pod"`
+	Serial string `yaml:"serial"`
+	Role   string `yaml:"role"`
+
+	line int
+	// err is set if the record could not be read.
+	err error
+}
+
+// ImportNodes reads fabric membership nodes in the given format,
+// either FormatCSV or FormatYAML.
+//
+// CSV input must begin with a header naming the name, id, pod, serial
+// and role columns, in any order. YAML input must be a list of mappings
+// with the same keys.
+//
+// Every node is validated as by NewNode, and node ids and serial numbers
+// must be unique. The nodes that are valid are returned, along with an
+// ImportErrors describing each line that is not.
+func (s *FabricMembershipService) ImportNodes(r io.Reader, format string) ([]*Node, error) {
+	var records []nodeRecord
+	var err error
+
+	switch format {
+	case FormatCSV:
+		records, err = readNodesCSV(r)
+	case FormatYAML:
+		records, err = readNodesYAML(r)
+	default:
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("import nodes: %v", err)
+	}
+
+	var nodes []*Node
+	var errs ImportErrors
+	ids := make(map[string]int)
+	serials := make(map[string]int)
+
+	for _, rec := range records {
+		if rec.err != nil {
+			errs = append(errs, &LineError{Line: rec.line, Err: rec.err})
+			continue
+		}
+		node, err := s.NewNode(rec.Name, rec.ID, rec.Pod, rec.Serial, rec.Role)
+		if err != nil {
+			errs = append(errs, &LineError{Line: rec.line, Err: err})
+			continue
+		}
+		if line, ok := ids[node.ID()]; ok {
+			errs = append(errs, &LineError{Line: rec.line, Err: fmt.Errorf("duplicate node id: %s (line %d)", node.ID(), line)})
+			continue
+		}
+		if line, ok := serials[node.Serial()]; ok {
+			errs = append(errs, &LineError{Line: rec.line, Err: fmt.Errorf("duplicate serial number: %s (line %d)", node.Serial(), line)})
+			continue
+		}
+		ids[node.ID()] = rec.line
+		serials[node.Serial()] = rec.line
+		nodes = append(nodes, node)
+	}
+
+	if len(errs) > 0 {
+		return nodes, errs
+	}
+	return nodes, nil
+}
+
+func readNodesCSV(r io.Reader) ([]nodeRecord, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %v", err)
+	}
+
+	index := make(map[string]int)
+	for i, col := range header {
+		index[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+	for _, col := range nodeColumns {
+		if _, ok := index[col]; !ok {
+			return nil, fmt.Errorf("missing column: %s", col)
+		}
+	}
+
+	var records []nodeRecord
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			// the reader carries on from the line after a malformed one
+			records = append(records, nodeRecord{line: perr.StartLine, err: perr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(row) != len(header) {
+			records = append(records, nodeRecord{
+				line: line,
+				err:  fmt.Errorf("expected %d fields, found %d", len(header), len(row)),
+			})
+			continue
+		}
+		field := func(col string) string {
+			return strings.TrimSpace(row[index[col]])
+		}
+		records = append(records, nodeRecord{
+			Name:   field("name"),
+			ID:     field("id"),
+			Pod:    field("pod"),
+			Serial: field("serial"),
+			Role:   field("role"),
+			line:   line,
+		})
+	}
+	return records, nil
+}
+
+func readNodesYAML(r io.Reader) ([]nodeRecord, error) {
+	var doc yaml.Node
+	err := yaml.NewDecoder(r).Decode(&doc)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &doc
+	if list.Kind == yaml.DocumentNode && len(list.Content) > 0 {
+		list = list.Content[0]
+	}
+	if list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("line %d: expected a list of nodes", list.Line)
+	}
+
+	var records []nodeRecord
+	for _, item := range list.Content {
+		var rec nodeRecord
+		if err := item.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("line %d: %v", item.Line, err)
+		}
+		rec.line = item.Line
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// ExportNodes writes the current fabric membership nodes, as returned by
+// List, in the given format, either FormatCSV or FormatYAML.
+//
+// Only leaf and spine nodes are exported, so that the output can be
+// read back in with ImportNodes.
+func (s *FabricMembershipService) ExportNodes(ctx context.Context, w io.Writer, format string) error {
+	nodes, err := s.List(ctx)
+	if err != nil {
+		return fmt.Errorf("export nodes: %v", err)
+	}
+
+	var records []nodeRecord
+	for _, n := range nodes {
+		if n.Role() != "leaf" && n.Role() != "spine" {
+			continue
+		}
+		records = append(records, nodeRecord{
+			Name:   n.Name(),
+			ID:     n.ID(),
+			Pod:    n.Pod(),
+			Serial: n.Serial(),
+			Role:   n.Role(),
+		})
+	}
+
+	switch format {
+	case FormatCSV:
+		err = writeNodesCSV(w, records)
+	case FormatYAML:
+		err = writeNodesYAML(w, records)
+	default:
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("export nodes: %v", err)
+	}
+	return nil
+}
+
+func writeNodesCSV(w io.Writer, records []nodeRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(nodeColumns); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if err := cw.Write([]string{rec.Name, rec.ID, rec.Pod, rec.Serial, rec.Role}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeNodesYAML(w io.Writer, records []nodeRecord) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(records); err != nil {
+		return err
+	}
+	return enc.Close()
+}
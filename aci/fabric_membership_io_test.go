@@ -0,0 +1,125 @@
+package aci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestImportNodesCSV(t *testing.T) {
+	s := &FabricMembershipService{}
+	input := `name, id, pod, serial, role
+leaf-101,101,1,FDO1,leaf
+leaf-102,102,1,FDO2
+spine-201,201,1,FDO3,spine,extra
+leaf-103,103,1,FDO4,router
+leaf-104,101,1,FDO5,leaf
+leaf-105,105,1,FDO1,leaf
+spine-202,202,2,FDO6,spine
+leaf-10"6,106,1,FDO7,leaf
+`
+	nodes, err := s.ImportNodes(strings.NewReader(input), FormatCSV)
+
+	var got []string
+	for _, n := range nodes {
+		got = append(got, n.ID())
+	}
+	if want := "101 202"; strings.Join(got, " ") != want {
+		t.Errorf("imported nodes %v, want %s", got, want)
+	}
+
+	var errs ImportErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v is not an ImportErrors", err)
+	}
+	tests := []struct {
+		line int
+		msg  string
+	}{
+		{3, "expected 5 fields, found 4"},
+		{4, "expected 5 fields, found 6"},
+		{5, "invalid role: router"},
+		{6, "duplicate node id: 101 (line 2)"},
+		{7, "duplicate serial number: FDO1 (line 2)"},
+		{9, `bare " in non-quoted-field`},
+	}
+	if len(errs) != len(tests) {
+		t.Fatalf("got %d line errors, want %d:\n%v", len(errs), len(tests), errs)
+	}
+	for i, tt := range tests {
+		if errs[i].Line != tt.line || errs[i].Err.Error() != tt.msg {
+			t.Errorf("error %d: got %v, want line %d: %s", i, errs[i], tt.line, tt.msg)
+		}
+	}
+}
+
+func TestImportNodesCSVMissingColumn(t *testing.T) {
+	s := &FabricMembershipService{}
+	_, err := s.ImportNodes(strings.NewReader("name,id,pod,serial\nleaf-101,101,1,FDO1\n"), FormatCSV)
+	if err == nil || !strings.Contains(err.Error(), "missing column: role") {
+		t.Errorf("got error %v, want missing column: role", err)
+	}
+}
+
+func TestImportNodesYAML(t *testing.T) {
+	s := &FabricMembershipService{}
+	input := `- name: leaf-101
+  id: "101"
+  pod: "1"
+  serial: FDO1
+  role: leaf
+- name: leaf-102
+  id: "99"
+  pod: "1"
+  serial: FDO2
+  role: leaf
+`
+	nodes, err := s.ImportNodes(strings.NewReader(input), FormatYAML)
+	if len(nodes) != 1 || nodes[0].Name() != "leaf-101" || nodes[0].Pod() != "1" {
+		t.Errorf("imported nodes %v, want leaf-101", nodes)
+	}
+	var errs ImportErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 6 {
+		t.Errorf("got error %v, want an invalid node id on line 6", err)
+	}
+}
+
+func TestImportNodesUnsupportedFormat(t *testing.T) {
+	s := &FabricMembershipService{}
+	if _, err := s.ImportNodes(strings.NewReader(""), "xml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestExportNodesRoundTrip(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/class/fabricNode.json": `{"imdata":[
+			{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-101","id":"101","name":"leaf-101","role":"leaf","serial":"FDO1"}}},
+			{"fabricNode":{"attributes":{"dn":"topology/pod-2/node-201","id":"201","name":"spine-201","role":"spine","serial":"FDO2"}}},
+			{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-1","id":"1","name":"apic1","role":"controller","serial":"FDO3"}}}
+		]}`,
+	})
+
+	for _, format := range []string{FormatCSV, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.FabricMembership.ExportNodes(context.Background(), &buf, format); err != nil {
+				t.Fatal(err)
+			}
+			nodes, err := c.FabricMembership.ImportNodes(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, n := range nodes {
+				got = append(got, strings.Join([]string{n.Name(), n.ID(), n.Pod(), n.Serial(), n.Role()}, ","))
+			}
+			want := "leaf-101,101,1,FDO1,leaf spine-201,201,2,FDO2,spine"
+			if strings.Join(got, " ") != want {
+				t.Errorf("round trip gave %v, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const outOfServicePath = "/api/node/mo/uni/fabric/outofsvc.json"
+
+func newTestNode(t *testing.T, name, id, pod, serial, role string) *Node {
+	t.Helper()
+	node, err := (&FabricMembershipService{}).NewNode(name, id, pod, serial, role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func TestDecommission(t *testing.T) {
+	node := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+
+	tests := []struct {
+		name string
+		call func(c *Client) error
+		want string
+	}{
+		{
+			"maintenance",
+			func(c *Client) error {
+				_, err := c.FabricMembership.Decommission(context.Background(), node, false)
+				return err
+			},
+			`{"fabricRsDecommissionNode":{"attributes":{"tDn":"topology/pod-1/node-101","status":"created,modified","removeFromController":"false"}}}`,
+		},
+		{
+			"remove from controller",
+			func(c *Client) error {
+				_, err := c.FabricMembership.DecommissionNode(context.Background(), node)
+				return err
+			},
+			`{"fabricRsDecommissionNode":{"attributes":{"tDn":"topology/pod-1/node-101","status":"created,modified","removeFromController":"true"}}}`,
+		},
+		{
+			"recommission",
+			func(c *Client) error {
+				_, err := c.FabricMembership.Recommission(context.Background(), node)
+				return err
+			},
+			`{"fabricRsDecommissionNode":{"attributes":{"tDn":"topology/pod-1/node-101","status":"deleted","removeFromController":"false"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, c := newFakeAPIC(t, nil)
+			if err := tt.call(c); err != nil {
+				t.Fatal(err)
+			}
+			posts := f.posted(outOfServicePath)
+			if len(posts) != 1 {
+				t.Fatalf("got %d posts, want 1", len(posts))
+			}
+			jsonEqual(t, posts[0], tt.want)
+		})
+	}
+}
+
+func TestDecommissionError(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	f.setReply(func(method, uri, body string) (int, string) {
+		return http.StatusBadRequest, apicError("102", "configured object not found")
+	})
+	node := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	if _, err := c.FabricMembership.Decommission(context.Background(), node, false); err == nil {
+		t.Error("expected the APIC error")
+	}
+}
+
+func TestState(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/topology/pod-1/node-101.json": `{"imdata":[{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-101","fabricSt":"inactive"}}}]}`,
+	})
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"101", FabricStateInactive},
+		{"102", FabricStateUnknown},
+	}
+	for _, tt := range tests {
+		node := newTestNode(t, "leaf-"+tt.id, tt.id, "1", "FDO"+tt.id, "leaf")
+		st, err := c.FabricMembership.State(context.Background(), node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st != tt.want {
+			t.Errorf("node %s: got state %s, want %s", tt.id, st, tt.want)
+		}
+	}
+}
+
+// pollStates replies to each poll of node 101 with the next of the
+// given states, repeating the last.
+func pollStates(f *fakeAPIC, states ...string) {
+	var polls int
+	f.setReply(func(method, uri, body string) (int, string) {
+		if method != http.MethodGet || !strings.HasPrefix(uri, "/api/node/mo/topology/pod-1/node-101.json") {
+			return 0, ""
+		}
+		st := states[len(states)-1]
+		if polls < len(states) {
+			st = states[polls]
+		}
+		polls++
+		return http.StatusOK, `{"imdata":[{"fabricNode":{"attributes":{"fabricSt":"` + st + `"}}}]}`
+	})
+}
+
+func TestWaitForState(t *testing.T) {
+	defer func(d time.Duration) { statePollInterval = d }(statePollInterval)
+	statePollInterval = time.Millisecond
+
+	f, c := newFakeAPIC(t, nil)
+	pollStates(f, FabricStateDisabled, FabricStateDiscovering, FabricStateActive)
+	node := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+
+	if err := c.FabricMembership.WaitForState(context.Background(), node, FabricStateActive); err != nil {
+		t.Fatal(err)
+	}
+	if node.FabricState() != FabricStateActive {
+		t.Errorf("got fabric state %s, want %s", node.FabricState(), FabricStateActive)
+	}
+}
+
+func TestWaitForStateTimeout(t *testing.T) {
+	defer func(d time.Duration) { statePollInterval = d }(statePollInterval)
+	statePollInterval = time.Millisecond
+
+	f, c := newFakeAPIC(t, nil)
+	pollStates(f, FabricStateDiscovering)
+	node := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := c.FabricMembership.WaitForState(ctx, node, FabricStateActive)
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("got error %v, want a timeout", err)
+	}
+	if node.FabricState() != FabricStateDiscovering {
+		t.Errorf("got fabric state %s, want %s", node.FabricState(), FabricStateDiscovering)
+	}
+}
+
+const nodeIdentityPath = "/api/node/mo/uni/controller/nodeidentpol.json"
+
+// replaceAPIC fakes the fabric during the replacement of leaf 101,
+// rejecting the registration of FDO2 if rejectRegister is set, and
+// reporting FDO2 active once registered unless neverActive is set.
+func replaceAPIC(t *testing.T, rejectRegister, neverActive bool) (*fakeAPIC, *Client) {
+	f, c := newFakeAPIC(t, nil)
+	var registered bool
+	f.setReply(func(method, uri, body string) (int, string) {
+		switch {
+		case method == http.MethodPost && identityPost(body) == "FDO2 "+createdModified:
+			if rejectRegister {
+				return http.StatusBadRequest, apicError("103", "serial number already registered")
+			}
+			registered = true
+		case method == http.MethodGet && strings.HasPrefix(uri, "/api/node/mo/topology/pod-1/node-101.json"):
+			// the old switch is gone once decommissioned
+			if registered && !neverActive {
+				return http.StatusOK, `{"imdata":[{"fabricNode":{"attributes":{"fabricSt":"active"}}}]}`
+			}
+			return http.StatusOK, `{"imdata":[]}`
+		}
+		return 0, ""
+	})
+	return f, c
+}
+
+// identityPost returns the serial number and status of the single node
+// identity posted in body, or an empty string if there isn't one.
+func identityPost(body string) string {
+	var nc NodeIdentProfContainer
+	if err := json.Unmarshal([]byte(body), &nc); err != nil || len(nc.Children) != 1 {
+		return ""
+	}
+	return nc.Children[0].Serial + " " + nc.Children[0].Status
+}
+
+func TestReplace(t *testing.T) {
+	defer func(d time.Duration) { statePollInterval = d }(statePollInterval)
+	statePollInterval = time.Millisecond
+
+	tests := []struct {
+		name           string
+		rejectRegister bool
+		neverActive    bool
+		steps          []string
+		identities     []string
+		err            string
+	}{
+		{
+			name:       "success",
+			steps:      []string{"decommission FDO1", "remove identity FDO1", "register FDO2", "discover FDO2"},
+			identities: []string{"FDO1 deleted", "FDO2 created,modified"},
+		},
+		{
+			name:           "register rejected",
+			rejectRegister: true,
+			steps:          []string{"decommission FDO1", "remove identity FDO1", "register FDO2", "rollback FDO2"},
+			identities:     []string{"FDO1 deleted", "FDO2 deleted", "FDO1 created,modified"},
+			err:            "register",
+		},
+		{
+			name:        "never discovered",
+			neverActive: true,
+			steps:       []string{"decommission FDO1", "remove identity FDO1", "register FDO2", "discover FDO2", "rollback FDO2"},
+			identities:  []string{"FDO1 deleted", "FDO2 created,modified", "FDO2 deleted", "FDO1 created,modified"},
+			err:         "discover",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, c := replaceAPIC(t, tt.rejectRegister, tt.neverActive)
+			old := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+
+			var steps []string
+			progress := func(step string, node *Node) {
+				steps = append(steps, step+" "+node.Serial())
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			node, err := c.FabricMembership.Replace(ctx, old, "FDO2", progress)
+
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if node.Serial() != "FDO2" || node.ID() != "101" || node.FabricState() != FabricStateActive {
+					t.Errorf("got node %s in state %s", node, node.FabricState())
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want a failed %s", err, tt.err)
+			}
+
+			if !reflect.DeepEqual(steps, tt.steps) {
+				t.Errorf("got steps %q, want %q", steps, tt.steps)
+			}
+			var identities []string
+			for _, body := range f.posted(nodeIdentityPath) {
+				identities = append(identities, identityPost(body))
+			}
+			if !reflect.DeepEqual(identities, tt.identities) {
+				t.Errorf("posted identities %q, want %q", identities, tt.identities)
+			}
+			if len(f.posted(outOfServicePath)) != 1 {
+				t.Errorf("got %d decommissions, want 1", len(f.posted(outOfServicePath)))
+			}
+			if old.Status() != "" || old.FabricState() != "" {
+				t.Errorf("old node modified: status %q, fabric state %q", old.Status(), old.FabricState())
+			}
+		})
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	f.setReply(func(method, uri, body string) (int, string) {
+		if strings.Contains(body, `"serial":"FDO103"`) {
+			return http.StatusBadRequest, apicError("103", "node id already registered")
+		}
+		return 0, ""
+	})
+	var nodes []*Node
+	for _, id := range []string{"101", "102", "103", "104", "105"} {
+		node := newTestNode(t, "leaf-"+id, id, "1", "FDO"+id, "leaf")
+		node.SetCreated()
+		nodes = append(nodes, node)
+	}
+
+	results, err := c.FabricMembership.BatchUpdate(context.Background(), 4, nodes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(nodes) {
+		t.Fatalf("got %d results, want %d", len(results), len(nodes))
+	}
+	for i, r := range results {
+		if r.Node != nodes[i] {
+			t.Errorf("result %d is for node %s, want %s", i, r.Node, nodes[i])
+		}
+		if got, want := r.OK(), r.Node.ID() != "103"; got != want {
+			t.Errorf("node %s: got ok %t, want %t", r.Node.ID(), got, want)
+		}
+	}
+	if r := results[2]; r.Code != "103" || r.Text != "node id already registered" {
+		t.Errorf("node 103: got code %q text %q", r.Code, r.Text)
+	}
+
+	// the first chunk is bisected down to the rejected node,
+	// the second chunk is sent as is
+	var sent []string
+	for _, body := range f.posted(nodeIdentityPath) {
+		var nc NodeIdentProfContainer
+		if err := json.Unmarshal([]byte(body), &nc); err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, child := range nc.Children {
+			ids = append(ids, child.NodeID)
+		}
+		sent = append(sent, strings.Join(ids, ","))
+	}
+	if want := []string{"101,102", "104", "105"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("got updates %q, want %q", sent, want)
+	}
+}
+
+func TestCheckPods(t *testing.T) {
+	f, c := newFakeAPIC(t, podFixtures)
+	leaf := newTestNode(t, "leaf-401", "401", "4", "FDO1", "leaf")
+	leaf.SetCreated()
+
+	err := c.FabricMembership.CheckPods(context.Background(), leaf)
+	if err == nil || !strings.Contains(err.Error(), "pod 4 does not exist") {
+		t.Errorf("got error %v, want pod 4 does not exist", err)
+	}
+
+	// existing pods, and nodes being removed from missing ones, pass
+	removed := newTestNode(t, "leaf-402", "402", "4", "FDO2", "leaf")
+	removed.SetDeleted()
+	var nodes []*Node
+	for _, pod := range []string{"1", "2", "3"} {
+		node := newTestNode(t, "leaf-10"+pod, "10"+pod, pod, "FDO10"+pod, "leaf")
+		node.SetCreated()
+		nodes = append(nodes, node)
+	}
+	if err := c.FabricMembership.CheckPods(context.Background(), append(nodes, removed)...); err != nil {
+		t.Error(err)
+	}
+
+	// Update itself does not check pods
+	if _, err := c.FabricMembership.Update(context.Background(), leaf); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(f.posted(nodeIdentityPath)); got != 1 {
+		t.Errorf("got %d posts, want 1", got)
+	}
+}
@@ -0,0 +1,536 @@
+package aci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// outOfBandEPG is the distinguished name of the default
+// out-of-band management EPG.
+const outOfBandEPG = "uni/tn-mgmt/mgmtp-default/oob-default"
+
+// managementEPG is the management EPG through which the fabric
+// reaches the servers of a fabric policy, out-of-band by default.
+type managementEPG struct {
+	inBand string
+}
+
+// ManagementEPG returns the distinguished name of the management EPG.
+func (m *managementEPG) ManagementEPG() string {
+	if m.inBand == "" {
+		return outOfBandEPG
+	}
+	return fmt.Sprintf("uni/tn-mgmt/mgmtp-default/inb-%s", m.inBand)
+}
+
+// SetInBandEPG validates and sets the in-band management EPG
+// as the management EPG.
+func (m *managementEPG) SetInBandEPG(epg string) error {
+	if err := validateName("in-band epg name", epg); err != nil {
+		return err
+	}
+	m.inBand = epg
+	return nil
+}
+
+// SetOutOfBandEPG sets the default out-of-band management EPG
+// as the management EPG.
+func (m *managementEPG) SetOutOfBandEPG() {
+	m.inBand = ""
+}
+
+// setManagementEPG sets the management EPG from its distinguished name.
+func (m *managementEPG) setManagementEPG(dn string) {
+	m.inBand = strings.TrimPrefix(dn, "uni/tn-mgmt/mgmtp-default/inb-")
+	if m.inBand == dn {
+		m.inBand = ""
+	}
+}
+
+// NTPPolicy is a date and time policy, the NTP servers
+// the fabric nodes synchronise their clocks with.
+type NTPPolicy struct {
+	object
+	managementEPG
+	enabled bool
+	servers []*NTPServer
+}
+
+// DN returns the distinguished name of the NTP policy.
+func (p *NTPPolicy) DN() string {
+	return ntpPolicyDN(p.name)
+}
+
+// Enabled returns whether NTP is enabled.
+func (p *NTPPolicy) Enabled() bool {
+	return p.enabled
+}
+
+// SetEnabled sets whether NTP is enabled.
+func (p *NTPPolicy) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+// Servers returns the NTP servers of the policy.
+func (p *NTPPolicy) Servers() []*NTPServer {
+	return p.servers
+}
+
+// AddServer adds an NTP server to the policy,
+// replacing any server with the same host.
+func (p *NTPPolicy) AddServer(server *NTPServer) {
+	for i, s := range p.servers {
+		if s.Name() == server.Name() {
+			p.servers[i] = server
+			return
+		}
+	}
+	p.servers = append(p.servers, server)
+}
+
+// String returns the string representation of an NTP policy
+func (p *NTPPolicy) String() string {
+	return p.name
+}
+
+// NTPServer is an NTP server, named by its hostname or IP address.
+type NTPServer struct {
+	object
+	preferred bool
+}
+
+// Preferred returns whether the server is preferred.
+func (s *NTPServer) Preferred() bool {
+	return s.preferred
+}
+
+// SetPreferred sets whether the server is preferred.
+func (s *NTPServer) SetPreferred(preferred bool) {
+	s.preferred = preferred
+}
+
+// String returns the string representation of an NTP server
+func (s *NTPServer) String() string {
+	return s.name
+}
+
+// DateTimeFormat is the format in which the fabric displays dates and
+// times. There is only one, named "default".
+type DateTimeFormat struct {
+	timezone   string
+	display    string
+	showOffset bool
+}
+
+// Timezone returns the timezone of the format.
+func (f *DateTimeFormat) Timezone() string {
+	return f.timezone
+}
+
+// SetTimezone validates and sets the timezone of the format.
+//
+// A timezone is its offset from UTC in minutes, prefixed with "p" when
+// positive or "n" when negative, and its name with "/" as "-", such as
+// "p0_UTC", "p60_Europe-Paris" or "n300_America-New_York".
+func (f *DateTimeFormat) SetTimezone(timezone string) error {
+	if !timezonePattern.MatchString(timezone) {
+		return fmt.Errorf("invalid timezone: %s", timezone)
+	}
+	f.timezone = timezone
+	return nil
+}
+
+// timezonePattern matches a timezone, such as "p60_Europe-Paris".
+var timezonePattern = regexp.MustCompile(`^[pn][0-9]{1,3}_[a-zA-Z0-9_+-]+$`)
+
+// Display returns how dates and times are displayed, "local" or "utc".
+func (f *DateTimeFormat) Display() string {
+	return f.display
+}
+
+// SetDisplay sets how dates and times are displayed.
+// Can only be "local" or "utc".
+func (f *DateTimeFormat) SetDisplay(display string) error {
+	if display != "local" && display != "utc" {
+		return fmt.Errorf("invalid display format: %s", display)
+	}
+	f.display = display
+	return nil
+}
+
+// ShowOffset returns whether the offset from UTC is displayed.
+func (f *DateTimeFormat) ShowOffset() bool {
+	return f.showOffset
+}
+
+// SetShowOffset sets whether the offset from UTC is displayed.
+func (f *DateTimeFormat) SetShowOffset(show bool) {
+	f.showOffset = show
+}
+
+// String returns the string representation of a date and time format
+func (f *DateTimeFormat) String() string {
+	return fmt.Sprintf("%s (%s)", f.timezone, f.display)
+}
+
+// DNSProfile is a DNS profile, the DNS servers and domains used by the
+// fabric nodes in the VRFs labelled with it.
+type DNSProfile struct {
+	object
+	managementEPG
+	servers []*DNSServer
+	domains []*DNSDomain
+}
+
+// DN returns the distinguished name of the DNS profile.
+func (p *DNSProfile) DN() string {
+	return dnsProfileDN(p.name)
+}
+
+// Servers returns the DNS servers of the profile.
+func (p *DNSProfile) Servers() []*DNSServer {
+	return p.servers
+}
+
+// AddServer adds a DNS server to the profile,
+// replacing any server with the same address.
+func (p *DNSProfile) AddServer(server *DNSServer) {
+	for i, s := range p.servers {
+		if s.Name() == server.Name() {
+			p.servers[i] = server
+			return
+		}
+	}
+	p.servers = append(p.servers, server)
+}
+
+// Domains returns the DNS domains of the profile.
+func (p *DNSProfile) Domains() []*DNSDomain {
+	return p.domains
+}
+
+// AddDomain adds a DNS domain to the profile,
+// replacing any domain of the same name.
+func (p *DNSProfile) AddDomain(domain *DNSDomain) {
+	for i, d := range p.domains {
+		if d.Name() == domain.Name() {
+			p.domains[i] = domain
+			return
+		}
+	}
+	p.domains = append(p.domains, domain)
+}
+
+// String returns the string representation of a DNS profile
+func (p *DNSProfile) String() string {
+	return p.name
+}
+
+// DNSServer is a DNS server, named by its IP address.
+type DNSServer struct {
+	object
+	preferred bool
+}
+
+// Preferred returns whether the server is preferred.
+func (s *DNSServer) Preferred() bool {
+	return s.preferred
+}
+
+// SetPreferred sets whether the server is preferred.
+func (s *DNSServer) SetPreferred(preferred bool) {
+	s.preferred = preferred
+}
+
+// String returns the string representation of a DNS server
+func (s *DNSServer) String() string {
+	return s.name
+}
+
+// DNSDomain is a DNS search domain.
+type DNSDomain struct {
+	object
+	isDefault bool
+}
+
+// Default returns whether the domain is the default domain.
+func (d *DNSDomain) Default() bool {
+	return d.isDefault
+}
+
+// SetDefault sets whether the domain is the default domain.
+func (d *DNSDomain) SetDefault(isDefault bool) {
+	d.isDefault = isDefault
+}
+
+// String returns the string representation of a DNS domain
+func (d *DNSDomain) String() string {
+	return d.name
+}
+
+// SNMPPolicy is an SNMP policy, the communities and client groups
+// allowed to query the fabric nodes.
+type SNMPPolicy struct {
+	object
+	enabled      bool
+	contact      string
+	location     string
+	communities  []*SNMPCommunity
+	clientGroups []*SNMPClientGroup
+}
+
+// DN returns the distinguished name of the SNMP policy.
+func (p *SNMPPolicy) DN() string {
+	return snmpPolicyDN(p.name)
+}
+
+// Enabled returns whether SNMP is enabled.
+func (p *SNMPPolicy) Enabled() bool {
+	return p.enabled
+}
+
+// SetEnabled sets whether SNMP is enabled.
+func (p *SNMPPolicy) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+// Contact returns the system contact of the policy.
+func (p *SNMPPolicy) Contact() string {
+	return p.contact
+}
+
+// SetContact validates and sets the system contact of the policy.
+//
+// A contact can be up to 255 characters, and cannot contain
+// quotes or backslashes.
+func (p *SNMPPolicy) SetContact(contact string) error {
+	if len(contact) > 255 || strings.ContainsAny(contact, `"'\`) {
+		return fmt.Errorf("invalid contact: %s", contact)
+	}
+	p.contact = contact
+	return nil
+}
+
+// Location returns the system location of the policy.
+func (p *SNMPPolicy) Location() string {
+	return p.location
+}
+
+// SetLocation validates and sets the system location of the policy,
+// following the same rules as SetContact.
+func (p *SNMPPolicy) SetLocation(location string) error {
+	if len(location) > 255 || strings.ContainsAny(location, `"'\`) {
+		return fmt.Errorf("invalid location: %s", location)
+	}
+	p.location = location
+	return nil
+}
+
+// Communities returns the communities of the policy.
+func (p *SNMPPolicy) Communities() []*SNMPCommunity {
+	return p.communities
+}
+
+// AddCommunity adds a community to the policy,
+// replacing any community of the same name.
+func (p *SNMPPolicy) AddCommunity(community *SNMPCommunity) {
+	for i, c := range p.communities {
+		if c.Name() == community.Name() {
+			p.communities[i] = community
+			return
+		}
+	}
+	p.communities = append(p.communities, community)
+}
+
+// ClientGroups returns the client groups of the policy.
+func (p *SNMPPolicy) ClientGroups() []*SNMPClientGroup {
+	return p.clientGroups
+}
+
+// AddClientGroup adds a client group to the policy,
+// replacing any client group of the same name.
+func (p *SNMPPolicy) AddClientGroup(group *SNMPClientGroup) {
+	for i, g := range p.clientGroups {
+		if g.Name() == group.Name() {
+			p.clientGroups[i] = group
+			return
+		}
+	}
+	p.clientGroups = append(p.clientGroups, group)
+}
+
+// String returns the string representation of an SNMP policy
+func (p *SNMPPolicy) String() string {
+	return p.name
+}
+
+// SNMPCommunity is an SNMP community, named by its community string.
+type SNMPCommunity struct {
+	object
+}
+
+// String returns the string representation of an SNMP community
+func (c *SNMPCommunity) String() string {
+	return c.name
+}
+
+// SNMPClientGroup is a group of SNMP clients, and the management
+// EPG through which they reach the fabric nodes.
+type SNMPClientGroup struct {
+	object
+	managementEPG
+	clients []*SNMPClient
+}
+
+// Clients returns the clients of the client group.
+func (g *SNMPClientGroup) Clients() []*SNMPClient {
+	return g.clients
+}
+
+// AddClient adds a client to the client group,
+// replacing any client with the same address.
+func (g *SNMPClientGroup) AddClient(client *SNMPClient) {
+	for i, c := range g.clients {
+		if c.Name() == client.Name() {
+			g.clients[i] = client
+			return
+		}
+	}
+	g.clients = append(g.clients, client)
+}
+
+// String returns the string representation of an SNMP client group
+func (g *SNMPClientGroup) String() string {
+	return g.name
+}
+
+// SNMPClient is an SNMP client, named by its IP address.
+type SNMPClient struct {
+	object
+}
+
+// String returns the string representation of an SNMP client
+func (c *SNMPClient) String() string {
+	return c.name
+}
+
+// Syslog severities, from the most to the least severe.
+var syslogSeverities = []string{
+	"emergencies",
+	"alerts",
+	"critical",
+	"errors",
+	"warnings",
+	"notifications",
+	"information",
+	"debugging",
+}
+
+// validateSeverity validates a syslog severity.
+func validateSeverity(severity string) error {
+	for _, s := range syslogSeverities {
+		if s == severity {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid severity: %s", severity)
+}
+
+// SyslogGroup is a syslog monitoring destination group,
+// the syslog servers the fabric sends its messages to.
+type SyslogGroup struct {
+	object
+	destinations []*SyslogDestination
+}
+
+// DN returns the distinguished name of the syslog group.
+func (g *SyslogGroup) DN() string {
+	return syslogGroupDN(g.name)
+}
+
+// Destinations returns the remote destinations of the syslog group.
+func (g *SyslogGroup) Destinations() []*SyslogDestination {
+	return g.destinations
+}
+
+// AddDestination adds a remote destination to the syslog group,
+// replacing any destination with the same host.
+func (g *SyslogGroup) AddDestination(dest *SyslogDestination) {
+	for i, d := range g.destinations {
+		if d.Name() == dest.Name() {
+			g.destinations[i] = dest
+			return
+		}
+	}
+	g.destinations = append(g.destinations, dest)
+}
+
+// String returns the string representation of a syslog group
+func (g *SyslogGroup) String() string {
+	return g.name
+}
+
+// SyslogDestination is a remote syslog server, named by its hostname
+// or IP address, and the messages it is sent.
+type SyslogDestination struct {
+	object
+	managementEPG
+	port     int
+	severity string
+	facility string
+}
+
+// Port returns the UDP port of the destination.
+func (d *SyslogDestination) Port() int {
+	return d.port
+}
+
+// SetPort validates and sets the UDP port of the destination.
+func (d *SyslogDestination) SetPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	d.port = port
+	return nil
+}
+
+// Severity returns the least severe level of message sent to the destination.
+func (d *SyslogDestination) Severity() string {
+	return d.severity
+}
+
+// SetSeverity validates and sets the least severe level of message sent
+// to the destination. Can only be "emergencies", "alerts", "critical",
+// "errors", "warnings", "notifications", "information" or "debugging".
+func (d *SyslogDestination) SetSeverity(severity string) error {
+	if err := validateSeverity(severity); err != nil {
+		return err
+	}
+	d.severity = severity
+	return nil
+}
+
+// Facility returns the facility of the messages sent to the destination.
+func (d *SyslogDestination) Facility() string {
+	return d.facility
+}
+
+// SetFacility validates and sets the facility of the messages sent to
+// the destination. Can only be "local0" to "local7".
+func (d *SyslogDestination) SetFacility(facility string) error {
+	if !facilityPattern.MatchString(facility) {
+		return fmt.Errorf("invalid facility: %s", facility)
+	}
+	d.facility = facility
+	return nil
+}
+
+// facilityPattern matches a syslog facility, "local0" to "local7".
+var facilityPattern = regexp.MustCompile(`^local[0-7]$`)
+
+// String returns the string representation of a syslog destination
+func (d *SyslogDestination) String() string {
+	return fmt.Sprintf("%s:%d", d.name, d.port)
+}
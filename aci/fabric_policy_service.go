@@ -0,0 +1,886 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// FabricContainer is a container for any object of the fabric policies.
+// Only one of its fields is set.
+type FabricContainer struct {
+	DatetimePol        *FabricObject `json:"datetimePol,omitempty"`
+	DatetimeNtpProv    *FabricObject `json:"datetimeNtpProv,omitempty"`
+	RsNtpProvToEpg     *Relation     `json:"datetimeRsNtpProvToEpg,omitempty"`
+	DatetimeFormat     *FabricObject `json:"datetimeFormat,omitempty"`
+	DNSProfile         *FabricObject `json:"dnsProfile,omitempty"`
+	DNSProv            *FabricObject `json:"dnsProv,omitempty"`
+	DNSDomain          *FabricObject `json:"dnsDomain,omitempty"`
+	RsProfileToEpg     *Relation     `json:"dnsRsProfileToEpg,omitempty"`
+	DNSLbl             *FabricObject `json:"dnsLbl,omitempty"`
+	SnmpPol            *FabricObject `json:"snmpPol,omitempty"`
+	SnmpCommunityP     *FabricObject `json:"snmpCommunityP,omitempty"`
+	SnmpClientGrpP     *FabricObject `json:"snmpClientGrpP,omitempty"`
+	SnmpClientP        *FabricObject `json:"snmpClientP,omitempty"`
+	SnmpRsEpg          *Relation     `json:"snmpRsEpg,omitempty"`
+	SyslogGroup        *FabricObject `json:"syslogGroup,omitempty"`
+	SyslogRemoteDest   *FabricObject `json:"syslogRemoteDest,omitempty"`
+	RsARemoteHostToEpg *Relation     `json:"fileRsARemoteHostToEpg,omitempty"`
+	SyslogSrc          *FabricObject `json:"syslogSrc,omitempty"`
+	SyslogRsDestGroup  *Relation     `json:"syslogRsDestGroup,omitempty"`
+	PodPGrp            *FabricObject `json:"fabricPodPGrp,omitempty"`
+	RsTimePol          *Relation     `json:"fabricRsTimePol,omitempty"`
+	RsSnmpPol          *Relation     `json:"fabricRsSnmpPol,omitempty"`
+	RsPodPGrpIsisDomP  *Relation     `json:"fabricRsPodPGrpIsisDomP,omitempty"`
+	RsPodPGrpCoopP     *Relation     `json:"fabricRsPodPGrpCoopP,omitempty"`
+	RsPodPGrpBGPRRP    *Relation     `json:"fabricRsPodPGrpBGPRRP,omitempty"`
+	RsCommPol          *Relation     `json:"fabricRsCommPol,omitempty"`
+	RsMacsecPol        *Relation     `json:"fabricRsMacsecPol,omitempty"`
+	RsPodPGrp          *Relation     `json:"fabricRsPodPGrp,omitempty"`
+	BgpInstPol         *FabricObject `json:"bgpInstPol,omitempty"`
+	BgpAsP             *FabricObject `json:"bgpAsP,omitempty"`
+	BgpRRP             *FabricObject `json:"bgpRRP,omitempty"`
+	BgpRRNodePEp       *FabricObject `json:"bgpRRNodePEp,omitempty"`
+}
+
+// FabricObject is any object of the fabric policies
+type FabricObject struct {
+	FabricAttrs `json:"attributes"`
+	Children    []FabricContainer `json:"children,omitempty"`
+}
+
+// FabricAttrs contains the attributes of the fabric policy objects
+type FabricAttrs struct {
+	Addr               string `json:"addr,omitempty"`
+	AdminSt            string `json:"adminSt,omitempty"`
+	AdminState         string `json:"adminState,omitempty"`
+	Asn                string `json:"asn,omitempty"`
+	Contact            string `json:"contact,omitempty"`
+	Descr              string `json:"descr,omitempty"`
+	DisplayFormat      string `json:"displayFormat,omitempty"`
+	DN                 string `json:"dn,omitempty"`
+	Format             string `json:"format,omitempty"`
+	ForwardingFacility string `json:"forwardingFacility,omitempty"`
+	Host               string `json:"host,omitempty"`
+	ID                 string `json:"id,omitempty"`
+	Incl               string `json:"incl,omitempty"`
+	IsDefault          string `json:"isDefault,omitempty"`
+	Loc                string `json:"loc,omitempty"`
+	MinSev             string `json:"minSev,omitempty"`
+	Name               string `json:"name,omitempty"`
+	PodID              string `json:"podId,omitempty"`
+	Port               string `json:"port,omitempty"`
+	Preferred          string `json:"preferred,omitempty"`
+	Severity           string `json:"severity,omitempty"`
+	ShowOffset         string `json:"showOffset,omitempty"`
+	Status             string `json:"status,omitempty"`
+	Tz                 string `json:"tz,omitempty"`
+}
+
+// FabricResponse contains the response for fabric policy requests
+type FabricResponse struct {
+	TotalCount string            `json:"totalCount"`
+	Imdata     []FabricContainer `json:"imdata"`
+}
+
+// FabricPolicyService handles communication with the fabric policy
+// related methods of the APIC API, configuring the date and time,
+// DNS, SNMP, syslog and BGP of the fabric.
+type FabricPolicyService service
+
+// ntpPolicyDN returns the distinguished name of an NTP policy.
+func ntpPolicyDN(name string) string {
+	return fmt.Sprintf("uni/fabric/time-%s", name)
+}
+
+// dnsProfileDN returns the distinguished name of a DNS profile.
+func dnsProfileDN(name string) string {
+	return fmt.Sprintf("uni/fabric/dnsp-%s", name)
+}
+
+// snmpPolicyDN returns the distinguished name of an SNMP policy.
+func snmpPolicyDN(name string) string {
+	return fmt.Sprintf("uni/fabric/snmppol-%s", name)
+}
+
+// syslogGroupDN returns the distinguished name of a syslog group.
+func syslogGroupDN(name string) string {
+	return fmt.Sprintf("uni/fabric/slgroup-%s", name)
+}
+
+// dateTimeFormatDN is the distinguished name of the date and time format.
+const dateTimeFormatDN = "uni/fabric/format-default"
+
+// NewNTPPolicy instantiates a valid, enabled NTP policy,
+// reaching its servers out-of-band.
+func (s *FabricPolicyService) NewNTPPolicy(name string) (*NTPPolicy, error) {
+	p := &NTPPolicy{enabled: true}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewNTPServer instantiates a valid NTP server.
+func (s *FabricPolicyService) NewNTPServer(host string, preferred bool) (*NTPServer, error) {
+	server := &NTPServer{preferred: preferred}
+	if err := validateHost("ntp server", host); err != nil {
+		return server, err
+	}
+	server.name = host
+	return server, nil
+}
+
+// NewDateTimeFormat instantiates a valid date and time format of the
+// given timezone, displaying local times with their offset from UTC.
+func (s *FabricPolicyService) NewDateTimeFormat(timezone string) (*DateTimeFormat, error) {
+	f := &DateTimeFormat{display: "local", showOffset: true}
+	if err := f.SetTimezone(timezone); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// NewDNSProfile instantiates a valid DNS profile,
+// reaching its servers out-of-band.
+func (s *FabricPolicyService) NewDNSProfile(name string) (*DNSProfile, error) {
+	p := &DNSProfile{}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewDNSServer instantiates a valid DNS server.
+func (s *FabricPolicyService) NewDNSServer(address string, preferred bool) (*DNSServer, error) {
+	server := &DNSServer{preferred: preferred}
+	if net.ParseIP(address) == nil {
+		return server, fmt.Errorf("invalid dns server: %s", address)
+	}
+	server.name = address
+	return server, nil
+}
+
+// NewDNSDomain instantiates a valid DNS domain.
+func (s *FabricPolicyService) NewDNSDomain(name string, isDefault bool) (*DNSDomain, error) {
+	d := &DNSDomain{isDefault: isDefault}
+	if err := validateHost("dns domain", name); err != nil {
+		return d, err
+	}
+	d.name = name
+	return d, nil
+}
+
+// NewSNMPPolicy instantiates a valid, enabled SNMP policy.
+func (s *FabricPolicyService) NewSNMPPolicy(name string) (*SNMPPolicy, error) {
+	p := &SNMPPolicy{enabled: true}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewSNMPCommunity instantiates a valid SNMP community.
+func (s *FabricPolicyService) NewSNMPCommunity(name string) (*SNMPCommunity, error) {
+	c := &SNMPCommunity{}
+	if err := validateName("snmp community", name); err != nil {
+		return c, err
+	}
+	c.name = name
+	return c, nil
+}
+
+// NewSNMPClientGroup instantiates a valid SNMP client group,
+// reaching the fabric out-of-band.
+func (s *FabricPolicyService) NewSNMPClientGroup(name string) (*SNMPClientGroup, error) {
+	g := &SNMPClientGroup{}
+	if err := g.SetName(name); err != nil {
+		return g, err
+	}
+	return g, nil
+}
+
+// NewSNMPClient instantiates a valid SNMP client.
+func (s *FabricPolicyService) NewSNMPClient(address string) (*SNMPClient, error) {
+	c := &SNMPClient{}
+	if net.ParseIP(address) == nil {
+		return c, fmt.Errorf("invalid snmp client: %s", address)
+	}
+	c.name = address
+	return c, nil
+}
+
+// NewSyslogGroup instantiates a valid syslog group.
+func (s *FabricPolicyService) NewSyslogGroup(name string) (*SyslogGroup, error) {
+	g := &SyslogGroup{}
+	if err := g.SetName(name); err != nil {
+		return g, err
+	}
+	return g, nil
+}
+
+// NewSyslogDestination instantiates a valid syslog destination on port 514,
+// sent warnings and more severe messages with the local7 facility,
+// and reached out-of-band.
+func (s *FabricPolicyService) NewSyslogDestination(host string) (*SyslogDestination, error) {
+	d := &SyslogDestination{port: 514, severity: "warnings", facility: "local7"}
+	if err := validateHost("syslog destination", host); err != nil {
+		return d, err
+	}
+	d.name = host
+	return d, nil
+}
+
+// getFabric retrieves the fabric policy objects of the given path.
+func (s *FabricPolicyService) getFabric(ctx context.Context, path string) ([]FabricContainer, error) {
+	var fr FabricResponse
+	if err := s.client.get(ctx, path, &fr); err != nil {
+		return nil, err
+	}
+	return fr.Imdata, nil
+}
+
+// listClass retrieves every fabric policy object of a class,
+// with its children.
+func (s *FabricPolicyService) listClass(ctx context.Context, class string) ([]FabricContainer, error) {
+	return s.getFabric(ctx, fmt.Sprintf("api/node/class/%s.json?rsp-subtree=full", class))
+}
+
+// postFabric posts a fabric policy object to its distinguished name.
+func (s *FabricPolicyService) postFabric(ctx context.Context, dn string, payload FabricContainer) (FabricResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", dn)
+	var fr FabricResponse
+	err := s.client.post(ctx, path, payload, &fr)
+	return fr, err
+}
+
+func newNTPPolicyContainer(p *NTPPolicy) FabricContainer {
+	o := &FabricObject{
+		FabricAttrs: FabricAttrs{
+			DN:      p.DN(),
+			Name:    p.Name(),
+			Descr:   p.Description(),
+			AdminSt: adminState(p.Enabled()),
+			Status:  p.Status(),
+		},
+	}
+
+	// children are implicitly removed with their policy
+	if p.Status() == deleted {
+		return FabricContainer{DatetimePol: o}
+	}
+
+	for _, server := range p.Servers() {
+		prov := &FabricObject{
+			FabricAttrs: FabricAttrs{
+				Name:      server.Name(),
+				Preferred: yesNo(server.Preferred()),
+				Status:    server.Status(),
+			},
+		}
+		if server.Status() != deleted {
+			prov.Children = append(prov.Children, FabricContainer{
+				RsNtpProvToEpg: newRelation(RelationAttrs{TDN: p.ManagementEPG()}),
+			})
+		}
+		o.Children = append(o.Children, FabricContainer{DatetimeNtpProv: prov})
+	}
+
+	return FabricContainer{DatetimePol: o}
+}
+
+// CreateNTPPolicy creates an NTP policy.
+func (s *FabricPolicyService) CreateNTPPolicy(ctx context.Context, p *NTPPolicy) (FabricResponse, error) {
+	p.SetCreated()
+	return s.UpdateNTPPolicy(ctx, p)
+}
+
+// DeleteNTPPolicy deletes an NTP policy.
+func (s *FabricPolicyService) DeleteNTPPolicy(ctx context.Context, p *NTPPolicy) (FabricResponse, error) {
+	p.SetDeleted()
+	return s.UpdateNTPPolicy(ctx, p)
+}
+
+// UpdateNTPPolicy creates, modifies or deletes an NTP policy according to
+// its status, along with each of its servers according to theirs.
+func (s *FabricPolicyService) UpdateNTPPolicy(ctx context.Context, p *NTPPolicy) (FabricResponse, error) {
+	return s.postFabric(ctx, p.DN(), newNTPPolicyContainer(p))
+}
+
+// GetNTPPolicy retrieves an NTP policy.
+func (s *FabricPolicyService) GetNTPPolicy(ctx context.Context, name string) (*NTPPolicy, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", ntpPolicyDN(name))
+	fc, err := s.getFabric(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get ntp policy: %v", err)
+	}
+	if len(fc) == 0 || fc[0].DatetimePol == nil {
+		return nil, fmt.Errorf("get ntp policy: %s not found", name)
+	}
+	return ntpPolicyFromResponse(fc[0].DatetimePol), nil
+}
+
+// ListNTPPolicies lists all NTP policies.
+func (s *FabricPolicyService) ListNTPPolicies(ctx context.Context) ([]*NTPPolicy, error) {
+	fc, err := s.listClass(ctx, "datetimePol")
+	if err != nil {
+		return nil, fmt.Errorf("list ntp policies: %v", err)
+	}
+
+	var policies []*NTPPolicy
+	for _, c := range fc {
+		if c.DatetimePol != nil {
+			policies = append(policies, ntpPolicyFromResponse(c.DatetimePol))
+		}
+	}
+	return policies, nil
+}
+
+// fabricObject returns the object of a fabric policy returned by the
+// APIC. We trust the APIC to return valid policy attributes throughout.
+func fabricObject(o *FabricObject) object {
+	return object{name: o.Name, description: o.Descr, status: o.Status}
+}
+
+func ntpPolicyFromResponse(o *FabricObject) *NTPPolicy {
+	p := &NTPPolicy{object: fabricObject(o), enabled: o.AdminSt == "enabled"}
+	for _, c := range o.Children {
+		if c.DatetimeNtpProv == nil {
+			continue
+		}
+		p.servers = append(p.servers, &NTPServer{
+			object:    fabricObject(c.DatetimeNtpProv),
+			preferred: c.DatetimeNtpProv.Preferred == "yes",
+		})
+		for _, cc := range c.DatetimeNtpProv.Children {
+			if cc.RsNtpProvToEpg != nil {
+				p.setManagementEPG(cc.RsNtpProvToEpg.TDN)
+			}
+		}
+	}
+	return p
+}
+
+// GetDateTimeFormat retrieves the date and time format of the fabric.
+func (s *FabricPolicyService) GetDateTimeFormat(ctx context.Context) (*DateTimeFormat, error) {
+	fc, err := s.getFabric(ctx, fmt.Sprintf("api/node/mo/%s.json", dateTimeFormatDN))
+	if err != nil {
+		return nil, fmt.Errorf("get date time format: %v", err)
+	}
+	if len(fc) == 0 || fc[0].DatetimeFormat == nil {
+		return nil, fmt.Errorf("get date time format: %s not found", dateTimeFormatDN)
+	}
+	o := fc[0].DatetimeFormat
+	return &DateTimeFormat{
+		timezone:   o.Tz,
+		display:    o.DisplayFormat,
+		showOffset: o.ShowOffset == "enabled",
+	}, nil
+}
+
+// UpdateDateTimeFormat modifies the date and time format of the fabric.
+func (s *FabricPolicyService) UpdateDateTimeFormat(ctx context.Context, f *DateTimeFormat) (FabricResponse, error) {
+	return s.postFabric(ctx, dateTimeFormatDN, FabricContainer{
+		DatetimeFormat: &FabricObject{
+			FabricAttrs: FabricAttrs{
+				DN:            dateTimeFormatDN,
+				Tz:            f.Timezone(),
+				DisplayFormat: f.Display(),
+				ShowOffset:    adminState(f.ShowOffset()),
+				Status:        modified,
+			},
+		},
+	})
+}
+
+func newDNSProfileContainer(p *DNSProfile) FabricContainer {
+	o := &FabricObject{
+		FabricAttrs: FabricAttrs{
+			DN:     p.DN(),
+			Name:   p.Name(),
+			Descr:  p.Description(),
+			Status: p.Status(),
+		},
+	}
+
+	// children are implicitly removed with their profile
+	if p.Status() == deleted {
+		return FabricContainer{DNSProfile: o}
+	}
+
+	o.Children = append(o.Children, FabricContainer{
+		RsProfileToEpg: newRelation(RelationAttrs{TDN: p.ManagementEPG()}),
+	})
+	for _, server := range p.Servers() {
+		o.Children = append(o.Children, FabricContainer{
+			DNSProv: &FabricObject{
+				FabricAttrs: FabricAttrs{
+					Addr:      server.Name(),
+					Preferred: yesNo(server.Preferred()),
+					Status:    server.Status(),
+				},
+			},
+		})
+	}
+	for _, domain := range p.Domains() {
+		o.Children = append(o.Children, FabricContainer{
+			DNSDomain: &FabricObject{
+				FabricAttrs: FabricAttrs{
+					Name:      domain.Name(),
+					IsDefault: yesNo(domain.Default()),
+					Status:    domain.Status(),
+				},
+			},
+		})
+	}
+
+	return FabricContainer{DNSProfile: o}
+}
+
+// CreateDNSProfile creates a DNS profile.
+func (s *FabricPolicyService) CreateDNSProfile(ctx context.Context, p *DNSProfile) (FabricResponse, error) {
+	p.SetCreated()
+	return s.UpdateDNSProfile(ctx, p)
+}
+
+// DeleteDNSProfile deletes a DNS profile.
+func (s *FabricPolicyService) DeleteDNSProfile(ctx context.Context, p *DNSProfile) (FabricResponse, error) {
+	p.SetDeleted()
+	return s.UpdateDNSProfile(ctx, p)
+}
+
+// UpdateDNSProfile creates, modifies or deletes a DNS profile according to
+// its status, along with each of its servers and domains according to theirs.
+//
+// A profile can have at most one default domain.
+func (s *FabricPolicyService) UpdateDNSProfile(ctx context.Context, p *DNSProfile) (FabricResponse, error) {
+	var defaults int
+	for _, d := range p.Domains() {
+		if d.Default() && d.Status() != deleted {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return FabricResponse{}, fmt.Errorf("update dns profile: %s has %d default domains", p.Name(), defaults)
+	}
+	return s.postFabric(ctx, p.DN(), newDNSProfileContainer(p))
+}
+
+// GetDNSProfile retrieves a DNS profile.
+func (s *FabricPolicyService) GetDNSProfile(ctx context.Context, name string) (*DNSProfile, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", dnsProfileDN(name))
+	fc, err := s.getFabric(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get dns profile: %v", err)
+	}
+	if len(fc) == 0 || fc[0].DNSProfile == nil {
+		return nil, fmt.Errorf("get dns profile: %s not found", name)
+	}
+	return dnsProfileFromResponse(fc[0].DNSProfile), nil
+}
+
+// ListDNSProfiles lists all DNS profiles.
+func (s *FabricPolicyService) ListDNSProfiles(ctx context.Context) ([]*DNSProfile, error) {
+	fc, err := s.listClass(ctx, "dnsProfile")
+	if err != nil {
+		return nil, fmt.Errorf("list dns profiles: %v", err)
+	}
+
+	var profiles []*DNSProfile
+	for _, c := range fc {
+		if c.DNSProfile != nil {
+			profiles = append(profiles, dnsProfileFromResponse(c.DNSProfile))
+		}
+	}
+	return profiles, nil
+}
+
+func dnsProfileFromResponse(o *FabricObject) *DNSProfile {
+	p := &DNSProfile{object: fabricObject(o)}
+	for _, c := range o.Children {
+		switch {
+		case c.RsProfileToEpg != nil:
+			p.setManagementEPG(c.RsProfileToEpg.TDN)
+		case c.DNSProv != nil:
+			server := &DNSServer{
+				object:    fabricObject(c.DNSProv),
+				preferred: c.DNSProv.Preferred == "yes",
+			}
+			server.name = c.DNSProv.Addr
+			p.servers = append(p.servers, server)
+		case c.DNSDomain != nil:
+			p.domains = append(p.domains, &DNSDomain{
+				object:    fabricObject(c.DNSDomain),
+				isDefault: c.DNSDomain.IsDefault == "yes",
+			})
+		}
+	}
+	return p
+}
+
+// AssignDNSProfile labels a VRF of the mgmt tenant, such as "oob" or "inb",
+// with a DNS profile, so the fabric nodes use it in that VRF.
+func (s *FabricPolicyService) AssignDNSProfile(ctx context.Context, profile, vrf string) (FabricResponse, error) {
+	if err := validateName("dns profile name", profile); err != nil {
+		return FabricResponse{}, err
+	}
+	if err := validateName("vrf name", vrf); err != nil {
+		return FabricResponse{}, err
+	}
+	dn := fmt.Sprintf("%s/dnslbl-%s", vrfDN("mgmt", vrf), profile)
+	return s.postFabric(ctx, dn, FabricContainer{
+		DNSLbl: &FabricObject{
+			FabricAttrs: FabricAttrs{DN: dn, Name: profile, Status: createdModified},
+		},
+	})
+}
+
+func newSNMPPolicyContainer(p *SNMPPolicy) FabricContainer {
+	o := &FabricObject{
+		FabricAttrs: FabricAttrs{
+			DN:      p.DN(),
+			Name:    p.Name(),
+			Descr:   p.Description(),
+			AdminSt: adminState(p.Enabled()),
+			Contact: p.Contact(),
+			Loc:     p.Location(),
+			Status:  p.Status(),
+		},
+	}
+
+	// children are implicitly removed with their policy
+	if p.Status() == deleted {
+		return FabricContainer{SnmpPol: o}
+	}
+
+	for _, c := range p.Communities() {
+		o.Children = append(o.Children, FabricContainer{
+			SnmpCommunityP: &FabricObject{
+				FabricAttrs: FabricAttrs{Name: c.Name(), Status: c.Status()},
+			},
+		})
+	}
+	for _, g := range p.ClientGroups() {
+		grp := &FabricObject{
+			FabricAttrs: FabricAttrs{
+				Name:   g.Name(),
+				Descr:  g.Description(),
+				Status: g.Status(),
+			},
+		}
+		// children are implicitly removed with their group
+		if g.Status() != deleted {
+			grp.Children = append(grp.Children, FabricContainer{
+				SnmpRsEpg: newRelation(RelationAttrs{TDN: g.ManagementEPG()}),
+			})
+			for _, c := range g.Clients() {
+				grp.Children = append(grp.Children, FabricContainer{
+					SnmpClientP: &FabricObject{
+						FabricAttrs: FabricAttrs{Addr: c.Name(), Status: c.Status()},
+					},
+				})
+			}
+		}
+		o.Children = append(o.Children, FabricContainer{SnmpClientGrpP: grp})
+	}
+
+	return FabricContainer{SnmpPol: o}
+}
+
+// CreateSNMPPolicy creates an SNMP policy.
+func (s *FabricPolicyService) CreateSNMPPolicy(ctx context.Context, p *SNMPPolicy) (FabricResponse, error) {
+	p.SetCreated()
+	return s.UpdateSNMPPolicy(ctx, p)
+}
+
+// DeleteSNMPPolicy deletes an SNMP policy.
+func (s *FabricPolicyService) DeleteSNMPPolicy(ctx context.Context, p *SNMPPolicy) (FabricResponse, error) {
+	p.SetDeleted()
+	return s.UpdateSNMPPolicy(ctx, p)
+}
+
+// UpdateSNMPPolicy creates, modifies or deletes an SNMP policy according
+// to its status, along with each of its communities, client groups and
+// clients according to theirs.
+func (s *FabricPolicyService) UpdateSNMPPolicy(ctx context.Context, p *SNMPPolicy) (FabricResponse, error) {
+	return s.postFabric(ctx, p.DN(), newSNMPPolicyContainer(p))
+}
+
+// GetSNMPPolicy retrieves an SNMP policy.
+func (s *FabricPolicyService) GetSNMPPolicy(ctx context.Context, name string) (*SNMPPolicy, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", snmpPolicyDN(name))
+	fc, err := s.getFabric(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get snmp policy: %v", err)
+	}
+	if len(fc) == 0 || fc[0].SnmpPol == nil {
+		return nil, fmt.Errorf("get snmp policy: %s not found", name)
+	}
+	return snmpPolicyFromResponse(fc[0].SnmpPol), nil
+}
+
+// ListSNMPPolicies lists all SNMP policies.
+func (s *FabricPolicyService) ListSNMPPolicies(ctx context.Context) ([]*SNMPPolicy, error) {
+	fc, err := s.listClass(ctx, "snmpPol")
+	if err != nil {
+		return nil, fmt.Errorf("list snmp policies: %v", err)
+	}
+
+	var policies []*SNMPPolicy
+	for _, c := range fc {
+		if c.SnmpPol != nil {
+			policies = append(policies, snmpPolicyFromResponse(c.SnmpPol))
+		}
+	}
+	return policies, nil
+}
+
+func snmpPolicyFromResponse(o *FabricObject) *SNMPPolicy {
+	p := &SNMPPolicy{
+		object:   fabricObject(o),
+		enabled:  o.AdminSt == "enabled",
+		contact:  o.Contact,
+		location: o.Loc,
+	}
+	for _, c := range o.Children {
+		switch {
+		case c.SnmpCommunityP != nil:
+			p.communities = append(p.communities, &SNMPCommunity{object: fabricObject(c.SnmpCommunityP)})
+		case c.SnmpClientGrpP != nil:
+			g := &SNMPClientGroup{object: fabricObject(c.SnmpClientGrpP)}
+			for _, cc := range c.SnmpClientGrpP.Children {
+				switch {
+				case cc.SnmpRsEpg != nil:
+					g.setManagementEPG(cc.SnmpRsEpg.TDN)
+				case cc.SnmpClientP != nil:
+					client := &SNMPClient{object: fabricObject(cc.SnmpClientP)}
+					client.name = cc.SnmpClientP.Addr
+					g.clients = append(g.clients, client)
+				}
+			}
+			p.clientGroups = append(p.clientGroups, g)
+		}
+	}
+	return p
+}
+
+// UpdatePodPolicyGroup creates or modifies a pod policy group using the
+// named NTP and SNMP policies, and applies it to every pod of the fabric.
+//
+// An existing group is read and written back with only its NTP and SNMP
+// policies changed, so that its ISIS, COOP, BGP route reflector, MACsec
+// and management access policies are kept as they are.
+func (s *FabricPolicyService) UpdatePodPolicyGroup(ctx context.Context, name, ntp, snmp string) (FabricResponse, error) {
+	if err := validateName("pod policy group name", name); err != nil {
+		return FabricResponse{}, err
+	}
+	if err := validateName("ntp policy name", ntp); err != nil {
+		return FabricResponse{}, err
+	}
+	if err := validateName("snmp policy name", snmp); err != nil {
+		return FabricResponse{}, err
+	}
+
+	dn := fmt.Sprintf("uni/fabric/funcprof/podpgrp-%s", name)
+	fc, err := s.getFabric(ctx, fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=children", dn))
+	if err != nil {
+		return FabricResponse{}, fmt.Errorf("update pod policy group: %v", err)
+	}
+	var children []FabricContainer
+	if len(fc) > 0 && fc[0].PodPGrp != nil {
+		children = podPolicyGroupRelations(fc[0].PodPGrp)
+	}
+	children = append(children,
+		FabricContainer{RsTimePol: newRelation(RelationAttrs{TnDatetimePolName: ntp})},
+		FabricContainer{RsSnmpPol: newRelation(RelationAttrs{TnSnmpPolName: snmp})},
+	)
+
+	fr, err := s.postFabric(ctx, dn, FabricContainer{
+		PodPGrp: &FabricObject{
+			FabricAttrs: FabricAttrs{DN: dn, Name: name, Status: createdModified},
+			Children:    children,
+		},
+	})
+	if err != nil {
+		return fr, err
+	}
+
+	selector := "uni/fabric/podprof-default/pods-default-typ-ALL/rspodPGrp"
+	return s.postFabric(ctx, selector, FabricContainer{
+		RsPodPGrp: newRelation(RelationAttrs{DN: selector, TDN: dn, Status: createdModified}),
+	})
+}
+
+// podPolicyGroupRelations returns the relations of a pod policy group
+// returned by the APIC to its policies other than its NTP and SNMP
+// policies, to be posted back as they are.
+func podPolicyGroupRelations(o *FabricObject) []FabricContainer {
+	var kept []FabricContainer
+	for _, c := range o.Children {
+		r := FabricContainer{
+			RsPodPGrpIsisDomP: keptRelation(c.RsPodPGrpIsisDomP),
+			RsPodPGrpCoopP:    keptRelation(c.RsPodPGrpCoopP),
+			RsPodPGrpBGPRRP:   keptRelation(c.RsPodPGrpBGPRRP),
+			RsCommPol:         keptRelation(c.RsCommPol),
+			RsMacsecPol:       keptRelation(c.RsMacsecPol),
+		}
+		if r != (FabricContainer{}) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// keptRelation returns a named relation returned by the APIC to be posted
+// back, without the attributes the APIC resolves itself, or nil if it is nil.
+func keptRelation(r *Relation) *Relation {
+	if r == nil {
+		return nil
+	}
+	attrs := r.RelationAttrs
+	attrs.DN, attrs.TDN, attrs.Status = "", "", ""
+	return newRelation(attrs)
+}
+
+func newSyslogGroupContainer(g *SyslogGroup) FabricContainer {
+	o := &FabricObject{
+		FabricAttrs: FabricAttrs{
+			DN:     g.DN(),
+			Name:   g.Name(),
+			Descr:  g.Description(),
+			Format: "aci",
+			Status: g.Status(),
+		},
+	}
+
+	// children are implicitly removed with their group
+	if g.Status() == deleted {
+		return FabricContainer{SyslogGroup: o}
+	}
+
+	for _, d := range g.Destinations() {
+		dest := &FabricObject{
+			FabricAttrs: FabricAttrs{
+				Host:               d.Name(),
+				Name:               d.Name(),
+				Descr:              d.Description(),
+				AdminState:         "enabled",
+				Port:               strconv.Itoa(d.Port()),
+				Severity:           d.Severity(),
+				ForwardingFacility: d.Facility(),
+				Status:             d.Status(),
+			},
+		}
+		if d.Status() != deleted {
+			dest.Children = append(dest.Children, FabricContainer{
+				RsARemoteHostToEpg: newRelation(RelationAttrs{TDN: d.ManagementEPG()}),
+			})
+		}
+		o.Children = append(o.Children, FabricContainer{SyslogRemoteDest: dest})
+	}
+
+	return FabricContainer{SyslogGroup: o}
+}
+
+// CreateSyslogGroup creates a syslog group.
+func (s *FabricPolicyService) CreateSyslogGroup(ctx context.Context, g *SyslogGroup) (FabricResponse, error) {
+	g.SetCreated()
+	return s.UpdateSyslogGroup(ctx, g)
+}
+
+// DeleteSyslogGroup deletes a syslog group.
+func (s *FabricPolicyService) DeleteSyslogGroup(ctx context.Context, g *SyslogGroup) (FabricResponse, error) {
+	g.SetDeleted()
+	return s.UpdateSyslogGroup(ctx, g)
+}
+
+// UpdateSyslogGroup creates, modifies or deletes a syslog group according
+// to its status, along with each of its destinations according to theirs.
+func (s *FabricPolicyService) UpdateSyslogGroup(ctx context.Context, g *SyslogGroup) (FabricResponse, error) {
+	return s.postFabric(ctx, g.DN(), newSyslogGroupContainer(g))
+}
+
+// GetSyslogGroup retrieves a syslog group.
+func (s *FabricPolicyService) GetSyslogGroup(ctx context.Context, name string) (*SyslogGroup, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", syslogGroupDN(name))
+	fc, err := s.getFabric(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get syslog group: %v", err)
+	}
+	if len(fc) == 0 || fc[0].SyslogGroup == nil {
+		return nil, fmt.Errorf("get syslog group: %s not found", name)
+	}
+	return syslogGroupFromResponse(fc[0].SyslogGroup), nil
+}
+
+// ListSyslogGroups lists all syslog groups.
+func (s *FabricPolicyService) ListSyslogGroups(ctx context.Context) ([]*SyslogGroup, error) {
+	fc, err := s.listClass(ctx, "syslogGroup")
+	if err != nil {
+		return nil, fmt.Errorf("list syslog groups: %v", err)
+	}
+
+	var groups []*SyslogGroup
+	for _, c := range fc {
+		if c.SyslogGroup != nil {
+			groups = append(groups, syslogGroupFromResponse(c.SyslogGroup))
+		}
+	}
+	return groups, nil
+}
+
+func syslogGroupFromResponse(o *FabricObject) *SyslogGroup {
+	g := &SyslogGroup{object: fabricObject(o)}
+	for _, c := range o.Children {
+		if c.SyslogRemoteDest == nil {
+			continue
+		}
+		port, _ := strconv.Atoi(c.SyslogRemoteDest.Port)
+		d := &SyslogDestination{
+			object:   fabricObject(c.SyslogRemoteDest),
+			port:     port,
+			severity: c.SyslogRemoteDest.Severity,
+			facility: c.SyslogRemoteDest.ForwardingFacility,
+		}
+		d.name = c.SyslogRemoteDest.Host
+		for _, cc := range c.SyslogRemoteDest.Children {
+			if cc.RsARemoteHostToEpg != nil {
+				d.setManagementEPG(cc.RsARemoteHostToEpg.TDN)
+			}
+		}
+		g.destinations = append(g.destinations, d)
+	}
+	return g
+}
+
+// UpdateSyslogSource creates or modifies a syslog source of the common
+// fabric monitoring policy, sending the faults, events and audit logs of
+// at least the given severity to the named syslog group.
+func (s *FabricPolicyService) UpdateSyslogSource(ctx context.Context, name, group, severity string) (FabricResponse, error) {
+	if err := validateName("syslog source name", name); err != nil {
+		return FabricResponse{}, err
+	}
+	if err := validateName("syslog group name", group); err != nil {
+		return FabricResponse{}, err
+	}
+	if err := validateSeverity(severity); err != nil {
+		return FabricResponse{}, err
+	}
+
+	dn := fmt.Sprintf("uni/fabric/moncommon/slsrc-%s", name)
+	return s.postFabric(ctx, dn, FabricContainer{
+		SyslogSrc: &FabricObject{
+			FabricAttrs: FabricAttrs{
+				DN:     dn,
+				Name:   name,
+				Incl:   "audit,events,faults",
+				MinSev: severity,
+				Status: createdModified,
+			},
+			Children: []FabricContainer{
+				{SyslogRsDestGroup: newRelation(RelationAttrs{TDN: syslogGroupDN(group)})},
+			},
+		},
+	})
+}
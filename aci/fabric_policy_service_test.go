@@ -0,0 +1,178 @@
+package aci
+
+import (
+	"context"
+	"testing"
+)
+
+const (
+	podPolicyGroupPath  = "/api/node/mo/uni/fabric/funcprof/podpgrp-pods.json"
+	podPolicySelectPath = "/api/node/mo/uni/fabric/podprof-default/pods-default-typ-ALL/rspodPGrp.json"
+)
+
+func TestFabricPolicyValidation(t *testing.T) {
+	s := &FabricPolicyService{}
+	timezones := []struct {
+		timezone string
+		valid    bool
+	}{
+		{"p0_UTC", true},
+		{"p60_Europe-Paris", true},
+		{"n300_America-New_York", true},
+		{"UTC", false},
+		{"p60_Europe/Paris", false},
+		{"x60_Europe-Paris", false},
+	}
+	for _, tt := range timezones {
+		if _, err := s.NewDateTimeFormat(tt.timezone); (err == nil) != tt.valid {
+			t.Errorf("timezone %s: got error %v, want valid %t", tt.timezone, err, tt.valid)
+		}
+	}
+
+	d, err := s.NewSyslogDestination("192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	facilities := []struct {
+		facility string
+		valid    bool
+	}{
+		{"local0", true},
+		{"local7", true},
+		{"local8", false},
+		{"user", false},
+		{"xlocal0", false},
+	}
+	for _, tt := range facilities {
+		if err := d.SetFacility(tt.facility); (err == nil) != tt.valid {
+			t.Errorf("facility %s: got error %v, want valid %t", tt.facility, err, tt.valid)
+		}
+	}
+	if d.Facility() != "local7" {
+		t.Errorf("got facility %s, want local7", d.Facility())
+	}
+}
+
+func TestNTPPolicy(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	s := c.FabricPolicy
+	p, err := s.NewNTPPolicy("ntp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetInBandEPG("inb"); err != nil {
+		t.Fatal(err)
+	}
+	server, err := s.NewNTPServer("ntp1.example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := s.NewNTPServer("192.0.2.1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.SetDeleted()
+	p.AddServer(server)
+	p.AddServer(old)
+
+	if _, err := s.CreateNTPPolicy(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted("/api/node/mo/uni/fabric/time-ntp.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"datetimePol":{"attributes":{"dn":"uni/fabric/time-ntp","name":"ntp","adminSt":"enabled","status":"created,modified"},"children":[
+		{"datetimeNtpProv":{"attributes":{"name":"ntp1.example.com","preferred":"yes"},"children":[
+			{"datetimeRsNtpProvToEpg":{"attributes":{"tDn":"uni/tn-mgmt/mgmtp-default/inb-inb"}}}
+		]}},
+		{"datetimeNtpProv":{"attributes":{"name":"192.0.2.1","preferred":"no","status":"deleted"}}}
+	]}}`)
+
+	f.setFixture("/api/node/mo/uni/fabric/time-ntp.json", `{"imdata":[`+posts[0]+`]}`)
+	got, err := s.GetNTPPolicy(context.Background(), "ntp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name() != "ntp" || !got.Enabled() || len(got.Servers()) != 2 || !got.Servers()[0].Preferred() {
+		t.Errorf("got %s enabled %t servers %v", got, got.Enabled(), got.Servers())
+	}
+	if want := "uni/tn-mgmt/mgmtp-default/inb-inb"; got.ManagementEPG() != want {
+		t.Errorf("got management epg %s, want %s", got.ManagementEPG(), want)
+	}
+}
+
+func TestUpdatePodPolicyGroup(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+
+	// a new group only has the given policies
+	if _, err := c.FabricPolicy.UpdatePodPolicyGroup(context.Background(), "pods", "ntp", "snmp"); err != nil {
+		t.Fatal(err)
+	}
+	jsonEqual(t, f.posted(podPolicyGroupPath)[0], `{"fabricPodPGrp":{"attributes":{"dn":"uni/fabric/funcprof/podpgrp-pods","name":"pods","status":"created,modified"},"children":[
+		{"fabricRsTimePol":{"attributes":{"tnDatetimePolName":"ntp"}}},
+		{"fabricRsSnmpPol":{"attributes":{"tnSnmpPolName":"snmp"}}}
+	]}}`)
+	jsonEqual(t, f.posted(podPolicySelectPath)[0], `{"fabricRsPodPGrp":{"attributes":{"dn":"uni/fabric/podprof-default/pods-default-typ-ALL/rspodPGrp","tDn":"uni/fabric/funcprof/podpgrp-pods","status":"created,modified"}}}`)
+
+	// an existing group keeps its other policies
+	f.setFixture(podPolicyGroupPath, `{"imdata":[{"fabricPodPGrp":{"attributes":{"dn":"uni/fabric/funcprof/podpgrp-pods","name":"pods"},"children":[
+		{"fabricRsTimePol":{"attributes":{"tnDatetimePolName":"old-ntp","tDn":"uni/fabric/time-old-ntp"}}},
+		{"fabricRsSnmpPol":{"attributes":{"tnSnmpPolName":"old-snmp","tDn":"uni/fabric/snmppol-old-snmp"}}},
+		{"fabricRsPodPGrpIsisDomP":{"attributes":{"tnIsisDomPolName":"isis","tDn":"uni/fabric/isisDomP-isis"}}},
+		{"fabricRsPodPGrpCoopP":{"attributes":{"tnCoopPolName":"coop","tDn":"uni/fabric/pol-coop"}}},
+		{"fabricRsPodPGrpBGPRRP":{"attributes":{"tnBgpInstPolName":"default","tDn":"uni/fabric/bgpInstP-default"}}},
+		{"fabricRsCommPol":{"attributes":{"tnCommPolName":"mgmt","tDn":"uni/fabric/comm-mgmt"}}},
+		{"fabricRsMacsecPol":{"attributes":{"tnMacsecFabIfPolName":"","tDn":"uni/fabric/macsecfabifpol-default"}}}
+	]}}]}`)
+	if _, err := c.FabricPolicy.UpdatePodPolicyGroup(context.Background(), "pods", "ntp", "snmp"); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted(podPolicyGroupPath)
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	jsonEqual(t, posts[1], `{"fabricPodPGrp":{"attributes":{"dn":"uni/fabric/funcprof/podpgrp-pods","name":"pods","status":"created,modified"},"children":[
+		{"fabricRsPodPGrpIsisDomP":{"attributes":{"tnIsisDomPolName":"isis"}}},
+		{"fabricRsPodPGrpCoopP":{"attributes":{"tnCoopPolName":"coop"}}},
+		{"fabricRsPodPGrpBGPRRP":{"attributes":{"tnBgpInstPolName":"default"}}},
+		{"fabricRsCommPol":{"attributes":{"tnCommPolName":"mgmt"}}},
+		{"fabricRsMacsecPol":{"attributes":{}}},
+		{"fabricRsTimePol":{"attributes":{"tnDatetimePolName":"ntp"}}},
+		{"fabricRsSnmpPol":{"attributes":{"tnSnmpPolName":"snmp"}}}
+	]}}`)
+
+	if _, err := c.FabricPolicy.UpdatePodPolicyGroup(context.Background(), "pods", "ntp", ""); err == nil {
+		t.Error("expected an error for no snmp policy")
+	}
+}
+
+func TestSyslogGroup(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	s := c.FabricPolicy
+	g, err := s.NewSyslogGroup("syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.NewSyslogDestination("192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetFacility("local3"); err != nil {
+		t.Fatal(err)
+	}
+	g.AddDestination(d)
+
+	if _, err := s.CreateSyslogGroup(context.Background(), g); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted("/api/node/mo/uni/fabric/slgroup-syslog.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"syslogGroup":{"attributes":{"dn":"uni/fabric/slgroup-syslog","name":"syslog","format":"aci","status":"created,modified"},"children":[
+		{"syslogRemoteDest":{"attributes":{"host":"192.0.2.10","name":"192.0.2.10","adminState":"enabled","port":"514","severity":"warnings","forwardingFacility":"local3"},"children":[
+			{"fileRsARemoteHostToEpg":{"attributes":{"tDn":"uni/tn-mgmt/mgmtp-default/oob-default"}}}
+		]}}
+	]}}`)
+}
@@ -2,8 +2,10 @@ package aci
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // FabricInstanceContainer ...
@@ -205,13 +207,59 @@ func newGeoRackContainer(site, building, floor, room, row string, rack *Rack) Ge
 	geoRack.RN = fmt.Sprintf("rack-%s", rack.Name())
 	geoRack.Status = rack.Status()
 
+	for _, node := range rack.Nodes() {
+		var geoNode GeoRsNodeLocationContainer
+		geoNode.TDN = nodeTDN(node)
+		geoNode.Status = createdModified
+		geoRack.GeoNodes = append(geoRack.GeoNodes, geoNode)
+	}
+	for _, node := range rack.removed {
+		var geoNode GeoRsNodeLocationContainer
+		geoNode.TDN = nodeTDN(node)
+		geoNode.Status = deleted
+		geoRack.GeoNodes = append(geoRack.GeoNodes, geoNode)
+	}
+
 	return geoRack
 }
 
 // GeoRack ...
 type GeoRack struct {
 	GeoAttrs `json:"attributes,omitempty"`
-	GeoNodes []interface{} `json:"children,omitempty"`
+	GeoNodes []GeoRsNodeLocationContainer `json:"children,omitempty"`
+}
+
+// GeoRsNodeLocationContainer is a container for the relation
+// of a rack to a fabric node installed in it
+type GeoRsNodeLocationContainer struct {
+	GeoRsNodeLocation `json:"geoRsNodeLocation,omitempty"`
+}
+
+// GeoRsNodeLocation relates a rack to a fabric node installed in it
+type GeoRsNodeLocation struct {
+	GeoRsNodeLocationAttrs `json:"attributes,omitempty"`
+}
+
+// GeoRsNodeLocationAttrs contains the attributes of a rack to node relation
+type GeoRsNodeLocationAttrs struct {
+	DN     string `json:"dn,omitempty"`
+	TDN    string `json:"tDn,omitempty"` // "topology/pod-<pod>/node-<nodeID>"
+	Status string `json:"status,omitempty"`
+}
+
+// nodeTDN returns the distinguished name of the fabric node in the topology.
+func nodeTDN(node *Node) string {
+	return fmt.Sprintf("topology/pod-%s/node-%s", node.Pod(), node.ID())
+}
+
+// nodeFromTDN returns a node with the pod and id of the
+// given topology distinguished name.
+func nodeFromTDN(tdn string) *Node {
+	node := &Node{pod: podFromDN(tdn)}
+	if i := strings.LastIndex(tdn, "/node-"); i >= 0 {
+		node.id = tdn[i+len("/node-"):]
+	}
+	return node
 }
 
 // GeoAttrs ...
@@ -367,46 +415,408 @@ func (s *GeolocationService) ListSites(ctx context.Context) ([]*Site, error) {
 	var sites []*Site
 
 	for _, siteLocation := range gs.Imdata {
-		site, err := s.NewSite(siteLocation.Name, siteLocation.Descr)
+		site, err := s.siteFromGeo(siteLocation.GeoSite)
 		if err != nil {
 			return sites, err
 		}
-		for _, buildingLocation := range siteLocation.GeoBuildings {
-			building, err := s.NewBuilding(buildingLocation.Name, buildingLocation.Descr)
-			if err != nil {
-				return sites, err
-			}
-			for _, floorLocation := range buildingLocation.GeoFloors {
-				floor, err := s.NewFloor(floorLocation.Name, floorLocation.Descr)
-				if err != nil {
-					return sites, err
-				}
-				for _, roomLocation := range floorLocation.GeoRooms {
-					room, err := s.NewRoom(roomLocation.Name, roomLocation.Descr)
-					if err != nil {
-						return sites, err
-					}
-					for _, rowLocation := range roomLocation.GeoRows {
-						row, err := s.NewRow(rowLocation.Name, rowLocation.Descr)
-						if err != nil {
-							return sites, err
-						}
-						for _, rackLocation := range rowLocation.GeoRacks {
-							rack, err := s.NewRack(rackLocation.Name, rackLocation.Descr)
-							if err != nil {
-								return sites, err
-							}
-							row.AddRack(rack)
-						}
-						room.AddRow(row)
-					}
-					floor.AddRoom(room)
-				}
-				building.AddFloor(floor)
-			}
-			site.AddBuilding(building)
-		}
 		sites = append(sites, site)
 	}
 	return sites, nil
 }
+
+func (s *GeolocationService) siteFromGeo(g GeoSite) (*Site, error) {
+	site, err := s.NewSite(g.Name, g.Descr)
+	if err != nil {
+		return site, err
+	}
+	for _, c := range g.GeoBuildings {
+		building, err := s.buildingFromGeo(c.GeoBuilding)
+		if err != nil {
+			return site, err
+		}
+		site.AddBuilding(building)
+	}
+	return site, nil
+}
+
+func (s *GeolocationService) buildingFromGeo(g GeoBuilding) (*Building, error) {
+	building, err := s.NewBuilding(g.Name, g.Descr)
+	if err != nil {
+		return building, err
+	}
+	for _, c := range g.GeoFloors {
+		floor, err := s.floorFromGeo(c.GeoFloor)
+		if err != nil {
+			return building, err
+		}
+		building.AddFloor(floor)
+	}
+	return building, nil
+}
+
+func (s *GeolocationService) floorFromGeo(g GeoFloor) (*Floor, error) {
+	floor, err := s.NewFloor(g.Name, g.Descr)
+	if err != nil {
+		return floor, err
+	}
+	for _, c := range g.GeoRooms {
+		room, err := s.roomFromGeo(c.GeoRoom)
+		if err != nil {
+			return floor, err
+		}
+		floor.AddRoom(room)
+	}
+	return floor, nil
+}
+
+func (s *GeolocationService) roomFromGeo(g GeoRoom) (*Room, error) {
+	room, err := s.NewRoom(g.Name, g.Descr)
+	if err != nil {
+		return room, err
+	}
+	for _, c := range g.GeoRows {
+		row, err := s.rowFromGeo(c.GeoRow)
+		if err != nil {
+			return room, err
+		}
+		room.AddRow(row)
+	}
+	return room, nil
+}
+
+func (s *GeolocationService) rowFromGeo(g GeoRow) (*Row, error) {
+	row, err := s.NewRow(g.Name, g.Descr)
+	if err != nil {
+		return row, err
+	}
+	for _, c := range g.GeoRacks {
+		rack, err := s.rackFromGeo(c.GeoRack)
+		if err != nil {
+			return row, err
+		}
+		row.AddRack(rack)
+	}
+	return row, nil
+}
+
+func (s *GeolocationService) rackFromGeo(g GeoRack) (*Rack, error) {
+	rack, err := s.NewRack(g.Name, g.Descr)
+	if err != nil {
+		return rack, err
+	}
+	for _, c := range g.GeoNodes {
+		if c.TDN == "" {
+			continue
+		}
+		rack.AddNode(nodeFromTDN(c.TDN))
+	}
+	return rack, nil
+}
+
+// geoDN returns the distinguished name of a geolocation object
+// from the names of it and its parents, starting with the site.
+func geoDN(names ...string) string {
+	prefixes := []string{"site", "building", "floor", "room", "row", "rack"}
+	dn := "uni/fabric"
+	for i, name := range names {
+		dn += fmt.Sprintf("/%s-%s", prefixes[i], name)
+	}
+	return dn
+}
+
+// getLocation retrieves the full subtree of the geolocation object
+// with the given distinguished name into v, returning an error
+// if the object does not exist.
+func (s *GeolocationService) getLocation(ctx context.Context, dn string, v interface{}) error {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", dn)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	// structure of expected response
+	var raw struct {
+		Imdata []json.RawMessage `json:"imdata"`
+	}
+	_, err = s.client.Do(ctx, req, &raw)
+	if err != nil {
+		return err
+	}
+	if len(raw.Imdata) == 0 {
+		return fmt.Errorf("%s not found", dn)
+	}
+	return json.Unmarshal(raw.Imdata[0], v)
+}
+
+// postLocation posts a geolocation object subtree to the given distinguished name.
+func (s *GeolocationService) postLocation(ctx context.Context, dn string, payload interface{}) (GeolocationResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", dn)
+
+	var gr GeolocationResponse
+
+	req, err := s.client.NewRequest(http.MethodPost, path, payload)
+	if err != nil {
+		return gr, err
+	}
+
+	_, err = s.client.Do(ctx, req, &gr)
+	return gr, err
+}
+
+// deleteLocation deletes the geolocation object of the given class
+// and distinguished name, along with everything beneath it.
+func (s *GeolocationService) deleteLocation(ctx context.Context, class, dn string) (GeolocationResponse, error) {
+	payload := map[string]GeoSite{
+		class: {GeoAttrs: GeoAttrs{DN: dn, Status: deleted}},
+	}
+	return s.postLocation(ctx, dn, payload)
+}
+
+// GetSite retrieves a site and everything within it.
+func (s *GeolocationService) GetSite(ctx context.Context, site string) (*Site, error) {
+	var c GeoSiteContainer
+	if err := s.getLocation(ctx, geoDN(site), &c); err != nil {
+		return nil, fmt.Errorf("get site: %v", err)
+	}
+	return s.siteFromGeo(c.GeoSite)
+}
+
+// DeleteSite deletes a site and everything within it.
+func (s *GeolocationService) DeleteSite(ctx context.Context, site string) (GeolocationResponse, error) {
+	return s.deleteLocation(ctx, "geoSite", geoDN(site))
+}
+
+// RenameSite renames a site, by creating the site under its new name
+// with everything within it and then deleting the original.
+func (s *GeolocationService) RenameSite(ctx context.Context, site, name string) (GeolocationResponse, error) {
+	sl, err := s.GetSite(ctx, site)
+	if err != nil {
+		return GeolocationResponse{}, err
+	}
+	if err := sl.SetName(name); err != nil {
+		return GeolocationResponse{}, err
+	}
+	if _, err := s.postLocation(ctx, geoDN(name), newGeoSiteContainer(sl)); err != nil {
+		return GeolocationResponse{}, fmt.Errorf("rename site: %v", err)
+	}
+	return s.DeleteSite(ctx, site)
+}
+
+// GetBuilding retrieves a building and everything within it.
+func (s *GeolocationService) GetBuilding(ctx context.Context, site, building string) (*Building, error) {
+	var c GeoBuildingContainer
+	if err := s.getLocation(ctx, geoDN(site, building), &c); err != nil {
+		return nil, fmt.Errorf("get building: %v", err)
+	}
+	return s.buildingFromGeo(c.GeoBuilding)
+}
+
+// DeleteBuilding deletes a building and everything within it.
+func (s *GeolocationService) DeleteBuilding(ctx context.Context, site, building string) (GeolocationResponse, error) {
+	return s.deleteLocation(ctx, "geoBuilding", geoDN(site, building))
+}
+
+// RenameBuilding renames a building, by creating the building under its
+// new name with everything within it and then deleting the original.
+func (s *GeolocationService) RenameBuilding(ctx context.Context, site, building, name string) (GeolocationResponse, error) {
+	l, err := s.GetBuilding(ctx, site, building)
+	if err != nil {
+		return GeolocationResponse{}, err
+	}
+	if err := l.SetName(name); err != nil {
+		return GeolocationResponse{}, err
+	}
+	if _, err := s.postLocation(ctx, geoDN(site, name), newGeoBuildingContainer(site, l)); err != nil {
+		return GeolocationResponse{}, fmt.Errorf("rename building: %v", err)
+	}
+	return s.DeleteBuilding(ctx, site, building)
+}
+
+// GetFloor retrieves a floor and everything within it.
+func (s *GeolocationService) GetFloor(ctx context.Context, site, building, floor string) (*Floor, error) {
+	var c GeoFloorContainer
+	if err := s.getLocation(ctx, geoDN(site, building, floor), &c); err != nil {
+		return nil, fmt.Errorf("get floor: %v", err)
+	}
+	return s.floorFromGeo(c.GeoFloor)
+}
+
+// DeleteFloor deletes a floor and everything within it.
+func (s *GeolocationService) DeleteFloor(ctx context.Context, site, building, floor string) (GeolocationResponse, error) {
+	return s.deleteLocation(ctx, "geoFloor", geoDN(site, building, floor))
+}
+
+// RenameFloor renames a floor, by creating the floor under its
+// new name with everything within it and then deleting the original.
+func (s *GeolocationService) RenameFloor(ctx context.Context, site, building, floor, name string) (GeolocationResponse, error) {
+	l, err := s.GetFloor(ctx, site, building, floor)
+	if err != nil {
+		return GeolocationResponse{}, err
+	}
+	if err := l.SetName(name); err != nil {
+		return GeolocationResponse{}, err
+	}
+	if _, err := s.postLocation(ctx, geoDN(site, building, name), newGeoFloorContainer(site, building, l)); err != nil {
+		return GeolocationResponse{}, fmt.Errorf("rename floor: %v", err)
+	}
+	return s.DeleteFloor(ctx, site, building, floor)
+}
+
+// GetRoom retrieves a room and everything within it.
+func (s *GeolocationService) GetRoom(ctx context.Context, site, building, floor, room string) (*Room, error) {
+	var c GeoRoomContainer
+	if err := s.getLocation(ctx, geoDN(site, building, floor, room), &c); err != nil {
+		return nil, fmt.Errorf("get room: %v", err)
+	}
+	return s.roomFromGeo(c.GeoRoom)
+}
+
+// DeleteRoom deletes a room and everything within it.
+func (s *GeolocationService) DeleteRoom(ctx context.Context, site, building, floor, room string) (GeolocationResponse, error) {
+	return s.deleteLocation(ctx, "geoRoom", geoDN(site, building, floor, room))
+}
+
+// RenameRoom renames a room, by creating the room under its
+// new name with everything within it and then deleting the original.
+func (s *GeolocationService) RenameRoom(ctx context.Context, site, building, floor, room, name string) (GeolocationResponse, error) {
+	l, err := s.GetRoom(ctx, site, building, floor, room)
+	if err != nil {
+		return GeolocationResponse{}, err
+	}
+	if err := l.SetName(name); err != nil {
+		return GeolocationResponse{}, err
+	}
+	if _, err := s.postLocation(ctx, geoDN(site, building, floor, name), newGeoRoomContainer(site, building, floor, l)); err != nil {
+		return GeolocationResponse{}, fmt.Errorf("rename room: %v", err)
+	}
+	return s.DeleteRoom(ctx, site, building, floor, room)
+}
+
+// GetRow retrieves a row and everything within it.
+func (s *GeolocationService) GetRow(ctx context.Context, site, building, floor, room, row string) (*Row, error) {
+	var c GeoRowContainer
+	if err := s.getLocation(ctx, geoDN(site, building, floor, room, row), &c); err != nil {
+		return nil, fmt.Errorf("get row: %v", err)
+	}
+	return s.rowFromGeo(c.GeoRow)
+}
+
+// DeleteRow deletes a row and everything within it.
+func (s *GeolocationService) DeleteRow(ctx context.Context, site, building, floor, room, row string) (GeolocationResponse, error) {
+	return s.deleteLocation(ctx, "geoRow", geoDN(site, building, floor, room, row))
+}
+
+// RenameRow renames a row, by creating the row under its
+// new name with everything within it and then deleting the original.
+func (s *GeolocationService) RenameRow(ctx context.Context, site, building, floor, room, row, name string) (GeolocationResponse, error) {
+	l, err := s.GetRow(ctx, site, building, floor, room, row)
+	if err != nil {
+		return GeolocationResponse{}, err
+	}
+	if err := l.SetName(name); err != nil {
+		return GeolocationResponse{}, err
+	}
+	if _, err := s.postLocation(ctx, geoDN(site, building, floor, room, name), newGeoRowContainer(site, building, floor, room, l)); err != nil {
+		return GeolocationResponse{}, fmt.Errorf("rename row: %v", err)
+	}
+	return s.DeleteRow(ctx, site, building, floor, room, row)
+}
+
+// GetRack retrieves a rack.
+func (s *GeolocationService) GetRack(ctx context.Context, site, building, floor, room, row, rack string) (*Rack, error) {
+	var c GeoRackContainer
+	if err := s.getLocation(ctx, geoDN(site, building, floor, room, row, rack), &c); err != nil {
+		return nil, fmt.Errorf("get rack: %v", err)
+	}
+	return s.rackFromGeo(c.GeoRack)
+}
+
+// DeleteRack deletes a rack.
+func (s *GeolocationService) DeleteRack(ctx context.Context, site, building, floor, room, row, rack string) (GeolocationResponse, error) {
+	return s.deleteLocation(ctx, "geoRack", geoDN(site, building, floor, room, row, rack))
+}
+
+// RenameRack renames a rack, by creating the rack under its
+// new name and then deleting the original.
+func (s *GeolocationService) RenameRack(ctx context.Context, site, building, floor, room, row, rack, name string) (GeolocationResponse, error) {
+	l, err := s.GetRack(ctx, site, building, floor, room, row, rack)
+	if err != nil {
+		return GeolocationResponse{}, err
+	}
+	if err := l.SetName(name); err != nil {
+		return GeolocationResponse{}, err
+	}
+	if _, err := s.postLocation(ctx, geoDN(site, building, floor, room, row, name), newGeoRackContainer(site, building, floor, room, row, l)); err != nil {
+		return GeolocationResponse{}, fmt.Errorf("rename rack: %v", err)
+	}
+	return s.DeleteRack(ctx, site, building, floor, room, row, rack)
+}
+
+// LocateNode returns the location of the rack a fabric node is installed in,
+// and whether the node has been located in a rack at all.
+func (s *GeolocationService) LocateNode(ctx context.Context, id string) (NodeLocation, bool, error) {
+	locations, err := s.NodeLocations(ctx)
+	if err != nil {
+		return NodeLocation{}, false, fmt.Errorf("locate node %s: %v", id, err)
+	}
+	loc, ok := locations[id]
+	return loc, ok, nil
+}
+
+// NodeLocations returns the location of every fabric node that has been
+// installed in a rack, keyed by node id.
+func (s *GeolocationService) NodeLocations(ctx context.Context) (map[string]NodeLocation, error) {
+	path := "api/node/class/geoRsNodeLocation.json"
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("node locations: %v", err)
+	}
+
+	// structure of expected response
+	var gr struct {
+		Imdata []GeoRsNodeLocationContainer `json:"imdata"`
+	}
+
+	_, err = s.client.Do(ctx, req, &gr)
+	if err != nil {
+		return nil, fmt.Errorf("node locations: %v", err)
+	}
+
+	locations := make(map[string]NodeLocation)
+	for _, rel := range gr.Imdata {
+		node := nodeFromTDN(rel.TDN)
+		locations[node.ID()] = locationFromDN(rel.DN)
+	}
+	return locations, nil
+}
+
+// locationFromDN returns the location described by the distinguished name
+// of a geolocation object, such as
+// "uni/fabric/site-a/building-b/floor-c/room-d/row-e/rack-f".
+func locationFromDN(dn string) NodeLocation {
+	var loc NodeLocation
+	for _, rn := range strings.Split(dn, "/") {
+		i := strings.Index(rn, "-")
+		if i < 0 {
+			continue
+		}
+		name := rn[i+1:]
+		switch rn[:i] {
+		case "site":
+			loc.Site = name
+		case "building":
+			loc.Building = name
+		case "floor":
+			loc.Floor = name
+		case "room":
+			loc.Room = name
+		case "row":
+			loc.Row = name
+		case "rack":
+			loc.Rack = name
+		}
+	}
+	return loc
+}
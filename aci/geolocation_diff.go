@@ -0,0 +1,230 @@
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GeoDiff is the difference between a site on the APIC and its desired state,
+// as the distinguished names of the objects to be created, modified and deleted.
+type GeoDiff struct {
+	Created  []string
+	Modified []string
+	Deleted  []string
+
+	// payload is the minimal subtree that applies the changes
+	payload *geoObject
+}
+
+// Empty reports whether there are no differences.
+func (d *GeoDiff) Empty() bool {
+	return len(d.Created) == 0 && len(d.Modified) == 0 && len(d.Deleted) == 0
+}
+
+// geoObject is an object of any level of the geolocation hierarchy,
+// used to compare sites regardless of level.
+type geoObject struct {
+	class    string
+	dn       string
+	name     string
+	descr    string
+	tdn      string // geoRsNodeLocation only
+	status   string
+	children []*geoObject
+}
+
+// MarshalJSON encodes the object as the APIC expects it,
+// keyed by its class.
+func (o *geoObject) MarshalJSON() ([]byte, error) {
+	type attributes struct {
+		DN     string  `json:"dn,omitempty"`
+		Name   string  `json:"name,omitempty"`
+		Descr  *string `json:"descr,omitempty"`
+		TDN    string  `json:"tDn,omitempty"`
+		Status string  `json:"status,omitempty"`
+	}
+	type object struct {
+		Attributes attributes   `json:"attributes"`
+		Children   []*geoObject `json:"children,omitempty"`
+	}
+	obj := object{
+		Attributes: attributes{
+			DN:     o.dn,
+			Name:   o.name,
+			TDN:    o.tdn,
+			Status: o.status,
+		},
+		Children: o.children,
+	}
+	// descriptions are sent, even when empty, only with the locations
+	// being created or modified, so that a description can be cleared;
+	// node relations have no description
+	if o.tdn == "" && (o.status == createdModified || o.status == modified) {
+		obj.Attributes.Descr = &o.descr
+	}
+	return json.Marshal(map[string]object{o.class: obj})
+}
+
+// key identifies an object amongst its siblings.
+func (o *geoObject) key() string {
+	return o.class + " " + o.name + o.tdn
+}
+
+// created returns a copy of the object and everything beneath it,
+// all marked for creation.
+func (o *geoObject) created() *geoObject {
+	c := *o
+	c.status = createdModified
+	c.children = nil
+	for _, child := range o.children {
+		c.children = append(c.children, child.created())
+	}
+	return &c
+}
+
+func newGeoObject(class, parentDN, prefix string, l *location) *geoObject {
+	return &geoObject{
+		class: class,
+		dn:    fmt.Sprintf("%s/%s-%s", parentDN, prefix, l.Name()),
+		name:  l.Name(),
+		descr: l.Description(),
+	}
+}
+
+func siteObject(site *Site) *geoObject {
+	o := newGeoObject("geoSite", "uni/fabric", "site", &site.location)
+	for _, building := range site.Buildings() {
+		o.children = append(o.children, buildingObject(o.dn, building))
+	}
+	return o
+}
+
+func buildingObject(parentDN string, building *Building) *geoObject {
+	o := newGeoObject("geoBuilding", parentDN, "building", &building.location)
+	for _, floor := range building.Floors() {
+		o.children = append(o.children, floorObject(o.dn, floor))
+	}
+	return o
+}
+
+func floorObject(parentDN string, floor *Floor) *geoObject {
+	o := newGeoObject("geoFloor", parentDN, "floor", &floor.location)
+	for _, room := range floor.Rooms() {
+		o.children = append(o.children, roomObject(o.dn, room))
+	}
+	return o
+}
+
+func roomObject(parentDN string, room *Room) *geoObject {
+	o := newGeoObject("geoRoom", parentDN, "room", &room.location)
+	for _, row := range room.Rows() {
+		o.children = append(o.children, rowObject(o.dn, row))
+	}
+	return o
+}
+
+func rowObject(parentDN string, row *Row) *geoObject {
+	o := newGeoObject("geoRow", parentDN, "row", &row.location)
+	for _, rack := range row.Racks() {
+		o.children = append(o.children, rackObject(o.dn, rack))
+	}
+	return o
+}
+
+func rackObject(parentDN string, rack *Rack) *geoObject {
+	o := newGeoObject("geoRack", parentDN, "rack", &rack.location)
+	for _, node := range rack.Nodes() {
+		tdn := nodeTDN(node)
+		o.children = append(o.children, &geoObject{
+			class: "geoRsNodeLocation",
+			dn:    fmt.Sprintf("%s/rsnodeLocation-[%s]", o.dn, tdn),
+			tdn:   tdn,
+		})
+	}
+	return o
+}
+
+// diff returns the minimal subtree that changes live into desired,
+// or nil if they are the same, recording each change in d.
+func (d *GeoDiff) diff(live, desired *geoObject) *geoObject {
+	switch {
+	case live == nil && desired == nil:
+		return nil
+	case live == nil:
+		d.Created = append(d.Created, desired.dn)
+		return desired.created()
+	case desired == nil:
+		d.Deleted = append(d.Deleted, live.dn)
+		return &geoObject{class: live.class, dn: live.dn, status: deleted}
+	}
+
+	o := &geoObject{class: desired.class, dn: desired.dn, tdn: desired.tdn, descr: desired.descr}
+	if live.descr != desired.descr {
+		d.Modified = append(d.Modified, desired.dn)
+		o.status = modified
+	}
+
+	liveChildren := make(map[string]*geoObject)
+	for _, c := range live.children {
+		liveChildren[c.key()] = c
+	}
+	for _, c := range desired.children {
+		if cd := d.diff(liveChildren[c.key()], c); cd != nil {
+			o.children = append(o.children, cd)
+		}
+		delete(liveChildren, c.key())
+	}
+	// deletions are made in the order of the live tree
+	for _, c := range live.children {
+		if _, ok := liveChildren[c.key()]; ok {
+			o.children = append(o.children, d.diff(c, nil))
+		}
+	}
+
+	if o.status == "" && len(o.children) == 0 {
+		return nil
+	}
+	return o
+}
+
+// Diff compares the desired state of a site against the site on the APIC,
+// as returned by ListSites, and returns the minimal set of geolocation
+// objects to be created, modified and deleted.
+func (s *GeolocationService) Diff(ctx context.Context, desired *Site) (*GeoDiff, error) {
+	sites, err := s.ListSites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("diff: %v", err)
+	}
+
+	var live *geoObject
+	for _, site := range sites {
+		if site.Name() == desired.Name() {
+			live = siteObject(site)
+			break
+		}
+	}
+
+	d := &GeoDiff{}
+	d.payload = d.diff(live, siteObject(desired))
+	return d, nil
+}
+
+// Sync brings the site on the APIC into line with its desired state,
+// posting only the geolocation objects that differ. The differences
+// applied are returned.
+func (s *GeolocationService) Sync(ctx context.Context, desired *Site) (*GeoDiff, GeolocationResponse, error) {
+	d, err := s.Diff(ctx, desired)
+	if err != nil {
+		return nil, GeolocationResponse{}, err
+	}
+	if d.Empty() {
+		return d, GeolocationResponse{}, nil
+	}
+
+	gr, err := s.postLocation(ctx, d.payload.dn, d.payload)
+	if err != nil {
+		return d, gr, fmt.Errorf("sync: %v", err)
+	}
+	return d, gr, nil
+}
@@ -0,0 +1,174 @@
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// liveSites is the site s1 on the APIC, with building b1 holding leaf 101
+// in rack k1, and building b2.
+const liveSites = `{"imdata":[{"geoSite":{"attributes":{"name":"s1","descr":"site"},"children":[
+	{"geoBuilding":{"attributes":{"name":"b1","descr":"old"},"children":[
+		{"geoFloor":{"attributes":{"name":"f1"},"children":[
+			{"geoRoom":{"attributes":{"name":"r1"},"children":[
+				{"geoRow":{"attributes":{"name":"w1"},"children":[
+					{"geoRack":{"attributes":{"name":"k1"},"children":[
+						{"geoRsNodeLocation":{"attributes":{"tDn":"topology/pod-1/node-101"}}}
+					]}}
+				]}}
+			]}}
+		]}}
+	]}},
+	{"geoBuilding":{"attributes":{"name":"b2"}}}
+]}}]}`
+
+// desiredSite returns the site s1 with a rack k1 in building b1,
+// described as given, holding the given nodes.
+func desiredSite(t *testing.T, description string, nodes ...*Node) *Site {
+	t.Helper()
+	site, _ := geo.NewSite("s1", "site")
+	building, _ := geo.NewBuilding("b1", description)
+	floor, _ := geo.NewFloor("f1", "")
+	room, _ := geo.NewRoom("r1", "")
+	row, _ := geo.NewRow("w1", "")
+	rack, _ := geo.NewRack("k1", "")
+	for _, node := range nodes {
+		rack.AddNode(node)
+	}
+	row.AddRack(rack)
+	room.AddRow(row)
+	floor.AddRoom(room)
+	building.AddFloor(floor)
+	site.AddBuilding(building)
+	return site
+}
+
+func TestDiff(t *testing.T) {
+	f, c := newFakeAPIC(t, map[string]string{"/api/node/class/geoSite.json": liveSites})
+
+	site := desiredSite(t, "new", newTestNode(t, "leaf-101", "101", "1", "FDO101", "leaf"), newTestNode(t, "leaf-102", "102", "1", "FDO102", "leaf"))
+	b3, _ := c.Geolocation.NewBuilding("b3", "")
+	f3, _ := c.Geolocation.NewFloor("f3", "third")
+	b3.AddFloor(f3)
+	site.AddBuilding(b3)
+
+	d, _, err := c.Geolocation.Sync(context.Background(), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rack := "uni/fabric/site-s1/building-b1/floor-f1/room-r1/row-w1/rack-k1"
+	want := &GeoDiff{
+		Created:  []string{rack + "/rsnodeLocation-[topology/pod-1/node-102]", "uni/fabric/site-s1/building-b3"},
+		Modified: []string{"uni/fabric/site-s1/building-b1"},
+		Deleted:  []string{"uni/fabric/site-s1/building-b2"},
+	}
+	if !reflect.DeepEqual(d.Created, want.Created) ||
+		!reflect.DeepEqual(d.Modified, want.Modified) ||
+		!reflect.DeepEqual(d.Deleted, want.Deleted) {
+		t.Errorf("got diff %+v, want %+v", *d, *want)
+	}
+
+	posts := f.posted("/api/node/mo/uni/fabric/site-s1.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"geoSite":{"attributes":{"dn":"uni/fabric/site-s1"},"children":[
+		{"geoBuilding":{"attributes":{"dn":"uni/fabric/site-s1/building-b1","descr":"new","status":"modified"},"children":[
+			{"geoFloor":{"attributes":{"dn":"uni/fabric/site-s1/building-b1/floor-f1"},"children":[
+				{"geoRoom":{"attributes":{"dn":"uni/fabric/site-s1/building-b1/floor-f1/room-r1"},"children":[
+					{"geoRow":{"attributes":{"dn":"uni/fabric/site-s1/building-b1/floor-f1/room-r1/row-w1"},"children":[
+						{"geoRack":{"attributes":{"dn":"`+rack+`"},"children":[
+							{"geoRsNodeLocation":{"attributes":{"dn":"`+rack+`/rsnodeLocation-[topology/pod-1/node-102]","tDn":"topology/pod-1/node-102","status":"created,modified"}}}
+						]}}
+					]}}
+				]}}
+			]}}
+		]}},
+		{"geoBuilding":{"attributes":{"dn":"uni/fabric/site-s1/building-b3","name":"b3","descr":"","status":"created,modified"},"children":[
+			{"geoFloor":{"attributes":{"dn":"uni/fabric/site-s1/building-b3/floor-f3","name":"f3","descr":"third","status":"created,modified"}}}
+		]}},
+		{"geoBuilding":{"attributes":{"dn":"uni/fabric/site-s1/building-b2","status":"deleted"}}}
+	]}}`)
+}
+
+func TestDiffNodeRelations(t *testing.T) {
+	f, c := newFakeAPIC(t, map[string]string{"/api/node/class/geoSite.json": liveSites})
+
+	site := desiredSite(t, "old", newTestNode(t, "leaf-102", "102", "1", "FDO102", "leaf"))
+	b2, _ := c.Geolocation.NewBuilding("b2", "")
+	site.AddBuilding(b2)
+
+	d, _, err := c.Geolocation.Sync(context.Background(), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rack := "uni/fabric/site-s1/building-b1/floor-f1/room-r1/row-w1/rack-k1"
+	if want := []string{rack + "/rsnodeLocation-[topology/pod-1/node-102]"}; !reflect.DeepEqual(d.Created, want) {
+		t.Errorf("created %v, want %v", d.Created, want)
+	}
+	if want := []string{rack + "/rsnodeLocation-[topology/pod-1/node-101]"}; !reflect.DeepEqual(d.Deleted, want) {
+		t.Errorf("deleted %v, want %v", d.Deleted, want)
+	}
+	if len(d.Modified) != 0 {
+		t.Errorf("modified %v, want none", d.Modified)
+	}
+
+	posts := f.posted("/api/node/mo/uni/fabric/site-s1.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"geoSite":{"attributes":{"dn":"uni/fabric/site-s1"},"children":[
+		{"geoBuilding":{"attributes":{"dn":"uni/fabric/site-s1/building-b1"},"children":[
+			{"geoFloor":{"attributes":{"dn":"uni/fabric/site-s1/building-b1/floor-f1"},"children":[
+				{"geoRoom":{"attributes":{"dn":"uni/fabric/site-s1/building-b1/floor-f1/room-r1"},"children":[
+					{"geoRow":{"attributes":{"dn":"uni/fabric/site-s1/building-b1/floor-f1/room-r1/row-w1"},"children":[
+						{"geoRack":{"attributes":{"dn":"`+rack+`"},"children":[
+							{"geoRsNodeLocation":{"attributes":{"dn":"`+rack+`/rsnodeLocation-[topology/pod-1/node-102]","tDn":"topology/pod-1/node-102","status":"created,modified"}}},
+							{"geoRsNodeLocation":{"attributes":{"dn":"`+rack+`/rsnodeLocation-[topology/pod-1/node-101]","status":"deleted"}}}
+						]}}
+					]}}
+				]}}
+			]}}
+		]}}
+	]}}`)
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	f, c := newFakeAPIC(t, map[string]string{"/api/node/class/geoSite.json": liveSites})
+
+	site := desiredSite(t, "old", newTestNode(t, "leaf-101", "101", "1", "FDO101", "leaf"))
+	b2, _ := c.Geolocation.NewBuilding("b2", "")
+	site.AddBuilding(b2)
+
+	d, _, err := c.Geolocation.Sync(context.Background(), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Empty() {
+		t.Errorf("got diff %+v, want none", *d)
+	}
+	if n := f.postCount(); n != 0 {
+		t.Errorf("got %d posts, want none", n)
+	}
+}
+
+func TestDiffNewSite(t *testing.T) {
+	_, c := newFakeAPIC(t, nil)
+
+	site, _ := c.Geolocation.NewSite("s2", "")
+	d, err := c.Geolocation.Diff(context.Background(), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"uni/fabric/site-s2"}; !reflect.DeepEqual(d.Created, want) {
+		t.Errorf("created %v, want %v", d.Created, want)
+	}
+	b, err := json.Marshal(d.payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonEqual(t, string(b), `{"geoSite":{"attributes":{"dn":"uni/fabric/site-s2","name":"s2","descr":"","status":"created,modified"}}}`)
+}
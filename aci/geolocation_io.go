@@ -0,0 +1,544 @@
+package aci
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// siteColumns are the CSV header columns for geolocation sites, in the
+// order they are exported. This is the layout of a DCIM rack export.
+var siteColumns = []string{"site", "building", "floor", "room", "row", "rack", "device"}
+
+// siteImport builds sites from imported data, collecting an error
+// for every line that fails validation.
+type siteImport struct {
+	s     *GeolocationService
+	sites []*Site
+	nodes []*Node
+	errs  ImportErrors
+}
+
+func (si *siteImport) errorf(line int, format string, a ...interface{}) {
+	si.errs = append(si.errs, &LineError{Line: line, Err: fmt.Errorf(format, a...)})
+}
+
+// node returns the fabric node with the given name or id.
+func (si *siteImport) node(device string) *Node {
+	for _, node := range si.nodes {
+		if node.Name() == device || node.ID() == device {
+			return node
+		}
+	}
+	return nil
+}
+
+func (si *siteImport) site(name string) *Site {
+	for _, site := range si.sites {
+		if site.Name() == name {
+			return site
+		}
+	}
+	return nil
+}
+
+// ImportSites reads geolocation sites in the given format,
+// either FormatCSV or FormatYAML.
+//
+// CSV input, such as a DCIM rack export, must begin with a header naming
+// the site, building, floor, room, row and rack columns, in any order,
+// with one line for each rack. A line may stop short of the rack to
+// describe an empty part of the hierarchy. An optional device column
+// installs a fabric node in the rack.
+//
+// YAML input must be a list of sites, each a mapping with a name, an
+// optional description and a list of buildings, which in turn hold
+// floors, rooms, rows and racks in the same way. Racks hold a list of
+// nodes rather than further locations.
+//
+// Devices and nodes are the names or ids of the given fabric nodes.
+// The sites that could be built are returned, along with an ImportErrors
+// describing each line that is not valid.
+func (s *GeolocationService) ImportSites(r io.Reader, format string, nodes ...*Node) ([]*Site, error) {
+	si := &siteImport{s: s, nodes: nodes}
+
+	var err error
+	switch format {
+	case FormatCSV:
+		err = si.readCSV(r)
+	case FormatYAML:
+		err = si.readYAML(r)
+	default:
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("import sites: %v", err)
+	}
+
+	if len(si.errs) > 0 {
+		return si.sites, si.errs
+	}
+	return si.sites, nil
+}
+
+func (si *siteImport) readCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("read header: %v", err)
+	}
+
+	index := make(map[string]int)
+	for i, col := range header {
+		index[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+	for _, col := range siteColumns[:6] {
+		if _, ok := index[col]; !ok {
+			return fmt.Errorf("missing column: %s", col)
+		}
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(col string) string {
+			i, ok := index[col]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		var path []string
+		for _, col := range siteColumns[:6] {
+			path = append(path, field(col))
+		}
+		si.addPath(line, path, field("device"))
+	}
+}
+
+// addPath adds the locations named by path, from the site down to the rack,
+// to the imported sites, installing the device in the rack if one is given.
+func (si *siteImport) addPath(line int, path []string, device string) {
+	depth := 0
+	for depth < len(path) && path[depth] != "" {
+		depth++
+	}
+	for i := depth; i < len(path); i++ {
+		if path[i] != "" {
+			si.errorf(line, "%s given without %s", siteColumns[i], siteColumns[depth])
+			return
+		}
+	}
+	if depth == 0 {
+		si.errorf(line, "missing site")
+		return
+	}
+	if device != "" && depth < 6 {
+		si.errorf(line, "device %s given without rack", device)
+		return
+	}
+
+	// validate everything before changing any site,
+	// so that a bad line adds nothing at all
+	site, err := si.s.NewSite(path[0], "")
+	if err != nil {
+		si.errorf(line, "site: %v", err)
+		return
+	}
+	var (
+		building *Building
+		floor    *Floor
+		room     *Room
+		row      *Row
+		rack     *Rack
+	)
+	for i := 1; i < depth; i++ {
+		switch i {
+		case 1:
+			building, err = si.s.NewBuilding(path[i], "")
+		case 2:
+			floor, err = si.s.NewFloor(path[i], "")
+		case 3:
+			room, err = si.s.NewRoom(path[i], "")
+		case 4:
+			row, err = si.s.NewRow(path[i], "")
+		case 5:
+			rack, err = si.s.NewRack(path[i], "")
+		}
+		if err != nil {
+			si.errorf(line, "%s: %v", siteColumns[i], err)
+			return
+		}
+	}
+	var node *Node
+	if device != "" {
+		if node = si.node(device); node == nil {
+			si.errorf(line, "unknown device: %s", device)
+			return
+		}
+	}
+
+	if existing := si.site(site.Name()); existing != nil {
+		site = existing
+	} else {
+		si.sites = append(si.sites, site)
+	}
+	if depth < 2 {
+		return
+	}
+	if b := site.Building(building.Name()); b != nil {
+		building = b
+	} else {
+		site.AddBuilding(building)
+	}
+	if depth < 3 {
+		return
+	}
+	if f := building.Floor(floor.Name()); f != nil {
+		floor = f
+	} else {
+		building.AddFloor(floor)
+	}
+	if depth < 4 {
+		return
+	}
+	if r := floor.Room(room.Name()); r != nil {
+		room = r
+	} else {
+		floor.AddRoom(room)
+	}
+	if depth < 5 {
+		return
+	}
+	if r := room.Row(row.Name()); r != nil {
+		row = r
+	} else {
+		room.AddRow(row)
+	}
+	if depth < 6 {
+		return
+	}
+	if r := row.Rack(rack.Name()); r != nil {
+		rack = r
+	} else {
+		row.AddRack(rack)
+	}
+	if node != nil {
+		rack.AddNode(node)
+	}
+}
+
+// yamlChildren are the keys holding the children of each level of a
+// YAML geolocation hierarchy, from the site down to the rack.
+var yamlChildren = []string{"buildings", "floors", "rooms", "rows", "racks", "nodes"}
+
+func (si *siteImport) readYAML(r io.Reader) error {
+	var doc yaml.Node
+	err := yaml.NewDecoder(r).Decode(&doc)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	list := &doc
+	if list.Kind == yaml.DocumentNode && len(list.Content) > 0 {
+		list = list.Content[0]
+	}
+	if list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: expected a list of sites", list.Line)
+	}
+
+	for _, item := range list.Content {
+		name, description, children, ok := si.yamlLocation(item, 0)
+		if !ok {
+			continue
+		}
+		site, err := si.s.NewSite(name, description)
+		if err != nil {
+			si.errorf(item.Line, "site: %v", err)
+			continue
+		}
+		if existing := si.site(site.Name()); existing != nil {
+			si.errorf(item.Line, "duplicate site: %s", site.Name())
+			continue
+		}
+		for _, c := range children {
+			if building := si.yamlBuilding(c); building != nil {
+				site.AddBuilding(building)
+			}
+		}
+		si.sites = append(si.sites, site)
+	}
+	return nil
+}
+
+// yamlLocation reads the name and description of a YAML location at
+// the given depth of the hierarchy, along with its children.
+func (si *siteImport) yamlLocation(item *yaml.Node, depth int) (string, string, []*yaml.Node, bool) {
+	if item.Kind != yaml.MappingNode {
+		si.errorf(item.Line, "expected a %s", siteColumns[depth])
+		return "", "", nil, false
+	}
+
+	var name, description string
+	var children []*yaml.Node
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		switch key.Value {
+		case "name":
+			name = value.Value
+		case "description":
+			description = value.Value
+		case yamlChildren[depth]:
+			if value.Kind != yaml.SequenceNode {
+				si.errorf(value.Line, "expected a list of %s", yamlChildren[depth])
+				return "", "", nil, false
+			}
+			children = value.Content
+		default:
+			si.errorf(key.Line, "unknown %s field: %s", siteColumns[depth], key.Value)
+			return "", "", nil, false
+		}
+	}
+
+	return name, description, children, true
+}
+
+func (si *siteImport) yamlBuilding(item *yaml.Node) *Building {
+	name, description, children, ok := si.yamlLocation(item, 1)
+	if !ok {
+		return nil
+	}
+	building, err := si.s.NewBuilding(name, description)
+	if err != nil {
+		si.errorf(item.Line, "building: %v", err)
+		return nil
+	}
+	for _, c := range children {
+		if floor := si.yamlFloor(c); floor != nil {
+			building.AddFloor(floor)
+		}
+	}
+	return building
+}
+
+func (si *siteImport) yamlFloor(item *yaml.Node) *Floor {
+	name, description, children, ok := si.yamlLocation(item, 2)
+	if !ok {
+		return nil
+	}
+	floor, err := si.s.NewFloor(name, description)
+	if err != nil {
+		si.errorf(item.Line, "floor: %v", err)
+		return nil
+	}
+	for _, c := range children {
+		if room := si.yamlRoom(c); room != nil {
+			floor.AddRoom(room)
+		}
+	}
+	return floor
+}
+
+func (si *siteImport) yamlRoom(item *yaml.Node) *Room {
+	name, description, children, ok := si.yamlLocation(item, 3)
+	if !ok {
+		return nil
+	}
+	room, err := si.s.NewRoom(name, description)
+	if err != nil {
+		si.errorf(item.Line, "room: %v", err)
+		return nil
+	}
+	for _, c := range children {
+		if row := si.yamlRow(c); row != nil {
+			room.AddRow(row)
+		}
+	}
+	return room
+}
+
+func (si *siteImport) yamlRow(item *yaml.Node) *Row {
+	name, description, children, ok := si.yamlLocation(item, 4)
+	if !ok {
+		return nil
+	}
+	row, err := si.s.NewRow(name, description)
+	if err != nil {
+		si.errorf(item.Line, "row: %v", err)
+		return nil
+	}
+	for _, c := range children {
+		if rack := si.yamlRack(c); rack != nil {
+			row.AddRack(rack)
+		}
+	}
+	return row
+}
+
+func (si *siteImport) yamlRack(item *yaml.Node) *Rack {
+	name, description, children, ok := si.yamlLocation(item, 5)
+	if !ok {
+		return nil
+	}
+	rack, err := si.s.NewRack(name, description)
+	if err != nil {
+		si.errorf(item.Line, "rack: %v", err)
+		return nil
+	}
+	for _, c := range children {
+		node := si.node(c.Value)
+		if c.Kind != yaml.ScalarNode || node == nil {
+			si.errorf(c.Line, "unknown device: %s", c.Value)
+			continue
+		}
+		rack.AddNode(node)
+	}
+	return rack
+}
+
+// siteRecord is a geolocation location as it is exported to YAML.
+type siteRecord struct {
+	Name        string       `yaml:"name"`
+	Description string       `yaml:"description,omitempty"`
+	Buildings   []siteRecord `yaml:"buildings,omitempty"`
+	Floors      []siteRecord `yaml:"floors,omitempty"`
+	Rooms       []siteRecord `yaml:"rooms,omitempty"`
+	Rows        []siteRecord `yaml:"rows,omitempty"`
+	Racks       []siteRecord `yaml:"racks,omitempty"`
+	Nodes       []string     `yaml:"nodes,omitempty"`
+}
+
+func newSiteRecord(l *location) siteRecord {
+	return siteRecord{Name: l.Name(), Description: l.Description()}
+}
+
+// ExportSites writes the current geolocation sites, as returned by
+// ListSites, in the given format, either FormatCSV or FormatYAML.
+//
+// Fabric nodes installed in racks are written by node id. The output
+// can be read back in with ImportSites.
+func (s *GeolocationService) ExportSites(ctx context.Context, w io.Writer, format string) error {
+	sites, err := s.ListSites(ctx)
+	if err != nil {
+		return fmt.Errorf("export sites: %v", err)
+	}
+
+	switch format {
+	case FormatCSV:
+		err = writeSitesCSV(w, sites)
+	case FormatYAML:
+		err = writeSitesYAML(w, sites)
+	default:
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("export sites: %v", err)
+	}
+	return nil
+}
+
+func writeSitesCSV(w io.Writer, sites []*Site) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(siteColumns); err != nil {
+		return err
+	}
+
+	// write pads a path out to a full line, one for each level
+	write := func(path ...string) {
+		line := make([]string, len(siteColumns))
+		copy(line, path)
+		cw.Write(line)
+	}
+
+	for _, site := range sites {
+		if len(site.Buildings()) == 0 {
+			write(site.Name())
+		}
+		for _, building := range site.Buildings() {
+			if len(building.Floors()) == 0 {
+				write(site.Name(), building.Name())
+			}
+			for _, floor := range building.Floors() {
+				if len(floor.Rooms()) == 0 {
+					write(site.Name(), building.Name(), floor.Name())
+				}
+				for _, room := range floor.Rooms() {
+					if len(room.Rows()) == 0 {
+						write(site.Name(), building.Name(), floor.Name(), room.Name())
+					}
+					for _, row := range room.Rows() {
+						if len(row.Racks()) == 0 {
+							write(site.Name(), building.Name(), floor.Name(), room.Name(), row.Name())
+						}
+						for _, rack := range row.Racks() {
+							path := []string{site.Name(), building.Name(), floor.Name(), room.Name(), row.Name(), rack.Name()}
+							if len(rack.Nodes()) == 0 {
+								write(path...)
+							}
+							for _, node := range rack.Nodes() {
+								write(append(path, node.ID())...)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeSitesYAML(w io.Writer, sites []*Site) error {
+	var records []siteRecord
+	for _, site := range sites {
+		sr := newSiteRecord(&site.location)
+		for _, building := range site.Buildings() {
+			br := newSiteRecord(&building.location)
+			for _, floor := range building.Floors() {
+				fr := newSiteRecord(&floor.location)
+				for _, room := range floor.Rooms() {
+					rr := newSiteRecord(&room.location)
+					for _, row := range room.Rows() {
+						wr := newSiteRecord(&row.location)
+						for _, rack := range row.Racks() {
+							kr := newSiteRecord(&rack.location)
+							for _, node := range rack.Nodes() {
+								kr.Nodes = append(kr.Nodes, node.ID())
+							}
+							wr.Racks = append(wr.Racks, kr)
+						}
+						rr.Rows = append(rr.Rows, wr)
+					}
+					fr.Rooms = append(fr.Rooms, rr)
+				}
+				br.Floors = append(br.Floors, fr)
+			}
+			sr.Buildings = append(sr.Buildings, br)
+		}
+		records = append(records, sr)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(records); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,72 @@
+package aci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportSitesRoundTrip(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{"/api/node/class/geoSite.json": liveSites})
+	live, err := c.Geolocation.ListSites(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{FormatCSV, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.Geolocation.ExportSites(context.Background(), &buf, format); err != nil {
+				t.Fatal(err)
+			}
+			exported := buf.String()
+
+			sites, err := c.Geolocation.ImportSites(&buf, format, newTestNode(t, "leaf-101", "101", "1", "FDO101", "leaf"))
+			if err != nil {
+				t.Fatalf("%v\n%s", err, exported)
+			}
+			if len(sites) != 1 {
+				t.Fatalf("imported %d sites, want 1", len(sites))
+			}
+
+			got, want := siteObject(sites[0]), siteObject(live[0])
+			if format == FormatCSV {
+				// descriptions are not exported to CSV
+				clearDescriptions(want)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip of\n%s\ngave %+v, want %+v", exported, got, want)
+			}
+		})
+	}
+}
+
+func clearDescriptions(o *geoObject) {
+	o.descr = ""
+	for _, c := range o.children {
+		clearDescriptions(c)
+	}
+}
+
+func TestImportSitesCSVInvalidName(t *testing.T) {
+	s := &GeolocationService{}
+	input := `site,building,floor,room,row,rack
+s1,b1,f1,r1,w1,k1
+s1,b1,-f2
+s1,,f3
+`
+	sites, err := s.ImportSites(strings.NewReader(input), FormatCSV)
+	if len(sites) != 1 || len(sites[0].Buildings()[0].Floors()) != 1 {
+		t.Errorf("invalid lines changed the imported sites")
+	}
+	var errs ImportErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Line != 3 || errs[1].Line != 4 {
+		t.Fatalf("got error %v, want errors on lines 3 and 4", err)
+	}
+	if !strings.HasPrefix(errs[0].Err.Error(), "floor: ") {
+		t.Errorf("got %v, want an invalid floor", errs[0])
+	}
+}
@@ -0,0 +1,257 @@
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// siteFixture is site dc1 with a single building, floor, room, row and rack.
+const siteFixture = `{"imdata":[{"geoSite":{"attributes":{"name":"dc1","descr":"data centre"},"children":[
+	{"geoBuilding":{"attributes":{"name":"b1"},"children":[
+		{"geoFloor":{"attributes":{"name":"f1"},"children":[
+			{"geoRoom":{"attributes":{"name":"r1"},"children":[
+				{"geoRow":{"attributes":{"name":"row1"},"children":[
+					{"geoRack":{"attributes":{"name":"rack1","descr":"first rack"}}}
+				]}}
+			]}}
+		]}}
+	]}}
+]}}]}`
+
+func TestGetSite(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/fabric/site-dc1.json": siteFixture,
+	})
+
+	site, err := c.Geolocation.GetSite(context.Background(), "dc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if site.Name() != "dc1" || site.Description() != "data centre" {
+		t.Errorf("got site %s (%s)", site.Name(), site.Description())
+	}
+	var path []string
+	for _, b := range site.Buildings() {
+		for _, f := range b.Floors() {
+			for _, r := range f.Rooms() {
+				for _, row := range r.Rows() {
+					for _, rack := range row.Racks() {
+						path = append(path, b.Name(), f.Name(), r.Name(), row.Name(), rack.Name(), rack.Description())
+					}
+				}
+			}
+		}
+	}
+	if got, want := strings.Join(path, " "), "b1 f1 r1 row1 rack1 first rack"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if _, err := c.Geolocation.GetSite(context.Background(), "dc2"); err == nil || !strings.Contains(err.Error(), "uni/fabric/site-dc2 not found") {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
+
+func TestGetRack(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/fabric/site-dc1/building-b1/floor-f1/room-r1/row-row1/rack-rack1.json": `{"imdata":[{"geoRack":{"attributes":{"name":"rack1","descr":"first rack"}}}]}`,
+	})
+
+	rack, err := c.Geolocation.GetRack(context.Background(), "dc1", "b1", "f1", "r1", "row1", "rack1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rack.Name() != "rack1" || rack.Description() != "first rack" {
+		t.Errorf("got rack %s (%s)", rack.Name(), rack.Description())
+	}
+}
+
+func TestDeleteLocation(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+
+	if _, err := c.Geolocation.DeleteSite(context.Background(), "dc1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Geolocation.DeleteRow(context.Background(), "dc1", "b1", "f1", "r1", "row1"); err != nil {
+		t.Fatal(err)
+	}
+
+	site := f.posted("/api/node/mo/uni/fabric/site-dc1.json")
+	if len(site) != 1 {
+		t.Fatalf("got %d site posts, want 1", len(site))
+	}
+	jsonEqual(t, site[0], `{"geoSite":{"attributes":{"dn":"uni/fabric/site-dc1","status":"deleted"}}}`)
+
+	row := f.posted("/api/node/mo/uni/fabric/site-dc1/building-b1/floor-f1/room-r1/row-row1.json")
+	if len(row) != 1 {
+		t.Fatalf("got %d row posts, want 1", len(row))
+	}
+	jsonEqual(t, row[0], `{"geoRow":{"attributes":{"dn":"uni/fabric/site-dc1/building-b1/floor-f1/room-r1/row-row1","status":"deleted"}}}`)
+}
+
+func TestRenameSite(t *testing.T) {
+	f, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/fabric/site-dc1.json": siteFixture,
+	})
+
+	if _, err := c.Geolocation.RenameSite(context.Background(), "dc1", "dc9"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the renamed site is created with everything within it
+	created := f.posted("/api/node/mo/uni/fabric/site-dc9.json")
+	if len(created) != 1 {
+		t.Fatalf("got %d posts of the new site, want 1", len(created))
+	}
+	for _, want := range []string{
+		`"dn":"uni/fabric/site-dc9"`,
+		`"dn":"uni/fabric/site-dc9/building-b1/floor-f1/room-r1/row-row1/rack-rack1"`,
+		`"descr":"first rack"`,
+	} {
+		if !strings.Contains(created[0], want) {
+			t.Errorf("missing %s in %s", want, created[0])
+		}
+	}
+
+	// and the original deleted
+	removed := f.posted("/api/node/mo/uni/fabric/site-dc1.json")
+	if len(removed) != 1 {
+		t.Fatalf("got %d posts of the old site, want 1", len(removed))
+	}
+	jsonEqual(t, removed[0], `{"geoSite":{"attributes":{"dn":"uni/fabric/site-dc1","status":"deleted"}}}`)
+}
+
+func TestRenameInvalidName(t *testing.T) {
+	f, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/fabric/site-dc1/building-b1.json": `{"imdata":[{"geoBuilding":{"attributes":{"name":"b1"}}}]}`,
+	})
+
+	if _, err := c.Geolocation.RenameBuilding(context.Background(), "dc1", "b1", "b 2"); err == nil {
+		t.Error("expected an invalid name error")
+	}
+	if n := f.postCount(); n != 0 {
+		t.Errorf("got %d posts, want none", n)
+	}
+}
+
+// rackSite returns site dc1 down to the given rack.
+func rackSite(t *testing.T, rack *Rack) *Site {
+	t.Helper()
+	s := &GeolocationService{}
+	site, _ := s.NewSite("dc1", "")
+	building, _ := s.NewBuilding("b1", "")
+	floor, _ := s.NewFloor("f1", "")
+	room, _ := s.NewRoom("r1", "")
+	row, _ := s.NewRow("row1", "")
+	row.AddRack(rack)
+	room.AddRow(row)
+	floor.AddRoom(room)
+	building.AddFloor(floor)
+	site.AddBuilding(building)
+	return site
+}
+
+// rackRelations returns the node relations posted to rack1 of site dc1,
+// as the status and tDn of each.
+func rackRelations(t *testing.T, body string) []string {
+	t.Helper()
+	var c GeoSiteContainer
+	if err := json.Unmarshal([]byte(body), &c); err != nil {
+		t.Fatal(err)
+	}
+	rack := c.GeoBuildings[0].GeoFloors[0].GeoRooms[0].GeoRows[0].GeoRacks[0]
+	var rels []string
+	for _, n := range rack.GeoNodes {
+		rels = append(rels, n.Status+" "+n.TDN)
+	}
+	return rels
+}
+
+func TestUpdateSiteNodes(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	rack, err := c.Geolocation.NewRack("rack1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf101 := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	leaf102 := newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf")
+
+	rack.AddNode(leaf101)
+	rack.AddNode(leaf102)
+	rack.AddNode(leaf101)
+	if _, err := c.Geolocation.UpdateSite(context.Background(), rackSite(t, rack)); err != nil {
+		t.Fatal(err)
+	}
+
+	rack.DeleteNode(leaf102)
+	if _, err := c.Geolocation.UpdateSite(context.Background(), rackSite(t, rack)); err != nil {
+		t.Fatal(err)
+	}
+
+	// adding a removed node back no longer deletes its relation
+	rack.AddNode(leaf102)
+	if _, err := c.Geolocation.UpdateSite(context.Background(), rackSite(t, rack)); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := f.posted("/api/node/mo/uni/fabric/site-dc1.json")
+	if len(posts) != 3 {
+		t.Fatalf("got %d posts, want 3", len(posts))
+	}
+	tests := [][]string{
+		{"created,modified topology/pod-1/node-101", "created,modified topology/pod-1/node-102"},
+		{"created,modified topology/pod-1/node-101", "deleted topology/pod-1/node-102"},
+		{"created,modified topology/pod-1/node-101", "created,modified topology/pod-1/node-102"},
+	}
+	for i, want := range tests {
+		if got := rackRelations(t, posts[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("update %d: got relations %q, want %q", i+1, got, want)
+		}
+	}
+	if got := len(rack.Nodes()); got != 2 {
+		t.Errorf("got %d nodes in the rack, want 2", got)
+	}
+}
+
+func TestNodeLocations(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/class/geoRsNodeLocation.json": `{"imdata":[
+			{"geoRsNodeLocation":{"attributes":{"dn":"uni/fabric/site-dc1/building-b1/floor-f1/room-r1/row-row1/rack-rack1/rsnodeLocation-[topology/pod-1/node-101]","tDn":"topology/pod-1/node-101"}}}
+		]}`,
+	})
+
+	loc, ok, err := c.Geolocation.LocateNode(context.Background(), "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NodeLocation{Site: "dc1", Building: "b1", Floor: "f1", Room: "r1", Row: "row1", Rack: "rack1"}
+	if !ok || loc != want {
+		t.Errorf("got location %v %t, want %v", loc, ok, want)
+	}
+	if got := loc.String(); got != "site-dc1/building-b1/floor-f1/room-r1/row-row1/rack-rack1" {
+		t.Errorf("got location string %s", got)
+	}
+
+	if _, ok, err := c.Geolocation.LocateNode(context.Background(), "102"); ok || err != nil {
+		t.Errorf("got node 102 located %t, error %v", ok, err)
+	}
+}
+
+func TestGetRackNodes(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/fabric/site-dc1/building-b1/floor-f1/room-r1/row-row1/rack-rack1.json": `{"imdata":[{"geoRack":{"attributes":{"name":"rack1"},"children":[
+			{"geoRsNodeLocation":{"attributes":{"tDn":"topology/pod-2/node-201"}}}
+		]}}]}`,
+	})
+
+	rack, err := c.Geolocation.GetRack(context.Background(), "dc1", "b1", "f1", "r1", "row1", "rack1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := rack.Nodes()
+	if len(nodes) != 1 || nodes[0].ID() != "201" || nodes[0].Pod() != "2" {
+		t.Errorf("got rack nodes %v, want node 201 in pod 2", nodes)
+	}
+}
@@ -0,0 +1,433 @@
+package aci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InterfacePolicy is an interface policy, referred to by name from
+// the interface policy groups using it.
+type InterfacePolicy interface {
+	Name() string
+	Description() string
+	DN() string
+	SetCreated() string
+	SetDeleted() string
+	Status() string
+
+	// attrs returns the attributes particular to the policy's class
+	attrs() AccessAttrs
+	// wrap returns a container holding the policy's object
+	wrap(o *AccessObject) AccessContainer
+}
+
+// LinkLevelPolicy is a link level policy, the speed and
+// negotiation of the physical link.
+type LinkLevelPolicy struct {
+	object
+	speed    string
+	autoNeg  bool
+	fecMode  string
+	debounce int
+}
+
+// DN returns the distinguished name of the policy.
+func (p *LinkLevelPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/hintfpol-%s", p.name)
+}
+
+// Speed returns the speed of the link.
+func (p *LinkLevelPolicy) Speed() string {
+	return p.speed
+}
+
+// SetSpeed sets the speed of the link.
+// Can only be "inherit", "100M", "1G", "10G", "25G", "40G", "50G",
+// "100G", "200G" or "400G"
+func (p *LinkLevelPolicy) SetSpeed(speed string) error {
+	switch speed {
+	case "inherit", "100M", "1G", "10G", "25G", "40G", "50G", "100G", "200G", "400G":
+	default:
+		return fmt.Errorf("invalid speed: %s", speed)
+	}
+	p.speed = speed
+	return nil
+}
+
+// AutoNegotiation returns whether the link auto-negotiates.
+func (p *LinkLevelPolicy) AutoNegotiation() bool {
+	return p.autoNeg
+}
+
+// SetAutoNegotiation sets whether the link auto-negotiates.
+func (p *LinkLevelPolicy) SetAutoNegotiation(enabled bool) {
+	p.autoNeg = enabled
+}
+
+// FECMode returns the forward error correction mode of the link.
+func (p *LinkLevelPolicy) FECMode() string {
+	return p.fecMode
+}
+
+// SetFECMode sets the forward error correction mode of the link.
+// Can only be "inherit", "cl74-fc-fec", "cl91-rs-fec", "cons16-rs-fec",
+// "ieee-rs-fec", "kp-fec" or "disable-fec"
+func (p *LinkLevelPolicy) SetFECMode(mode string) error {
+	switch mode {
+	case "inherit", "cl74-fc-fec", "cl91-rs-fec", "cons16-rs-fec", "ieee-rs-fec", "kp-fec", "disable-fec":
+	default:
+		return fmt.Errorf("invalid fec mode: %s", mode)
+	}
+	p.fecMode = mode
+	return nil
+}
+
+// LinkDebounce returns the link debounce interval, in milliseconds.
+func (p *LinkLevelPolicy) LinkDebounce() int {
+	return p.debounce
+}
+
+// SetLinkDebounce validates and sets the link debounce interval.
+//
+// An interval must be between 0 and 5000 milliseconds inclusive.
+func (p *LinkLevelPolicy) SetLinkDebounce(ms int) error {
+	if ms < 0 || ms > 5000 {
+		return fmt.Errorf("invalid link debounce: %d", ms)
+	}
+	p.debounce = ms
+	return nil
+}
+
+func (p *LinkLevelPolicy) attrs() AccessAttrs {
+	autoNeg := "off"
+	if p.autoNeg {
+		autoNeg = "on"
+	}
+	return AccessAttrs{
+		Speed:        p.speed,
+		AutoNeg:      autoNeg,
+		FecMode:      p.fecMode,
+		LinkDebounce: strconv.Itoa(p.debounce),
+	}
+}
+
+func (p *LinkLevelPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{HIfPol: o}
+}
+
+// String returns the string representation of a link level policy
+func (p *LinkLevelPolicy) String() string {
+	return fmt.Sprintf("%s speed %s", p.name, p.speed)
+}
+
+// LACPPolicy is a port-channel policy, the LACP mode of the port-channels
+// and vPCs using it, and their number of links.
+type LACPPolicy struct {
+	object
+	mode     string
+	minLinks int
+	maxLinks int
+}
+
+// DN returns the distinguished name of the policy.
+func (p *LACPPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/lacplagp-%s", p.name)
+}
+
+// Mode returns the LACP mode of the policy.
+func (p *LACPPolicy) Mode() string {
+	return p.mode
+}
+
+// SetMode sets the LACP mode of the policy.
+// Can only be "off" (static), "active", "passive", "mac-pin",
+// "mac-pin-nicload" or "explicit-failover"
+func (p *LACPPolicy) SetMode(mode string) error {
+	switch mode {
+	case "off", "active", "passive", "mac-pin", "mac-pin-nicload", "explicit-failover":
+	default:
+		return fmt.Errorf("invalid lacp mode: %s", mode)
+	}
+	p.mode = mode
+	return nil
+}
+
+// Links returns the minimum and maximum number of links.
+func (p *LACPPolicy) Links() (int, int) {
+	return p.minLinks, p.maxLinks
+}
+
+// SetLinks validates and sets the minimum and maximum number of links.
+//
+// Both must be between 1 and 16 inclusive, the minimum
+// no more than the maximum.
+func (p *LACPPolicy) SetLinks(min, max int) error {
+	if min < 1 || max > 16 || min > max {
+		return fmt.Errorf("invalid links: %d-%d", min, max)
+	}
+	p.minLinks, p.maxLinks = min, max
+	return nil
+}
+
+func (p *LACPPolicy) attrs() AccessAttrs {
+	return AccessAttrs{
+		Mode:     p.mode,
+		MinLinks: strconv.Itoa(p.minLinks),
+		MaxLinks: strconv.Itoa(p.maxLinks),
+	}
+}
+
+func (p *LACPPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{LagPol: o}
+}
+
+// String returns the string representation of an LACP policy
+func (p *LACPPolicy) String() string {
+	return fmt.Sprintf("%s mode %s", p.name, p.mode)
+}
+
+// CDPPolicy is a CDP interface policy.
+type CDPPolicy struct {
+	object
+	enabled bool
+}
+
+// DN returns the distinguished name of the policy.
+func (p *CDPPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/cdpIfP-%s", p.name)
+}
+
+// Enabled returns whether CDP is enabled.
+func (p *CDPPolicy) Enabled() bool {
+	return p.enabled
+}
+
+// SetEnabled sets whether CDP is enabled.
+func (p *CDPPolicy) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+func (p *CDPPolicy) attrs() AccessAttrs {
+	return AccessAttrs{AdminSt: adminState(p.enabled)}
+}
+
+func (p *CDPPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{CdpIfPol: o}
+}
+
+// String returns the string representation of a CDP policy
+func (p *CDPPolicy) String() string {
+	return fmt.Sprintf("%s cdp %s", p.name, adminState(p.enabled))
+}
+
+// LLDPPolicy is an LLDP interface policy.
+type LLDPPolicy struct {
+	object
+	receive  bool
+	transmit bool
+}
+
+// DN returns the distinguished name of the policy.
+func (p *LLDPPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/lldpIfP-%s", p.name)
+}
+
+// Receive returns whether LLDP is received.
+func (p *LLDPPolicy) Receive() bool {
+	return p.receive
+}
+
+// SetReceive sets whether LLDP is received.
+func (p *LLDPPolicy) SetReceive(enabled bool) {
+	p.receive = enabled
+}
+
+// Transmit returns whether LLDP is transmitted.
+func (p *LLDPPolicy) Transmit() bool {
+	return p.transmit
+}
+
+// SetTransmit sets whether LLDP is transmitted.
+func (p *LLDPPolicy) SetTransmit(enabled bool) {
+	p.transmit = enabled
+}
+
+func (p *LLDPPolicy) attrs() AccessAttrs {
+	return AccessAttrs{
+		AdminRxSt: adminState(p.receive),
+		AdminTxSt: adminState(p.transmit),
+	}
+}
+
+func (p *LLDPPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{LldpIfPol: o}
+}
+
+// String returns the string representation of an LLDP policy
+func (p *LLDPPolicy) String() string {
+	return fmt.Sprintf("%s lldp rx %s tx %s", p.name, adminState(p.receive), adminState(p.transmit))
+}
+
+// MCPPolicy is a MisCabling Protocol interface policy,
+// detecting loops from outside the fabric.
+type MCPPolicy struct {
+	object
+	enabled bool
+}
+
+// DN returns the distinguished name of the policy.
+func (p *MCPPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/mcpIfP-%s", p.name)
+}
+
+// Enabled returns whether MCP is enabled.
+func (p *MCPPolicy) Enabled() bool {
+	return p.enabled
+}
+
+// SetEnabled sets whether MCP is enabled.
+func (p *MCPPolicy) SetEnabled(enabled bool) {
+	p.enabled = enabled
+}
+
+func (p *MCPPolicy) attrs() AccessAttrs {
+	return AccessAttrs{AdminSt: adminState(p.enabled)}
+}
+
+func (p *MCPPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{McpIfPol: o}
+}
+
+// String returns the string representation of an MCP policy
+func (p *MCPPolicy) String() string {
+	return fmt.Sprintf("%s mcp %s", p.name, adminState(p.enabled))
+}
+
+// STPPolicy is a spanning tree interface policy, guarding
+// against or filtering BPDUs from outside the fabric.
+type STPPolicy struct {
+	object
+	bpduGuard  bool
+	bpduFilter bool
+}
+
+// DN returns the distinguished name of the policy.
+func (p *STPPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/ifPol-%s", p.name)
+}
+
+// BPDUGuard returns whether BPDU guard is enabled.
+func (p *STPPolicy) BPDUGuard() bool {
+	return p.bpduGuard
+}
+
+// SetBPDUGuard sets whether BPDU guard is enabled, disabling
+// ports that receive BPDUs.
+func (p *STPPolicy) SetBPDUGuard(enabled bool) {
+	p.bpduGuard = enabled
+}
+
+// BPDUFilter returns whether BPDU filter is enabled.
+func (p *STPPolicy) BPDUFilter() bool {
+	return p.bpduFilter
+}
+
+// SetBPDUFilter sets whether BPDU filter is enabled, ignoring
+// the BPDUs ports receive.
+func (p *STPPolicy) SetBPDUFilter(enabled bool) {
+	p.bpduFilter = enabled
+}
+
+func (p *STPPolicy) attrs() AccessAttrs {
+	var ctrl []string
+	if p.bpduFilter {
+		ctrl = append(ctrl, "bpdu-filter")
+	}
+	if p.bpduGuard {
+		ctrl = append(ctrl, "bpdu-guard")
+	}
+	return AccessAttrs{Ctrl: strings.Join(ctrl, ",")}
+}
+
+func (p *STPPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{StpIfPol: &STPObject{*o}}
+}
+
+// String returns the string representation of an STP policy
+func (p *STPPolicy) String() string {
+	return fmt.Sprintf("%s bpdu guard %t filter %t", p.name, p.bpduGuard, p.bpduFilter)
+}
+
+// StormControlPolicy is a storm control interface policy, limiting the
+// broadcast, unknown unicast and multicast traffic of ports.
+type StormControlPolicy struct {
+	object
+	rate   float64
+	burst  float64
+	action string
+}
+
+// DN returns the distinguished name of the policy.
+func (p *StormControlPolicy) DN() string {
+	return fmt.Sprintf("uni/infra/stormctrlifp-%s", p.name)
+}
+
+// Rate returns the rate and burst rate of storm traffic allowed,
+// as percentages of the link's bandwidth.
+func (p *StormControlPolicy) Rate() (float64, float64) {
+	return p.rate, p.burst
+}
+
+// SetRate validates and sets the rate and burst rate of storm traffic allowed.
+//
+// Both are percentages of the link's bandwidth between 0 and 100 inclusive,
+// and the burst rate can not be lower than the rate.
+func (p *StormControlPolicy) SetRate(rate, burst float64) error {
+	if rate < 0 || burst > 100 || rate > burst {
+		return fmt.Errorf("invalid storm control rate: %g-%g", rate, burst)
+	}
+	p.rate, p.burst = rate, burst
+	return nil
+}
+
+// Action returns the action taken on ports exceeding the rate.
+func (p *StormControlPolicy) Action() string {
+	return p.action
+}
+
+// SetAction sets the action taken on ports exceeding the rate.
+// Can only be "drop" (the excess traffic) or "shutdown" (the port)
+func (p *StormControlPolicy) SetAction(action string) error {
+	if action != "drop" && action != "shutdown" {
+		return fmt.Errorf("invalid storm control action: %s", action)
+	}
+	p.action = action
+	return nil
+}
+
+func (p *StormControlPolicy) attrs() AccessAttrs {
+	// formatted as the APIC returns them, so that policies compare equal
+	return AccessAttrs{
+		Rate:            strconv.FormatFloat(p.rate, 'f', 6, 64),
+		BurstRate:       strconv.FormatFloat(p.burst, 'f', 6, 64),
+		StormCtrlAction: p.action,
+	}
+}
+
+func (p *StormControlPolicy) wrap(o *AccessObject) AccessContainer {
+	return AccessContainer{StormctrlIfPol: o}
+}
+
+// String returns the string representation of a storm control policy
+func (p *StormControlPolicy) String() string {
+	return fmt.Sprintf("%s rate %g%% burst %g%% %s", p.name, p.rate, p.burst, p.action)
+}
+
+// adminState returns the APIC representation of an admin state.
+func adminState(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,419 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NewLinkLevelPolicy instantiates a valid link level policy of the given
+// speed, auto-negotiating, with the default FEC mode and link debounce.
+func (s *AccessPolicyService) NewLinkLevelPolicy(name, speed string) (*LinkLevelPolicy, error) {
+	p := &LinkLevelPolicy{autoNeg: true, fecMode: "inherit", debounce: 100}
+
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+
+	if err := p.SetSpeed(speed); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
+
+// NewLACPPolicy instantiates a valid LACP policy of the given mode,
+// with between 1 and 16 links.
+func (s *AccessPolicyService) NewLACPPolicy(name, mode string) (*LACPPolicy, error) {
+	p := &LACPPolicy{minLinks: 1, maxLinks: 16}
+
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+
+	if err := p.SetMode(mode); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
+
+// NewCDPPolicy instantiates a valid CDP policy.
+func (s *AccessPolicyService) NewCDPPolicy(name string, enabled bool) (*CDPPolicy, error) {
+	p := &CDPPolicy{enabled: enabled}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewLLDPPolicy instantiates a valid LLDP policy.
+func (s *AccessPolicyService) NewLLDPPolicy(name string, receive, transmit bool) (*LLDPPolicy, error) {
+	p := &LLDPPolicy{receive: receive, transmit: transmit}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewMCPPolicy instantiates a valid MCP policy.
+func (s *AccessPolicyService) NewMCPPolicy(name string, enabled bool) (*MCPPolicy, error) {
+	p := &MCPPolicy{enabled: enabled}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewSTPPolicy instantiates a valid STP policy.
+func (s *AccessPolicyService) NewSTPPolicy(name string, bpduGuard, bpduFilter bool) (*STPPolicy, error) {
+	p := &STPPolicy{bpduGuard: bpduGuard, bpduFilter: bpduFilter}
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewStormControlPolicy instantiates a valid storm control policy,
+// dropping the traffic exceeding the given rates.
+func (s *AccessPolicyService) NewStormControlPolicy(name string, rate, burst float64) (*StormControlPolicy, error) {
+	p := &StormControlPolicy{action: "drop"}
+
+	if err := p.SetName(name); err != nil {
+		return p, err
+	}
+
+	if err := p.SetRate(rate, burst); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
+
+func newInterfacePolicyContainer(p InterfacePolicy) AccessContainer {
+	attrs := p.attrs()
+	attrs.DN = p.DN()
+	attrs.Name = p.Name()
+	attrs.Descr = p.Description()
+	attrs.Status = p.Status()
+	if p.Status() == deleted {
+		attrs = AccessAttrs{DN: p.DN(), Status: deleted}
+	}
+	return p.wrap(&AccessObject{AccessAttrs: attrs})
+}
+
+// CreateInterfacePolicy creates an interface policy.
+func (s *AccessPolicyService) CreateInterfacePolicy(ctx context.Context, p InterfacePolicy) (AccessResponse, error) {
+	p.SetCreated()
+	return s.UpdateInterfacePolicy(ctx, p)
+}
+
+// DeleteInterfacePolicy deletes an interface policy.
+func (s *AccessPolicyService) DeleteInterfacePolicy(ctx context.Context, p InterfacePolicy) (AccessResponse, error) {
+	p.SetDeleted()
+	return s.UpdateInterfacePolicy(ctx, p)
+}
+
+// UpdateInterfacePolicy creates, modifies or deletes an interface policy
+// according to its status.
+func (s *AccessPolicyService) UpdateInterfacePolicy(ctx context.Context, p InterfacePolicy) (AccessResponse, error) {
+	return s.postAccess(ctx, p.DN(), newInterfacePolicyContainer(p))
+}
+
+// listPolicies retrieves every interface policy of a class, converting each
+// with the given function, which returns nil for containers of other classes.
+func (s *AccessPolicyService) listPolicies(ctx context.Context, class string, convert func(AccessContainer) InterfacePolicy) ([]InterfacePolicy, error) {
+	ac, err := s.listClass(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	var policies []InterfacePolicy
+	for _, c := range ac {
+		if p := convert(c); p != nil {
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+// policyObject returns the object of an interface policy returned by the
+// APIC. We trust the APIC to return valid policy attributes throughout.
+func policyObject(o *AccessObject) object {
+	return object{name: o.Name, description: o.Descr, status: o.Status}
+}
+
+func linkLevelPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.HIfPol == nil {
+		return nil
+	}
+	debounce, _ := strconv.Atoi(c.HIfPol.LinkDebounce)
+	return &LinkLevelPolicy{
+		object:   policyObject(c.HIfPol),
+		speed:    c.HIfPol.Speed,
+		autoNeg:  c.HIfPol.AutoNeg != "off",
+		fecMode:  c.HIfPol.FecMode,
+		debounce: debounce,
+	}
+}
+
+func lacpPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.LagPol == nil {
+		return nil
+	}
+	min, _ := strconv.Atoi(c.LagPol.MinLinks)
+	max, _ := strconv.Atoi(c.LagPol.MaxLinks)
+	return &LACPPolicy{
+		object:   policyObject(c.LagPol),
+		mode:     c.LagPol.Mode,
+		minLinks: min,
+		maxLinks: max,
+	}
+}
+
+func cdpPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.CdpIfPol == nil {
+		return nil
+	}
+	return &CDPPolicy{
+		object:  policyObject(c.CdpIfPol),
+		enabled: c.CdpIfPol.AdminSt == "enabled",
+	}
+}
+
+func lldpPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.LldpIfPol == nil {
+		return nil
+	}
+	return &LLDPPolicy{
+		object:   policyObject(c.LldpIfPol),
+		receive:  c.LldpIfPol.AdminRxSt == "enabled",
+		transmit: c.LldpIfPol.AdminTxSt == "enabled",
+	}
+}
+
+func mcpPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.McpIfPol == nil {
+		return nil
+	}
+	return &MCPPolicy{
+		object:  policyObject(c.McpIfPol),
+		enabled: c.McpIfPol.AdminSt == "enabled",
+	}
+}
+
+func stpPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.StpIfPol == nil {
+		return nil
+	}
+	p := &STPPolicy{object: policyObject(&c.StpIfPol.AccessObject)}
+	for _, flag := range strings.Split(c.StpIfPol.Ctrl, ",") {
+		switch flag {
+		case "bpdu-guard":
+			p.bpduGuard = true
+		case "bpdu-filter":
+			p.bpduFilter = true
+		}
+	}
+	return p
+}
+
+func stormControlPolicyFromResponse(c AccessContainer) InterfacePolicy {
+	if c.StormctrlIfPol == nil {
+		return nil
+	}
+	rate, _ := strconv.ParseFloat(c.StormctrlIfPol.Rate, 64)
+	burst, _ := strconv.ParseFloat(c.StormctrlIfPol.BurstRate, 64)
+	return &StormControlPolicy{
+		object: policyObject(c.StormctrlIfPol),
+		rate:   rate,
+		burst:  burst,
+		action: c.StormctrlIfPol.StormCtrlAction,
+	}
+}
+
+// interfacePolicyClasses are the classes of interface policy,
+// with the functions converting them.
+var interfacePolicyClasses = []struct {
+	class   string
+	convert func(AccessContainer) InterfacePolicy
+}{
+	{"fabricHIfPol", linkLevelPolicyFromResponse},
+	{"lacpLagPol", lacpPolicyFromResponse},
+	{"cdpIfPol", cdpPolicyFromResponse},
+	{"lldpIfPol", lldpPolicyFromResponse},
+	{"mcpIfPol", mcpPolicyFromResponse},
+	{"stpIfPol", stpPolicyFromResponse},
+	{"stormctrlIfPol", stormControlPolicyFromResponse},
+}
+
+// ListLinkLevelPolicies lists all link level policies.
+func (s *AccessPolicyService) ListLinkLevelPolicies(ctx context.Context) ([]*LinkLevelPolicy, error) {
+	ps, err := s.listPolicies(ctx, "fabricHIfPol", linkLevelPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list link level policies: %v", err)
+	}
+	var policies []*LinkLevelPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*LinkLevelPolicy))
+	}
+	return policies, nil
+}
+
+// ListLACPPolicies lists all LACP policies.
+func (s *AccessPolicyService) ListLACPPolicies(ctx context.Context) ([]*LACPPolicy, error) {
+	ps, err := s.listPolicies(ctx, "lacpLagPol", lacpPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list lacp policies: %v", err)
+	}
+	var policies []*LACPPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*LACPPolicy))
+	}
+	return policies, nil
+}
+
+// ListCDPPolicies lists all CDP policies.
+func (s *AccessPolicyService) ListCDPPolicies(ctx context.Context) ([]*CDPPolicy, error) {
+	ps, err := s.listPolicies(ctx, "cdpIfPol", cdpPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list cdp policies: %v", err)
+	}
+	var policies []*CDPPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*CDPPolicy))
+	}
+	return policies, nil
+}
+
+// ListLLDPPolicies lists all LLDP policies.
+func (s *AccessPolicyService) ListLLDPPolicies(ctx context.Context) ([]*LLDPPolicy, error) {
+	ps, err := s.listPolicies(ctx, "lldpIfPol", lldpPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list lldp policies: %v", err)
+	}
+	var policies []*LLDPPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*LLDPPolicy))
+	}
+	return policies, nil
+}
+
+// ListMCPPolicies lists all MCP policies.
+func (s *AccessPolicyService) ListMCPPolicies(ctx context.Context) ([]*MCPPolicy, error) {
+	ps, err := s.listPolicies(ctx, "mcpIfPol", mcpPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list mcp policies: %v", err)
+	}
+	var policies []*MCPPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*MCPPolicy))
+	}
+	return policies, nil
+}
+
+// ListSTPPolicies lists all STP policies.
+func (s *AccessPolicyService) ListSTPPolicies(ctx context.Context) ([]*STPPolicy, error) {
+	ps, err := s.listPolicies(ctx, "stpIfPol", stpPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list stp policies: %v", err)
+	}
+	var policies []*STPPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*STPPolicy))
+	}
+	return policies, nil
+}
+
+// ListStormControlPolicies lists all storm control policies.
+func (s *AccessPolicyService) ListStormControlPolicies(ctx context.Context) ([]*StormControlPolicy, error) {
+	ps, err := s.listPolicies(ctx, "stormctrlIfPol", stormControlPolicyFromResponse)
+	if err != nil {
+		return nil, fmt.Errorf("list storm control policies: %v", err)
+	}
+	var policies []*StormControlPolicy
+	for _, p := range ps {
+		policies = append(policies, p.(*StormControlPolicy))
+	}
+	return policies, nil
+}
+
+// StandardInterfacePolicies returns a standard set of interface policies,
+// named after what they do, for policy groups to choose from:
+//
+//	link level    1G, 10G, 25G, 40G, 100G (auto-negotiating)
+//	LACP          lacp-active, lacp-passive, static-on, mac-pinning
+//	CDP           cdp-enabled, cdp-disabled
+//	LLDP          lldp-enabled, lldp-disabled
+//	MCP           mcp-enabled, mcp-disabled
+//	STP           bpdu-guard, bpdu-filter, stp-default
+//	storm control storm-control-off (100%)
+func (s *AccessPolicyService) StandardInterfacePolicies() []InterfacePolicy {
+	var policies []InterfacePolicy
+	// the names and values are known to be valid
+	for _, speed := range []string{"1G", "10G", "25G", "40G", "100G"} {
+		p, _ := s.NewLinkLevelPolicy(speed, speed)
+		policies = append(policies, p)
+	}
+	for _, lacp := range [][2]string{
+		{"lacp-active", "active"},
+		{"lacp-passive", "passive"},
+		{"static-on", "off"},
+		{"mac-pinning", "mac-pin"},
+	} {
+		p, _ := s.NewLACPPolicy(lacp[0], lacp[1])
+		policies = append(policies, p)
+	}
+	cdpEnabled, _ := s.NewCDPPolicy("cdp-enabled", true)
+	cdpDisabled, _ := s.NewCDPPolicy("cdp-disabled", false)
+	lldpEnabled, _ := s.NewLLDPPolicy("lldp-enabled", true, true)
+	lldpDisabled, _ := s.NewLLDPPolicy("lldp-disabled", false, false)
+	mcpEnabled, _ := s.NewMCPPolicy("mcp-enabled", true)
+	mcpDisabled, _ := s.NewMCPPolicy("mcp-disabled", false)
+	bpduGuard, _ := s.NewSTPPolicy("bpdu-guard", true, false)
+	bpduFilter, _ := s.NewSTPPolicy("bpdu-filter", false, true)
+	stpDefault, _ := s.NewSTPPolicy("stp-default", false, false)
+	stormOff, _ := s.NewStormControlPolicy("storm-control-off", 100, 100)
+	return append(policies,
+		cdpEnabled, cdpDisabled,
+		lldpEnabled, lldpDisabled,
+		mcpEnabled, mcpDisabled,
+		bpduGuard, bpduFilter, stpDefault,
+		stormOff,
+	)
+}
+
+// EnsurePolicies ensures the interface policies exist on the APIC as given,
+// creating those that are missing and modifying those that differ, and
+// returns the policies it posted. Policies already as given are left alone,
+// so EnsurePolicies can be called repeatedly.
+func (s *AccessPolicyService) EnsurePolicies(ctx context.Context, policies ...InterfacePolicy) ([]InterfacePolicy, error) {
+	live := make(map[string]InterfacePolicy)
+	for _, pc := range interfacePolicyClasses {
+		ps, err := s.listPolicies(ctx, pc.class, pc.convert)
+		if err != nil {
+			return nil, fmt.Errorf("ensure policies: %v", err)
+		}
+		for _, p := range ps {
+			live[p.DN()] = p
+		}
+	}
+
+	var posted []InterfacePolicy
+	for _, p := range policies {
+		if l, ok := live[p.DN()]; ok && l.attrs() == p.attrs() {
+			continue
+		}
+		if _, err := s.CreateInterfacePolicy(ctx, p); err != nil {
+			return posted, fmt.Errorf("ensure policies: %s: %v", p.DN(), err)
+		}
+		posted = append(posted, p)
+	}
+	return posted, nil
+}
+
+// EnsureStandardPolicies ensures the standard set of interface policies,
+// as returned by StandardInterfacePolicies, exists on the APIC.
+func (s *AccessPolicyService) EnsureStandardPolicies(ctx context.Context) ([]InterfacePolicy, error) {
+	return s.EnsurePolicies(ctx, s.StandardInterfacePolicies()...)
+}
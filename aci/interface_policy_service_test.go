@@ -0,0 +1,92 @@
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// apicPolicies holds the attributes of the policies on a fake APIC,
+// by class and distinguished name.
+type apicPolicies map[string]map[string]map[string]string
+
+// apply applies a posted policy as the APIC does, changing only
+// the attributes that are posted.
+func (ap apicPolicies) apply(t *testing.T, body string) {
+	t.Helper()
+	var posted map[string]struct {
+		Attributes map[string]string `json:"attributes"`
+	}
+	if err := json.Unmarshal([]byte(body), &posted); err != nil {
+		t.Fatal(err)
+	}
+	for class, o := range posted {
+		dn := o.Attributes["dn"]
+		if o.Attributes["status"] == deleted {
+			delete(ap[class], dn)
+			continue
+		}
+		if ap[class] == nil {
+			ap[class] = make(map[string]map[string]string)
+		}
+		if ap[class][dn] == nil {
+			ap[class][dn] = make(map[string]string)
+		}
+		for k, v := range o.Attributes {
+			if k != "status" {
+				ap[class][dn][k] = v
+			}
+		}
+	}
+}
+
+// fixtures returns the class queries of the policies.
+func (ap apicPolicies) fixtures() map[string]string {
+	fixtures := make(map[string]string)
+	for class, objects := range ap {
+		var imdata []string
+		for _, attrs := range objects {
+			b, _ := json.Marshal(map[string]interface{}{class: map[string]interface{}{"attributes": attrs}})
+			imdata = append(imdata, string(b))
+		}
+		fixtures[fmt.Sprintf("/api/node/class/%s.json", class)] = fmt.Sprintf(`{"imdata":[%s]}`, strings.Join(imdata, ","))
+	}
+	return fixtures
+}
+
+func TestEnsureStandardPolicies(t *testing.T) {
+	// stp-default has been changed on the APIC to guard against BPDUs
+	live := apicPolicies{"stpIfPol": {"uni/infra/ifPol-stp-default": {
+		"dn": "uni/infra/ifPol-stp-default", "name": "stp-default", "ctrl": "bpdu-guard",
+	}}}
+	f, c := newFakeAPIC(t, live.fixtures())
+
+	posted, err := c.AccessPolicy.EnsureStandardPolicies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(c.AccessPolicy.StandardInterfacePolicies()); len(posted) != want {
+		t.Fatalf("posted %d policies, want %d", len(posted), want)
+	}
+	stp := f.posted("/api/node/mo/uni/infra/ifPol-stp-default.json")
+	if len(stp) != 1 || !strings.Contains(stp[0], `"ctrl":""`) {
+		t.Errorf("stp-default posted as %v, want an empty ctrl", stp)
+	}
+
+	for _, bodies := range f.posts {
+		for _, body := range bodies {
+			live.apply(t, body)
+		}
+	}
+	f, c = newFakeAPIC(t, live.fixtures())
+
+	posted, err = c.AccessPolicy.EnsureStandardPolicies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 0 || f.postCount() != 0 {
+		t.Errorf("second call posted %v", posted)
+	}
+}
@@ -0,0 +1,728 @@
+package aci
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// L3Out is an external routed network, connecting a tenant VRF
+// to routers outside the fabric.
+type L3Out struct {
+	object
+	tenant       string
+	vrf          string
+	domain       string
+	bgp          bool
+	ospfArea     string
+	ospfAreaType string
+	nodeProfiles []*NodeProfile
+	externalEPGs []*ExternalEPG
+}
+
+// Tenant returns the name of the tenant the L3Out belongs to.
+func (l *L3Out) Tenant() string {
+	return l.tenant
+}
+
+// VRF returns the name of the VRF of the L3Out.
+func (l *L3Out) VRF() string {
+	return l.vrf
+}
+
+// SetVRF validates and sets the name of the VRF of the L3Out.
+func (l *L3Out) SetVRF(vrf string) error {
+	if err := validateName("vrf name", vrf); err != nil {
+		return err
+	}
+	l.vrf = vrf
+	return nil
+}
+
+// Domain returns the name of the L3 domain of the L3Out.
+func (l *L3Out) Domain() string {
+	return l.domain
+}
+
+// SetDomain validates and sets the name of the L3 domain of the L3Out.
+func (l *L3Out) SetDomain(domain string) error {
+	if err := validateName("domain name", domain); err != nil {
+		return err
+	}
+	l.domain = domain
+	return nil
+}
+
+// BGP returns whether BGP is enabled on the L3Out.
+func (l *L3Out) BGP() bool {
+	return l.bgp
+}
+
+// SetBGP sets whether BGP is enabled on the L3Out.
+func (l *L3Out) SetBGP(enabled bool) {
+	l.bgp = enabled
+}
+
+// OSPF returns the OSPF area and area type of the L3Out,
+// or empty strings if OSPF is not enabled.
+func (l *L3Out) OSPF() (string, string) {
+	return l.ospfArea, l.ospfAreaType
+}
+
+// SetOSPF validates and sets the OSPF area of the L3Out, enabling OSPF.
+//
+// An area is "backbone", a number, or in dotted decimal notation,
+// such as "0.0.0.1". An area type can only be "regular", "stub" or "nssa",
+// and the backbone must be regular.
+func (l *L3Out) SetOSPF(area, areaType string) error {
+	if area != "backbone" {
+		if _, err := strconv.ParseUint(area, 10, 32); err != nil && net.ParseIP(area).To4() == nil {
+			return fmt.Errorf("invalid ospf area: %s", area)
+		}
+	}
+	switch areaType {
+	case "regular":
+	case "stub", "nssa":
+		if area == "backbone" || area == "0" || area == "0.0.0.0" {
+			return fmt.Errorf("invalid ospf area type: %s for the backbone", areaType)
+		}
+	default:
+		return fmt.Errorf("invalid ospf area type: %s", areaType)
+	}
+	l.ospfArea, l.ospfAreaType = area, areaType
+	return nil
+}
+
+// DisableOSPF disables OSPF on the L3Out.
+func (l *L3Out) DisableOSPF() {
+	l.ospfArea, l.ospfAreaType = "", ""
+}
+
+// NodeProfiles returns the node profiles of the L3Out.
+func (l *L3Out) NodeProfiles() []*NodeProfile {
+	return l.nodeProfiles
+}
+
+// NodeProfile returns the node profile with the given name,
+// or nil if there is none.
+func (l *L3Out) NodeProfile(name string) *NodeProfile {
+	for _, np := range l.nodeProfiles {
+		if np.Name() == name {
+			return np
+		}
+	}
+	return nil
+}
+
+// AddNodeProfile adds a node profile to the L3Out,
+// replacing any node profile of the same name.
+func (l *L3Out) AddNodeProfile(np *NodeProfile) {
+	for i, p := range l.nodeProfiles {
+		if p.Name() == np.Name() {
+			l.nodeProfiles[i] = np
+			return
+		}
+	}
+	l.nodeProfiles = append(l.nodeProfiles, np)
+}
+
+// ExternalEPGs returns the external EPGs of the L3Out.
+func (l *L3Out) ExternalEPGs() []*ExternalEPG {
+	return l.externalEPGs
+}
+
+// ExternalEPG returns the external EPG with the given name,
+// or nil if there is none.
+func (l *L3Out) ExternalEPG(name string) *ExternalEPG {
+	for _, epg := range l.externalEPGs {
+		if epg.Name() == name {
+			return epg
+		}
+	}
+	return nil
+}
+
+// AddExternalEPG adds an external EPG to the L3Out,
+// replacing any external EPG of the same name.
+func (l *L3Out) AddExternalEPG(epg *ExternalEPG) {
+	for i, e := range l.externalEPGs {
+		if e.Name() == epg.Name() {
+			l.externalEPGs[i] = epg
+			return
+		}
+	}
+	l.externalEPGs = append(l.externalEPGs, epg)
+}
+
+// String returns the string representation of an L3Out
+func (l *L3Out) String() string {
+	return fmt.Sprintf("%s/%s", l.tenant, l.name)
+}
+
+// NodeProfile is the set of border leaf nodes of an L3Out,
+// along with their interfaces and BGP peers.
+type NodeProfile struct {
+	object
+	nodes             []*L3Node
+	interfaceProfiles []*InterfaceProfile
+	bgpPeers          []*BGPPeer
+}
+
+// Nodes returns the nodes of the node profile.
+func (np *NodeProfile) Nodes() []*L3Node {
+	return np.nodes
+}
+
+// AddNode adds a node to the node profile,
+// replacing any entry for the same node.
+func (np *NodeProfile) AddNode(node *L3Node) {
+	for i, n := range np.nodes {
+		if n.TDN() == node.TDN() {
+			np.nodes[i] = node
+			return
+		}
+	}
+	np.nodes = append(np.nodes, node)
+}
+
+// InterfaceProfiles returns the interface profiles of the node profile.
+func (np *NodeProfile) InterfaceProfiles() []*InterfaceProfile {
+	return np.interfaceProfiles
+}
+
+// AddInterfaceProfile adds an interface profile to the node profile,
+// replacing any interface profile of the same name.
+func (np *NodeProfile) AddInterfaceProfile(ip *InterfaceProfile) {
+	for i, p := range np.interfaceProfiles {
+		if p.Name() == ip.Name() {
+			np.interfaceProfiles[i] = ip
+			return
+		}
+	}
+	np.interfaceProfiles = append(np.interfaceProfiles, ip)
+}
+
+// BGPPeers returns the BGP peers of the node profile,
+// peered with from the loopbacks of its nodes.
+func (np *NodeProfile) BGPPeers() []*BGPPeer {
+	return np.bgpPeers
+}
+
+// AddBGPPeer adds a BGP peer to the node profile,
+// replacing any peer with the same address.
+func (np *NodeProfile) AddBGPPeer(peer *BGPPeer) {
+	np.bgpPeers = addBGPPeer(np.bgpPeers, peer)
+}
+
+// L3Node is a border leaf node of an L3Out, with its router id
+// and static routes.
+type L3Node struct {
+	pod          string
+	id           string
+	routerID     string
+	loopback     bool
+	staticRoutes []*StaticRoute
+	status       string
+}
+
+// Pod returns the id of the pod of the node.
+func (n *L3Node) Pod() string {
+	return n.pod
+}
+
+// ID returns the id of the node.
+func (n *L3Node) ID() string {
+	return n.id
+}
+
+// TDN returns the distinguished name of the node in the fabric topology.
+func (n *L3Node) TDN() string {
+	return fmt.Sprintf("topology/pod-%s/node-%s", n.pod, n.id)
+}
+
+// RouterID returns the router id of the node.
+func (n *L3Node) RouterID() string {
+	return n.routerID
+}
+
+// SetRouterID validates and sets the router id of the node.
+//
+// A router id must be an IPv4 address, such as "1.1.1.101".
+func (n *L3Node) SetRouterID(routerID string) error {
+	if ip := net.ParseIP(routerID); ip == nil || ip.To4() == nil || ip.IsUnspecified() {
+		return fmt.Errorf("invalid router id: %s", routerID)
+	}
+	n.routerID = routerID
+	return nil
+}
+
+// RouterIDLoopback returns whether the router id is also
+// configured as a loopback address on the node.
+func (n *L3Node) RouterIDLoopback() bool {
+	return n.loopback
+}
+
+// SetRouterIDLoopback sets whether the router id is also
+// configured as a loopback address on the node.
+func (n *L3Node) SetRouterIDLoopback(loopback bool) {
+	n.loopback = loopback
+}
+
+// StaticRoutes returns the static routes of the node.
+func (n *L3Node) StaticRoutes() []*StaticRoute {
+	return n.staticRoutes
+}
+
+// AddStaticRoute adds a static route to the node,
+// replacing any route to the same prefix.
+func (n *L3Node) AddStaticRoute(route *StaticRoute) {
+	for i, r := range n.staticRoutes {
+		if r.Prefix() == route.Prefix() {
+			n.staticRoutes[i] = route
+			return
+		}
+	}
+	n.staticRoutes = append(n.staticRoutes, route)
+}
+
+// SetCreated sets the status of the node to "created,modified".
+func (n *L3Node) SetCreated() string {
+	n.status = createdModified
+	return n.status
+}
+
+// SetDeleted sets the status of the node to "deleted".
+func (n *L3Node) SetDeleted() string {
+	n.status = deleted
+	return n.status
+}
+
+// Status returns the status of the node.
+func (n *L3Node) Status() string {
+	return n.status
+}
+
+// String returns the string representation of an L3Out node
+func (n *L3Node) String() string {
+	return fmt.Sprintf("%s rtr-%s", n.TDN(), n.routerID)
+}
+
+// StaticRoute is a static route of a border leaf node.
+type StaticRoute struct {
+	prefix   string
+	nextHops []string
+	status   string
+}
+
+// Prefix returns the prefix of the route, in CIDR notation.
+func (r *StaticRoute) Prefix() string {
+	return r.prefix
+}
+
+// SetPrefix validates and sets the prefix of the route,
+// such as "0.0.0.0/0".
+func (r *StaticRoute) SetPrefix(prefix string) error {
+	if _, _, err := net.ParseCIDR(prefix); err != nil {
+		return fmt.Errorf("invalid prefix: %s", prefix)
+	}
+	r.prefix = prefix
+	return nil
+}
+
+// NextHops returns the next hop addresses of the route.
+func (r *StaticRoute) NextHops() []string {
+	return r.nextHops
+}
+
+// AddNextHop validates and adds a next hop address to the route.
+func (r *StaticRoute) AddNextHop(nextHop string) error {
+	if net.ParseIP(nextHop) == nil {
+		return fmt.Errorf("invalid next hop: %s", nextHop)
+	}
+	r.nextHops = appendName(r.nextHops, nextHop)
+	return nil
+}
+
+// SetCreated sets the status of the route to "created,modified".
+func (r *StaticRoute) SetCreated() string {
+	r.status = createdModified
+	return r.status
+}
+
+// SetDeleted sets the status of the route to "deleted".
+func (r *StaticRoute) SetDeleted() string {
+	r.status = deleted
+	return r.status
+}
+
+// Status returns the status of the route.
+func (r *StaticRoute) Status() string {
+	return r.status
+}
+
+// String returns the string representation of a static route
+func (r *StaticRoute) String() string {
+	return fmt.Sprintf("%s via %s", r.prefix, strings.Join(r.nextHops, ","))
+}
+
+// InterfaceProfile is a set of routed interfaces of an L3Out node profile.
+type InterfaceProfile struct {
+	object
+	interfaces []*L3Interface
+}
+
+// Interfaces returns the interfaces of the interface profile.
+func (ip *InterfaceProfile) Interfaces() []*L3Interface {
+	return ip.interfaces
+}
+
+// AddInterface adds an interface to the interface profile,
+// replacing any interface on the same path.
+func (ip *InterfaceProfile) AddInterface(iface *L3Interface) {
+	for i, f := range ip.interfaces {
+		if f.TDN() == iface.TDN() {
+			ip.interfaces[i] = iface
+			return
+		}
+	}
+	ip.interfaces = append(ip.interfaces, iface)
+}
+
+// Types of L3Out interface.
+const (
+	L3InterfaceRouted       = "l3-port"
+	L3InterfaceSubInterface = "sub-interface"
+	L3InterfaceSVI          = "ext-svi"
+)
+
+// L3Interface is a routed interface, routed sub-interface or SVI of an
+// L3Out, on an access port, port-channel or vPC.
+type L3Interface struct {
+	ifType   string
+	pathType string
+	pod      string
+	nodes    []string
+	port     string
+	address  string
+	addressB string
+	vlan     int
+	mtu      string
+	bgpPeers []*BGPPeer
+	status   string
+}
+
+// Type returns the type of the interface, one of L3InterfaceRouted,
+// L3InterfaceSubInterface or L3InterfaceSVI.
+func (i *L3Interface) Type() string {
+	return i.ifType
+}
+
+// PathType returns the type of the path of the interface, one of
+// PathTypePort, PathTypePortChannel or PathTypeVPC.
+func (i *L3Interface) PathType() string {
+	return i.pathType
+}
+
+// Pod returns the id of the pod of the interface.
+func (i *L3Interface) Pod() string {
+	return i.pod
+}
+
+// Nodes returns the ids of the nodes of the interface,
+// two for a vPC and one otherwise.
+func (i *L3Interface) Nodes() []string {
+	return i.nodes
+}
+
+// Port returns the port of the interface, such as "eth1/10",
+// or the interface policy group of a port-channel or vPC.
+func (i *L3Interface) Port() string {
+	return i.port
+}
+
+// TDN returns the distinguished name of the path of the interface
+// in the fabric topology.
+func (i *L3Interface) TDN() string {
+	return pathTDN(i.pathType, i.pod, i.nodes, i.port)
+}
+
+// Address returns the address of the interface in CIDR notation,
+// that of the first node of a vPC.
+func (i *L3Interface) Address() string {
+	return i.address
+}
+
+// AddressB returns the address of the second node of a vPC SVI.
+func (i *L3Interface) AddressB() string {
+	return i.addressB
+}
+
+// VLAN returns the VLAN of a sub-interface or SVI,
+// or 0 for a routed interface.
+func (i *L3Interface) VLAN() int {
+	return i.vlan
+}
+
+// MTU returns the MTU of the interface.
+func (i *L3Interface) MTU() string {
+	return i.mtu
+}
+
+// SetMTU validates and sets the MTU of the interface.
+//
+// An MTU must be between 576 and 9216 inclusive, or "inherit"
+// to use the MTU of the fabric.
+func (i *L3Interface) SetMTU(mtu string) error {
+	if mtu != "inherit" {
+		n, err := strconv.Atoi(mtu)
+		if err != nil || n < 576 || n > 9216 {
+			return fmt.Errorf("invalid mtu: %s", mtu)
+		}
+	}
+	i.mtu = mtu
+	return nil
+}
+
+// BGPPeers returns the BGP peers of the interface,
+// peered with from its address.
+func (i *L3Interface) BGPPeers() []*BGPPeer {
+	return i.bgpPeers
+}
+
+// AddBGPPeer adds a BGP peer to the interface,
+// replacing any peer with the same address.
+func (i *L3Interface) AddBGPPeer(peer *BGPPeer) {
+	i.bgpPeers = addBGPPeer(i.bgpPeers, peer)
+}
+
+// SetCreated sets the status of the interface to "created,modified".
+func (i *L3Interface) SetCreated() string {
+	i.status = createdModified
+	return i.status
+}
+
+// SetDeleted sets the status of the interface to "deleted".
+func (i *L3Interface) SetDeleted() string {
+	i.status = deleted
+	return i.status
+}
+
+// Status returns the status of the interface.
+func (i *L3Interface) Status() string {
+	return i.status
+}
+
+// String returns the string representation of an L3Out interface
+func (i *L3Interface) String() string {
+	s := fmt.Sprintf("%s %s %s", i.TDN(), i.ifType, i.address)
+	if i.vlan != 0 {
+		s += fmt.Sprintf(" vlan-%d", i.vlan)
+	}
+	return s
+}
+
+// validateInterfaceAddress validates the address of a routed interface,
+// an IPv4 or IPv6 address with a prefix length, such as "192.0.2.1/30".
+func validateInterfaceAddress(address string) error {
+	if _, _, err := net.ParseCIDR(address); err != nil {
+		return fmt.Errorf("invalid address: %s", address)
+	}
+	return nil
+}
+
+// BGPPeer is a BGP neighbour of an L3Out.
+type BGPPeer struct {
+	address  string
+	remoteAS uint32
+	status   string
+}
+
+// Address returns the address of the peer.
+func (p *BGPPeer) Address() string {
+	return p.address
+}
+
+// SetAddress validates and sets the address of the peer, an IPv4 or
+// IPv6 address, or a prefix to accept dynamic peers from.
+func (p *BGPPeer) SetAddress(address string) error {
+	if net.ParseIP(address) == nil {
+		if _, _, err := net.ParseCIDR(address); err != nil {
+			return fmt.Errorf("invalid peer address: %s", address)
+		}
+	}
+	p.address = address
+	return nil
+}
+
+// RemoteAS returns the autonomous system number of the peer.
+func (p *BGPPeer) RemoteAS() uint32 {
+	return p.remoteAS
+}
+
+// SetRemoteAS validates and sets the autonomous system number of the peer.
+//
+// An autonomous system number can not be 0.
+func (p *BGPPeer) SetRemoteAS(asn uint32) error {
+	if asn == 0 {
+		return fmt.Errorf("invalid asn: %d", asn)
+	}
+	p.remoteAS = asn
+	return nil
+}
+
+// SetCreated sets the status of the peer to "created,modified".
+func (p *BGPPeer) SetCreated() string {
+	p.status = createdModified
+	return p.status
+}
+
+// SetDeleted sets the status of the peer to "deleted".
+func (p *BGPPeer) SetDeleted() string {
+	p.status = deleted
+	return p.status
+}
+
+// Status returns the status of the peer.
+func (p *BGPPeer) Status() string {
+	return p.status
+}
+
+// String returns the string representation of a BGP peer
+func (p *BGPPeer) String() string {
+	return fmt.Sprintf("%s as%d", p.address, p.remoteAS)
+}
+
+// addBGPPeer adds a peer to peers, replacing any peer with the same address.
+func addBGPPeer(peers []*BGPPeer, peer *BGPPeer) []*BGPPeer {
+	for i, p := range peers {
+		if p.Address() == peer.Address() {
+			peers[i] = peer
+			return peers
+		}
+	}
+	return append(peers, peer)
+}
+
+// ExternalEPG is an external EPG of an L3Out, classifying external
+// traffic by subnet so that contracts can be applied to it.
+type ExternalEPG struct {
+	object
+	subnets  []*ExternalSubnet
+	provided []string
+	consumed []string
+}
+
+// Subnets returns the subnets of the external EPG.
+func (epg *ExternalEPG) Subnets() []*ExternalSubnet {
+	return epg.subnets
+}
+
+// AddSubnet adds a subnet to the external EPG,
+// replacing any subnet with the same prefix.
+func (epg *ExternalEPG) AddSubnet(subnet *ExternalSubnet) {
+	for i, sn := range epg.subnets {
+		if sn.Prefix() == subnet.Prefix() {
+			epg.subnets[i] = subnet
+			return
+		}
+	}
+	epg.subnets = append(epg.subnets, subnet)
+}
+
+// ProvidedContracts returns the names of the contracts the external EPG provides.
+func (epg *ExternalEPG) ProvidedContracts() []string {
+	return epg.provided
+}
+
+// ProvideContract validates and adds a contract for the external EPG to provide.
+func (epg *ExternalEPG) ProvideContract(contract string) error {
+	if err := validateName("contract name", contract); err != nil {
+		return err
+	}
+	epg.provided = appendName(epg.provided, contract)
+	return nil
+}
+
+// ConsumedContracts returns the names of the contracts the external EPG consumes.
+func (epg *ExternalEPG) ConsumedContracts() []string {
+	return epg.consumed
+}
+
+// ConsumeContract validates and adds a contract for the external EPG to consume.
+func (epg *ExternalEPG) ConsumeContract(contract string) error {
+	if err := validateName("contract name", contract); err != nil {
+		return err
+	}
+	epg.consumed = appendName(epg.consumed, contract)
+	return nil
+}
+
+// ExternalSubnet is a subnet of an external EPG.
+type ExternalSubnet struct {
+	prefix string
+	scope  []string
+	status string
+}
+
+// Prefix returns the prefix of the subnet, in CIDR notation.
+func (sn *ExternalSubnet) Prefix() string {
+	return sn.prefix
+}
+
+// SetPrefix validates and sets the prefix of the subnet,
+// such as "0.0.0.0/0" to match all external traffic.
+func (sn *ExternalSubnet) SetPrefix(prefix string) error {
+	if _, _, err := net.ParseCIDR(prefix); err != nil {
+		return fmt.Errorf("invalid prefix: %s", prefix)
+	}
+	sn.prefix = prefix
+	return nil
+}
+
+// Scope returns the scope of the subnet.
+func (sn *ExternalSubnet) Scope() []string {
+	return sn.scope
+}
+
+// SetScope validates and sets the scope of the subnet.
+//
+// A scope can only be any of "import-security" (classify traffic
+// into the external EPG), "shared-security", "import-rtctrl",
+// "export-rtctrl" and "shared-rtctrl", and defaults to "import-security".
+func (sn *ExternalSubnet) SetScope(scope ...string) error {
+	for _, s := range scope {
+		switch s {
+		case "import-security", "shared-security", "import-rtctrl", "export-rtctrl", "shared-rtctrl":
+		default:
+			return fmt.Errorf("invalid scope: %s", strings.Join(scope, ","))
+		}
+	}
+	if len(scope) == 0 {
+		scope = []string{"import-security"}
+	}
+	sn.scope = scope
+	return nil
+}
+
+// SetCreated sets the status of the subnet to "created,modified".
+func (sn *ExternalSubnet) SetCreated() string {
+	sn.status = createdModified
+	return sn.status
+}
+
+// SetDeleted sets the status of the subnet to "deleted".
+func (sn *ExternalSubnet) SetDeleted() string {
+	sn.status = deleted
+	return sn.status
+}
+
+// Status returns the status of the subnet.
+func (sn *ExternalSubnet) Status() string {
+	return sn.status
+}
+
+// String returns the string representation of an external subnet
+func (sn *ExternalSubnet) String() string {
+	return fmt.Sprintf("%s %s", sn.prefix, strings.Join(sn.scope, ","))
+}
@@ -0,0 +1,743 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// L3OutContainer is a container for an L3Out
+type L3OutContainer struct {
+	L3extOut L3OutObject `json:"l3extOut"`
+}
+
+// L3OutObject is any object of the L3Out subtree
+type L3OutObject struct {
+	L3OutAttrs `json:"attributes"`
+	Children   []L3OutChild `json:"children,omitempty"`
+}
+
+// L3OutChild is a child of an object in the L3Out subtree.
+// Only one of its fields is set.
+type L3OutChild struct {
+	RsEctx         *Relation    `json:"l3extRsEctx,omitempty"`
+	RsL3DomAtt     *Relation    `json:"l3extRsL3DomAtt,omitempty"`
+	BGPExtP        *L3OutObject `json:"bgpExtP,omitempty"`
+	OSPFExtP       *L3OutObject `json:"ospfExtP,omitempty"`
+	LNodeP         *L3OutObject `json:"l3extLNodeP,omitempty"`
+	RsNodeL3OutAtt *L3OutObject `json:"l3extRsNodeL3OutAtt,omitempty"`
+	RouteP         *L3OutObject `json:"ipRouteP,omitempty"`
+	NexthopP       *L3OutObject `json:"ipNexthopP,omitempty"`
+	LIfP           *L3OutObject `json:"l3extLIfP,omitempty"`
+	OSPFIfP        *L3OutObject `json:"ospfIfP,omitempty"`
+	RsPathL3OutAtt *L3OutObject `json:"l3extRsPathL3OutAtt,omitempty"`
+	Member         *L3OutObject `json:"l3extMember,omitempty"`
+	BGPPeerP       *L3OutObject `json:"bgpPeerP,omitempty"`
+	BGPAsP         *L3OutObject `json:"bgpAsP,omitempty"`
+	InstP          *L3OutObject `json:"l3extInstP,omitempty"`
+	Subnet         *L3OutObject `json:"l3extSubnet,omitempty"`
+	RsProv         *Relation    `json:"fvRsProv,omitempty"`
+	RsCons         *Relation    `json:"fvRsCons,omitempty"`
+}
+
+// L3OutAttrs contains the attributes of the L3Out subtree objects
+type L3OutAttrs struct {
+	Addr          string `json:"addr,omitempty"`
+	AreaID        string `json:"areaId,omitempty"`
+	AreaType      string `json:"areaType,omitempty"`
+	Asn           string `json:"asn,omitempty"`
+	Descr         string `json:"descr,omitempty"`
+	DN            string `json:"dn,omitempty"`
+	Encap         string `json:"encap,omitempty"`
+	IfInstT       string `json:"ifInstT,omitempty"`
+	IP            string `json:"ip,omitempty"`
+	Mtu           string `json:"mtu,omitempty"`
+	Name          string `json:"name,omitempty"`
+	NhAddr        string `json:"nhAddr,omitempty"`
+	RN            string `json:"rn,omitempty"`
+	RtrID         string `json:"rtrId,omitempty"`
+	RtrIDLoopBack string `json:"rtrIdLoopBack,omitempty"`
+	Scope         string `json:"scope,omitempty"`
+	Side          string `json:"side,omitempty"`
+	Status        string `json:"status,omitempty"`
+	TDN           string `json:"tDn,omitempty"`
+}
+
+// L3OutResponse contains the response for L3Out requests
+type L3OutResponse struct {
+	TotalCount string           `json:"totalCount"`
+	Imdata     []L3OutContainer `json:"imdata"`
+}
+
+// L3OutService handles communication with the L3Out related
+// methods of the APIC API.
+type L3OutService service
+
+// l3OutDN returns the distinguished name of an L3Out.
+func l3OutDN(tenant, l3Out string) string {
+	return fmt.Sprintf("%s/out-%s", tenantDN(tenant), l3Out)
+}
+
+// NewL3Out instantiates a valid L3Out within a tenant,
+// routing the given VRF through the given L3 domain.
+func (s *L3OutService) NewL3Out(tenant, name, vrf, domain string) (*L3Out, error) {
+	l3Out := &L3Out{}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return l3Out, err
+	}
+	l3Out.tenant = tenant
+
+	if err := l3Out.SetName(name); err != nil {
+		return l3Out, err
+	}
+
+	if err := l3Out.SetVRF(vrf); err != nil {
+		return l3Out, err
+	}
+
+	if err := l3Out.SetDomain(domain); err != nil {
+		return l3Out, err
+	}
+
+	return l3Out, nil
+}
+
+// NewNodeProfile instantiates a valid node profile.
+func (s *L3OutService) NewNodeProfile(name string) (*NodeProfile, error) {
+	np := &NodeProfile{}
+	if err := np.SetName(name); err != nil {
+		return np, err
+	}
+	return np, nil
+}
+
+// NewL3Node instantiates a valid border leaf node with the given router id,
+// which is also configured as a loopback address.
+func (s *L3OutService) NewL3Node(node *Node, routerID string) (*L3Node, error) {
+	n := &L3Node{loopback: true}
+	if node == nil || node.ID() == "" || node.Pod() == "" {
+		return n, fmt.Errorf("invalid l3out node: node must have an id and pod")
+	}
+	n.pod, n.id = node.Pod(), node.ID()
+	if err := n.SetRouterID(routerID); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// NewStaticRoute instantiates a valid static route to a prefix
+// through the given next hops.
+func (s *L3OutService) NewStaticRoute(prefix string, nextHops ...string) (*StaticRoute, error) {
+	r := &StaticRoute{}
+	if err := r.SetPrefix(prefix); err != nil {
+		return r, err
+	}
+	for _, nh := range nextHops {
+		if err := r.AddNextHop(nh); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// NewInterfaceProfile instantiates a valid interface profile.
+func (s *L3OutService) NewInterfaceProfile(name string) (*InterfaceProfile, error) {
+	ip := &InterfaceProfile{}
+	if err := ip.SetName(name); err != nil {
+		return ip, err
+	}
+	return ip, nil
+}
+
+// NewRoutedInterface instantiates a valid routed interface on an access
+// port of a node, such as "eth1/10".
+func (s *L3OutService) NewRoutedInterface(node *Node, port, address string) (*L3Interface, error) {
+	i := &L3Interface{ifType: L3InterfaceRouted, pathType: PathTypePort}
+	if !portPattern.MatchString(port) {
+		return i, fmt.Errorf("invalid port: %s", port)
+	}
+	i.port = port
+	return i, i.setPath(0, address, node)
+}
+
+// NewSubInterface instantiates a valid routed sub-interface on an access
+// port of a node, encapsulated in the given VLAN.
+func (s *L3OutService) NewSubInterface(node *Node, port string, vlan int, address string) (*L3Interface, error) {
+	i := &L3Interface{ifType: L3InterfaceSubInterface, pathType: PathTypePort}
+	if !portPattern.MatchString(port) {
+		return i, fmt.Errorf("invalid port: %s", port)
+	}
+	i.port = port
+	return i, i.setPath(vlan, address, node)
+}
+
+// NewSVI instantiates a valid SVI in the given VLAN on an access port of a
+// node, such as "eth1/10", or on a port-channel, identified by its
+// interface policy group.
+func (s *L3OutService) NewSVI(node *Node, port string, vlan int, address string) (*L3Interface, error) {
+	i := &L3Interface{ifType: L3InterfaceSVI, pathType: PathTypePort}
+	if !portPattern.MatchString(port) {
+		if err := validateName("policy group name", port); err != nil {
+			return i, err
+		}
+		i.pathType = PathTypePortChannel
+	}
+	i.port = port
+	return i, i.setPath(vlan, address, node)
+}
+
+// NewVPCSVI instantiates a valid SVI in the given VLAN on a vPC across a
+// pair of nodes, identified by its interface policy group, with an
+// address on each node.
+func (s *L3OutService) NewVPCSVI(a, b *Node, policyGroup string, vlan int, addressA, addressB string) (*L3Interface, error) {
+	i := &L3Interface{ifType: L3InterfaceSVI, pathType: PathTypeVPC}
+	if err := validateName("policy group name", policyGroup); err != nil {
+		return i, err
+	}
+	i.port = policyGroup
+	if err := validateInterfaceAddress(addressB); err != nil {
+		return i, err
+	}
+	// the addresses follow the nodes, which are ordered by node id
+	if a != nil && b != nil {
+		x, _ := strconv.Atoi(a.ID())
+		y, _ := strconv.Atoi(b.ID())
+		if x > y {
+			addressA, addressB = addressB, addressA
+		}
+	}
+	i.addressB = addressB
+	return i, i.setPath(vlan, addressA, a, b)
+}
+
+// setPath validates and sets the nodes, VLAN and address of an interface,
+// using a static path to validate the nodes and VLAN.
+func (i *L3Interface) setPath(vlan int, address string, nodes ...*Node) error {
+	p := &StaticPath{}
+	if i.ifType == L3InterfaceRouted {
+		// routed interfaces are untagged, so the VLAN only satisfies the path
+		vlan = 1
+	}
+	if err := p.setNodes(vlan, "regular", nodes...); err != nil {
+		return err
+	}
+	i.pod, i.nodes = p.Pod(), p.Nodes()
+	if i.ifType != L3InterfaceRouted {
+		i.vlan = p.VLAN()
+	}
+	if err := validateInterfaceAddress(address); err != nil {
+		return err
+	}
+	i.address = address
+	i.mtu = "inherit"
+	return nil
+}
+
+// NewBGPPeer instantiates a valid BGP peer with the given address
+// and autonomous system number.
+func (s *L3OutService) NewBGPPeer(address string, remoteAS uint32) (*BGPPeer, error) {
+	p := &BGPPeer{}
+	if err := p.SetAddress(address); err != nil {
+		return p, err
+	}
+	if err := p.SetRemoteAS(remoteAS); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewExternalEPG instantiates a valid external EPG.
+func (s *L3OutService) NewExternalEPG(name string) (*ExternalEPG, error) {
+	epg := &ExternalEPG{}
+	if err := epg.SetName(name); err != nil {
+		return epg, err
+	}
+	return epg, nil
+}
+
+// NewExternalSubnet instantiates a valid external EPG subnet with the
+// given scope, by default classifying traffic into the external EPG.
+func (s *L3OutService) NewExternalSubnet(prefix string, scope ...string) (*ExternalSubnet, error) {
+	sn := &ExternalSubnet{}
+	if err := sn.SetPrefix(prefix); err != nil {
+		return sn, err
+	}
+	if err := sn.SetScope(scope...); err != nil {
+		return sn, err
+	}
+	return sn, nil
+}
+
+func newL3OutContainer(l3Out *L3Out) L3OutContainer {
+	c := L3OutObject{
+		L3OutAttrs: L3OutAttrs{
+			DN:     l3OutDN(l3Out.Tenant(), l3Out.Name()),
+			Name:   l3Out.Name(),
+			Descr:  l3Out.Description(),
+			Status: l3Out.Status(),
+		},
+	}
+
+	// children are implicitly removed with their L3Out
+	if l3Out.Status() == deleted {
+		return L3OutContainer{L3extOut: c}
+	}
+
+	c.Children = append(c.Children,
+		L3OutChild{RsEctx: newRelation(RelationAttrs{TnFvCtxName: l3Out.VRF()})},
+		L3OutChild{RsL3DomAtt: newRelation(RelationAttrs{TDN: fmt.Sprintf("uni/l3dom-%s", l3Out.Domain())})},
+	)
+
+	// disabled protocols are deleted, in case they were enabled before
+	bgp := &L3OutObject{}
+	if !l3Out.BGP() {
+		bgp.Status = deleted
+	}
+	c.Children = append(c.Children, L3OutChild{BGPExtP: bgp})
+	area, areaType := l3Out.OSPF()
+	ospf := &L3OutObject{L3OutAttrs: L3OutAttrs{AreaID: area, AreaType: areaType}}
+	if area == "" {
+		ospf.Status = deleted
+	}
+	c.Children = append(c.Children, L3OutChild{OSPFExtP: ospf})
+
+	for _, np := range l3Out.NodeProfiles() {
+		c.Children = append(c.Children, L3OutChild{LNodeP: nodeProfileObject(np, area != "")})
+	}
+
+	for _, epg := range l3Out.ExternalEPGs() {
+		c.Children = append(c.Children, L3OutChild{InstP: externalEPGObject(epg)})
+	}
+
+	return L3OutContainer{L3extOut: c}
+}
+
+func nodeProfileObject(np *NodeProfile, ospf bool) *L3OutObject {
+	o := &L3OutObject{
+		L3OutAttrs: L3OutAttrs{
+			Name:   np.Name(),
+			Descr:  np.Description(),
+			Status: np.Status(),
+		},
+	}
+	if np.Status() == deleted {
+		return o
+	}
+
+	for _, n := range np.Nodes() {
+		node := &L3OutObject{
+			L3OutAttrs: L3OutAttrs{
+				TDN:           n.TDN(),
+				RtrID:         n.RouterID(),
+				RtrIDLoopBack: yesNo(n.RouterIDLoopback()),
+				Status:        n.Status(),
+			},
+		}
+		for _, r := range n.StaticRoutes() {
+			route := &L3OutObject{L3OutAttrs: L3OutAttrs{IP: r.Prefix(), Status: r.Status()}}
+			for _, nh := range r.NextHops() {
+				route.Children = append(route.Children, L3OutChild{
+					NexthopP: &L3OutObject{L3OutAttrs: L3OutAttrs{NhAddr: nh}},
+				})
+			}
+			node.Children = append(node.Children, L3OutChild{RouteP: route})
+		}
+		o.Children = append(o.Children, L3OutChild{RsNodeL3OutAtt: node})
+	}
+
+	for _, peer := range np.BGPPeers() {
+		o.Children = append(o.Children, L3OutChild{BGPPeerP: bgpPeerObject(peer)})
+	}
+
+	for _, ip := range np.InterfaceProfiles() {
+		lif := &L3OutObject{
+			L3OutAttrs: L3OutAttrs{
+				Name:   ip.Name(),
+				Descr:  ip.Description(),
+				Status: ip.Status(),
+			},
+		}
+		if ip.Status() != deleted {
+			// OSPF runs on the interfaces with the default interface policy,
+			// and is deleted from them when disabled, as it is from the L3Out
+			ospfIf := &L3OutObject{}
+			if !ospf {
+				ospfIf.Status = deleted
+			}
+			lif.Children = append(lif.Children, L3OutChild{OSPFIfP: ospfIf})
+			for _, iface := range ip.Interfaces() {
+				lif.Children = append(lif.Children, L3OutChild{RsPathL3OutAtt: interfaceObject(iface)})
+			}
+		}
+		o.Children = append(o.Children, L3OutChild{LIfP: lif})
+	}
+
+	return o
+}
+
+func interfaceObject(iface *L3Interface) *L3OutObject {
+	o := &L3OutObject{
+		L3OutAttrs: L3OutAttrs{
+			TDN:     iface.TDN(),
+			IfInstT: iface.Type(),
+			Mtu:     iface.MTU(),
+			Status:  iface.Status(),
+		},
+	}
+	if iface.VLAN() != 0 {
+		o.Encap = fmt.Sprintf("vlan-%d", iface.VLAN())
+	}
+	// a vPC SVI has an address on each side of the vPC
+	if iface.PathType() == PathTypeVPC {
+		o.Children = append(o.Children,
+			L3OutChild{Member: &L3OutObject{L3OutAttrs: L3OutAttrs{Side: "A", Addr: iface.Address()}}},
+			L3OutChild{Member: &L3OutObject{L3OutAttrs: L3OutAttrs{Side: "B", Addr: iface.AddressB()}}},
+		)
+	} else {
+		o.Addr = iface.Address()
+	}
+	for _, peer := range iface.BGPPeers() {
+		o.Children = append(o.Children, L3OutChild{BGPPeerP: bgpPeerObject(peer)})
+	}
+	return o
+}
+
+func bgpPeerObject(peer *BGPPeer) *L3OutObject {
+	o := &L3OutObject{L3OutAttrs: L3OutAttrs{Addr: peer.Address(), Status: peer.Status()}}
+	if peer.Status() != deleted {
+		o.Children = append(o.Children, L3OutChild{
+			BGPAsP: &L3OutObject{L3OutAttrs: L3OutAttrs{Asn: strconv.FormatUint(uint64(peer.RemoteAS()), 10)}},
+		})
+	}
+	return o
+}
+
+func externalEPGObject(epg *ExternalEPG) *L3OutObject {
+	o := &L3OutObject{
+		L3OutAttrs: L3OutAttrs{
+			Name:   epg.Name(),
+			Descr:  epg.Description(),
+			Status: epg.Status(),
+		},
+	}
+	if epg.Status() == deleted {
+		return o
+	}
+	for _, sn := range epg.Subnets() {
+		o.Children = append(o.Children, L3OutChild{
+			Subnet: &L3OutObject{
+				L3OutAttrs: L3OutAttrs{
+					IP:     sn.Prefix(),
+					Scope:  strings.Join(sn.Scope(), ","),
+					Status: sn.Status(),
+				},
+			},
+		})
+	}
+	for _, contract := range epg.ProvidedContracts() {
+		o.Children = append(o.Children, L3OutChild{
+			RsProv: newRelation(RelationAttrs{TnVzBrCPName: contract}),
+		})
+	}
+	for _, contract := range epg.ConsumedContracts() {
+		o.Children = append(o.Children, L3OutChild{
+			RsCons: newRelation(RelationAttrs{TnVzBrCPName: contract}),
+		})
+	}
+	return o
+}
+
+// Create creates an L3Out.
+func (s *L3OutService) Create(ctx context.Context, l3Out *L3Out) (L3OutResponse, error) {
+	l3Out.SetCreated()
+	return s.Update(ctx, l3Out)
+}
+
+// Delete deletes an L3Out.
+func (s *L3OutService) Delete(ctx context.Context, l3Out *L3Out) (L3OutResponse, error) {
+	l3Out.SetDeleted()
+	return s.Update(ctx, l3Out)
+}
+
+// Update creates, modifies or deletes an L3Out according to its status,
+// along with each of the objects within it according to theirs.
+//
+// The nodes of the L3Out must be registered fabric members, each
+// interface must be on nodes of its node profile, and router ids
+// must be unique, so that mistakes fail before they are posted.
+func (s *L3OutService) Update(ctx context.Context, l3Out *L3Out) (L3OutResponse, error) {
+	var lr L3OutResponse
+
+	if l3Out.Status() != deleted {
+		if err := s.check(ctx, l3Out); err != nil {
+			return lr, err
+		}
+	}
+
+	path := fmt.Sprintf("api/node/mo/%s.json", l3OutDN(l3Out.Tenant(), l3Out.Name()))
+	payload := newL3OutContainer(l3Out)
+
+	err := s.client.post(ctx, path, payload, &lr)
+	return lr, err
+}
+
+// check returns an error if any node or interface of the L3Out not
+// being deleted is on a node that is not a registered fabric member,
+// if an interface is on a node outside its node profile, or if a
+// router id is used by more than one node.
+func (s *L3OutService) check(ctx context.Context, l3Out *L3Out) error {
+	var nodes []*L3Node
+	var ifaces []*L3Interface
+	for _, np := range l3Out.NodeProfiles() {
+		if np.Status() == deleted {
+			continue
+		}
+		profile := make(map[string]bool)
+		for _, n := range np.Nodes() {
+			if n.Status() == deleted {
+				continue
+			}
+			profile[n.ID()] = true
+			nodes = append(nodes, n)
+		}
+		for _, ip := range np.InterfaceProfiles() {
+			if ip.Status() == deleted {
+				continue
+			}
+			for _, iface := range ip.Interfaces() {
+				if iface.Status() == deleted {
+					continue
+				}
+				for _, id := range iface.Nodes() {
+					if !profile[id] {
+						return fmt.Errorf("l3out interface %s: node %s is not in node profile %s", iface.TDN(), id, np.Name())
+					}
+				}
+				ifaces = append(ifaces, iface)
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	routerIDs := make(map[string]string)
+	for _, n := range nodes {
+		if id, ok := routerIDs[n.RouterID()]; ok && id != n.ID() {
+			return fmt.Errorf("l3out node %s: router id %s is used by node %s", n.ID(), n.RouterID(), id)
+		}
+		routerIDs[n.RouterID()] = n.ID()
+	}
+
+	members, err := s.client.FabricMembership.List(ctx)
+	if err != nil {
+		return fmt.Errorf("check l3out nodes: %v", err)
+	}
+	registered := make(map[string]*Node)
+	for _, node := range members {
+		registered[node.ID()] = node
+	}
+
+	for _, n := range nodes {
+		node, ok := registered[n.ID()]
+		if !ok {
+			return fmt.Errorf("l3out node %s is not registered", n.ID())
+		}
+		if node.Pod() != n.Pod() {
+			return fmt.Errorf("l3out node %s is in pod %s", n.ID(), node.Pod())
+		}
+	}
+	for _, iface := range ifaces {
+		for _, id := range iface.Nodes() {
+			if registered[id].Pod() != iface.Pod() {
+				return fmt.Errorf("l3out interface %s: node %s is in pod %s", iface.TDN(), id, registered[id].Pod())
+			}
+		}
+	}
+	return nil
+}
+
+// Get retrieves an L3Out of a tenant.
+func (s *L3OutService) Get(ctx context.Context, tenant, name string) (*L3Out, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", l3OutDN(tenant, name))
+
+	var lr L3OutResponse
+	if err := s.client.get(ctx, path, &lr); err != nil {
+		return nil, fmt.Errorf("get l3out: %v", err)
+	}
+	if len(lr.Imdata) == 0 {
+		return nil, fmt.Errorf("get l3out: %s/%s not found", tenant, name)
+	}
+	return l3OutFromResponse(tenant, lr.Imdata[0].L3extOut), nil
+}
+
+// List lists all L3Outs of a tenant.
+func (s *L3OutService) List(ctx context.Context, tenant string) ([]*L3Out, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=l3extOut&rsp-subtree=full", tenantDN(tenant))
+
+	var lr L3OutResponse
+	if err := s.client.get(ctx, path, &lr); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var l3Outs []*L3Out
+	for _, l := range lr.Imdata {
+		l3Outs = append(l3Outs, l3OutFromResponse(tenant, l.L3extOut))
+	}
+	return l3Outs, nil
+}
+
+func l3OutFromResponse(tenant string, o L3OutObject) *L3Out {
+	l3Out := &L3Out{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+		tenant: tenant,
+	}
+	for _, c := range o.Children {
+		switch {
+		case c.RsEctx != nil:
+			l3Out.vrf = c.RsEctx.TnFvCtxName
+		case c.RsL3DomAtt != nil:
+			l3Out.domain = strings.TrimPrefix(c.RsL3DomAtt.TDN, "uni/l3dom-")
+		case c.BGPExtP != nil:
+			l3Out.bgp = true
+		case c.OSPFExtP != nil:
+			l3Out.ospfArea, l3Out.ospfAreaType = c.OSPFExtP.AreaID, c.OSPFExtP.AreaType
+		case c.LNodeP != nil:
+			l3Out.nodeProfiles = append(l3Out.nodeProfiles, nodeProfileFromResponse(c.LNodeP))
+		case c.InstP != nil:
+			l3Out.externalEPGs = append(l3Out.externalEPGs, externalEPGFromResponse(c.InstP))
+		}
+	}
+	return l3Out
+}
+
+func nodeProfileFromResponse(o *L3OutObject) *NodeProfile {
+	np := &NodeProfile{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+	}
+	for _, c := range o.Children {
+		switch {
+		case c.RsNodeL3OutAtt != nil:
+			node := nodeFromTDN(c.RsNodeL3OutAtt.TDN)
+			n := &L3Node{
+				pod:      node.Pod(),
+				id:       node.ID(),
+				routerID: c.RsNodeL3OutAtt.RtrID,
+				loopback: c.RsNodeL3OutAtt.RtrIDLoopBack == "yes",
+				status:   c.RsNodeL3OutAtt.Status,
+			}
+			for _, rc := range c.RsNodeL3OutAtt.Children {
+				if rc.RouteP == nil {
+					continue
+				}
+				r := &StaticRoute{prefix: rc.RouteP.IP, status: rc.RouteP.Status}
+				for _, nc := range rc.RouteP.Children {
+					if nc.NexthopP != nil {
+						r.nextHops = appendName(r.nextHops, nc.NexthopP.NhAddr)
+					}
+				}
+				n.staticRoutes = append(n.staticRoutes, r)
+			}
+			np.nodes = append(np.nodes, n)
+		case c.BGPPeerP != nil:
+			np.bgpPeers = append(np.bgpPeers, bgpPeerFromResponse(c.BGPPeerP))
+		case c.LIfP != nil:
+			ip := &InterfaceProfile{
+				object: object{
+					name:        c.LIfP.Name,
+					description: c.LIfP.Descr,
+					status:      c.LIfP.Status,
+				},
+			}
+			for _, ic := range c.LIfP.Children {
+				if ic.RsPathL3OutAtt == nil {
+					continue
+				}
+				if iface := interfaceFromResponse(ic.RsPathL3OutAtt); iface != nil {
+					ip.interfaces = append(ip.interfaces, iface)
+				}
+			}
+			np.interfaceProfiles = append(np.interfaceProfiles, ip)
+		}
+	}
+	return np
+}
+
+// interfaceFromResponse returns an interface from its response,
+// or nil if it isn't on a path.
+func interfaceFromResponse(o *L3OutObject) *L3Interface {
+	pathType, pod, nodes, port, ok := parsePathTDN(o.TDN)
+	if !ok {
+		return nil
+	}
+	iface := &L3Interface{
+		ifType:   o.IfInstT,
+		pathType: pathType,
+		pod:      pod,
+		nodes:    nodes,
+		port:     port,
+		address:  o.Addr,
+		mtu:      o.Mtu,
+		status:   o.Status,
+	}
+	if strings.HasPrefix(o.Encap, "vlan-") {
+		iface.vlan, _ = strconv.Atoi(strings.TrimPrefix(o.Encap, "vlan-"))
+	}
+	for _, c := range o.Children {
+		switch {
+		case c.Member != nil && c.Member.Side == "A":
+			iface.address = c.Member.Addr
+		case c.Member != nil && c.Member.Side == "B":
+			iface.addressB = c.Member.Addr
+		case c.BGPPeerP != nil:
+			iface.bgpPeers = append(iface.bgpPeers, bgpPeerFromResponse(c.BGPPeerP))
+		}
+	}
+	return iface
+}
+
+func bgpPeerFromResponse(o *L3OutObject) *BGPPeer {
+	peer := &BGPPeer{address: o.Addr, status: o.Status}
+	for _, c := range o.Children {
+		if c.BGPAsP != nil {
+			asn, _ := strconv.ParseUint(c.BGPAsP.Asn, 10, 32)
+			peer.remoteAS = uint32(asn)
+		}
+	}
+	return peer
+}
+
+func externalEPGFromResponse(o *L3OutObject) *ExternalEPG {
+	epg := &ExternalEPG{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+	}
+	for _, c := range o.Children {
+		switch {
+		case c.Subnet != nil:
+			sn := &ExternalSubnet{prefix: c.Subnet.IP, status: c.Subnet.Status}
+			if c.Subnet.Scope != "" {
+				sn.scope = strings.Split(c.Subnet.Scope, ",")
+			}
+			epg.subnets = append(epg.subnets, sn)
+		case c.RsProv != nil:
+			epg.provided = appendName(epg.provided, c.RsProv.TnVzBrCPName)
+		case c.RsCons != nil:
+			epg.consumed = appendName(epg.consumed, c.RsCons.TnVzBrCPName)
+		}
+	}
+	return epg
+}
@@ -0,0 +1,292 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const l3OutPath = "/api/node/mo/uni/tn-t1/out-out1.json"
+
+// newTestL3Out returns an L3Out peering with BGP over OSPF area 1 from
+// leaves 101 and 102, with a routed interface on leaf 101 and an SVI on
+// the vPC between them.
+func newTestL3Out(t *testing.T, s *L3OutService) *L3Out {
+	t.Helper()
+	l3Out, err := s.NewL3Out("t1", "out1", "v1", "l3dom1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l3Out.SetBGP(true)
+	if err := l3Out.SetOSPF("1", "nssa"); err != nil {
+		t.Fatal(err)
+	}
+
+	leafA := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	leafB := newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf")
+	np, err := s.NewNodeProfile("border")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, leaf := range []*Node{leafA, leafB} {
+		n, err := s.NewL3Node(leaf, []string{"1.1.1.101", "1.1.1.102"}[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		np.AddNode(n)
+	}
+	route, err := s.NewStaticRoute("0.0.0.0/0", "192.0.2.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	np.Nodes()[0].AddStaticRoute(route)
+
+	ip, err := s.NewInterfaceProfile("uplinks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routed, err := s.NewRoutedInterface(leafA, "eth1/49", "192.0.2.1/30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := s.NewBGPPeer("192.0.2.2", 65001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routed.AddBGPPeer(peer)
+	ip.AddInterface(routed)
+	// given in reverse order, so the addresses swap with the nodes
+	svi, err := s.NewVPCSVI(leafB, leafA, "vpc-fw1", 100, "198.51.100.3/24", "198.51.100.2/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ip.AddInterface(svi)
+	np.AddInterfaceProfile(ip)
+	l3Out.AddNodeProfile(np)
+
+	epg, err := s.NewExternalEPG("all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sn, err := s.NewExternalSubnet("0.0.0.0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	epg.AddSubnet(sn)
+	if err := epg.ConsumeContract("web"); err != nil {
+		t.Fatal(err)
+	}
+	l3Out.AddExternalEPG(epg)
+	return l3Out
+}
+
+func TestL3OutUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, epgFixtures)
+	l3Out := newTestL3Out(t, c.L3Out)
+
+	if _, err := c.L3Out.Create(context.Background(), l3Out); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted(l3OutPath)
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"l3extOut":{"attributes":{"dn":"uni/tn-t1/out-out1","name":"out1","status":"created,modified"},"children":[
+		{"l3extRsEctx":{"attributes":{"tnFvCtxName":"v1"}}},
+		{"l3extRsL3DomAtt":{"attributes":{"tDn":"uni/l3dom-l3dom1"}}},
+		{"bgpExtP":{"attributes":{}}},
+		{"ospfExtP":{"attributes":{"areaId":"1","areaType":"nssa"}}},
+		{"l3extLNodeP":{"attributes":{"name":"border"},"children":[
+			{"l3extRsNodeL3OutAtt":{"attributes":{"rtrId":"1.1.1.101","rtrIdLoopBack":"yes","tDn":"topology/pod-1/node-101"},"children":[
+				{"ipRouteP":{"attributes":{"ip":"0.0.0.0/0"},"children":[{"ipNexthopP":{"attributes":{"nhAddr":"192.0.2.2"}}}]}}
+			]}},
+			{"l3extRsNodeL3OutAtt":{"attributes":{"rtrId":"1.1.1.102","rtrIdLoopBack":"yes","tDn":"topology/pod-1/node-102"}}},
+			{"l3extLIfP":{"attributes":{"name":"uplinks"},"children":[
+				{"ospfIfP":{"attributes":{}}},
+				{"l3extRsPathL3OutAtt":{"attributes":{"addr":"192.0.2.1/30","ifInstT":"l3-port","mtu":"inherit","tDn":"topology/pod-1/paths-101/pathep-[eth1/49]"},"children":[
+					{"bgpPeerP":{"attributes":{"addr":"192.0.2.2"},"children":[{"bgpAsP":{"attributes":{"asn":"65001"}}}]}}
+				]}},
+				{"l3extRsPathL3OutAtt":{"attributes":{"encap":"vlan-100","ifInstT":"ext-svi","mtu":"inherit","tDn":"topology/pod-1/protpaths-101-102/pathep-[vpc-fw1]"},"children":[
+					{"l3extMember":{"attributes":{"addr":"198.51.100.2/24","side":"A"}}},
+					{"l3extMember":{"attributes":{"addr":"198.51.100.3/24","side":"B"}}}
+				]}}
+			]}}
+		]}},
+		{"l3extInstP":{"attributes":{"name":"all"},"children":[
+			{"l3extSubnet":{"attributes":{"ip":"0.0.0.0/0","scope":"import-security"}}},
+			{"fvRsCons":{"attributes":{"tnVzBrCPName":"web"}}}
+		]}}
+	]}}`)
+
+	// disabling a protocol deletes it from the L3Out and its interfaces
+	l3Out.SetBGP(false)
+	l3Out.DisableOSPF()
+	if _, err := c.L3Out.Update(context.Background(), l3Out); err != nil {
+		t.Fatal(err)
+	}
+	posts = f.posted(l3OutPath)
+	body := posts[len(posts)-1]
+	for _, want := range []string{
+		`{"bgpExtP":{"attributes":{"status":"deleted"}}}`,
+		`{"ospfExtP":{"attributes":{"status":"deleted"}}}`,
+		`{"ospfIfP":{"attributes":{"status":"deleted"}}}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("posted %s, want it to contain %s", body, want)
+		}
+	}
+
+	// the children of a deleted L3Out are implicitly removed, unchecked
+	l3Out.NodeProfiles()[0].AddNode(&L3Node{pod: "1", id: "999", routerID: "1.1.1.99"})
+	if _, err := c.L3Out.Delete(context.Background(), l3Out); err != nil {
+		t.Fatal(err)
+	}
+	posts = f.posted(l3OutPath)
+	jsonEqual(t, posts[len(posts)-1], `{"l3extOut":{"attributes":{"dn":"uni/tn-t1/out-out1","name":"out1","status":"deleted"}}}`)
+}
+
+func TestL3OutCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, s *L3OutService, l3Out *L3Out)
+		want   string
+	}{
+		{
+			"interface outside node profile",
+			func(t *testing.T, s *L3OutService, l3Out *L3Out) {
+				iface, err := s.NewRoutedInterface(newTestNode(t, "leaf-201", "201", "2", "FDO3", "leaf"), "eth1/49", "192.0.2.5/30")
+				if err != nil {
+					t.Fatal(err)
+				}
+				l3Out.NodeProfiles()[0].InterfaceProfiles()[0].AddInterface(iface)
+			},
+			"node 201 is not in node profile border",
+		},
+		{
+			"duplicate router id",
+			func(t *testing.T, s *L3OutService, l3Out *L3Out) {
+				l3Out.NodeProfiles()[0].Nodes()[1].SetRouterID("1.1.1.101")
+			},
+			"router id 1.1.1.101 is used by node 101",
+		},
+		{
+			"unregistered node",
+			func(t *testing.T, s *L3OutService, l3Out *L3Out) {
+				n, err := s.NewL3Node(newTestNode(t, "leaf-103", "103", "1", "FDO4", "leaf"), "1.1.1.103")
+				if err != nil {
+					t.Fatal(err)
+				}
+				l3Out.NodeProfiles()[0].AddNode(n)
+			},
+			"l3out node 103 is not registered",
+		},
+		{
+			"wrong pod",
+			func(t *testing.T, s *L3OutService, l3Out *L3Out) {
+				n, err := s.NewL3Node(newTestNode(t, "leaf-201", "201", "1", "FDO3", "leaf"), "1.1.1.201")
+				if err != nil {
+					t.Fatal(err)
+				}
+				l3Out.NodeProfiles()[0].AddNode(n)
+			},
+			"l3out node 201 is in pod 2",
+		},
+	}
+	for _, tt := range tests {
+		f, c := newFakeAPIC(t, epgFixtures)
+		l3Out := newTestL3Out(t, c.L3Out)
+		tt.change(t, c.L3Out, l3Out)
+		_, err := c.L3Out.Update(context.Background(), l3Out)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %s", tt.name, err, tt.want)
+		}
+		if f.postCount() != 0 {
+			t.Errorf("%s: posted despite the error", tt.name)
+		}
+	}
+
+	// nodes and interfaces being deleted are not checked
+	_, c := newFakeAPIC(t, epgFixtures)
+	l3Out := newTestL3Out(t, c.L3Out)
+	n, err := c.L3Out.NewL3Node(newTestNode(t, "leaf-103", "103", "1", "FDO4", "leaf"), "1.1.1.101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.SetDeleted()
+	l3Out.NodeProfiles()[0].AddNode(n)
+	if _, err := c.L3Out.Update(context.Background(), l3Out); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestL3OutGet(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		l3OutPath: `{"imdata":[{"l3extOut":{"attributes":{"name":"out1","descr":"internet"},"children":[
+			{"l3extRsEctx":{"attributes":{"tnFvCtxName":"v1"}}},
+			{"l3extRsL3DomAtt":{"attributes":{"tDn":"uni/l3dom-l3dom1"}}},
+			{"bgpExtP":{"attributes":{}}},
+			{"l3extLNodeP":{"attributes":{"name":"border"},"children":[
+				{"l3extRsNodeL3OutAtt":{"attributes":{"rtrId":"1.1.1.101","rtrIdLoopBack":"yes","tDn":"topology/pod-1/node-101"},"children":[
+					{"ipRouteP":{"attributes":{"ip":"0.0.0.0/0"},"children":[{"ipNexthopP":{"attributes":{"nhAddr":"192.0.2.2"}}}]}}
+				]}},
+				{"bgpPeerP":{"attributes":{"addr":"10.0.0.1"},"children":[{"bgpAsP":{"attributes":{"asn":"65002"}}}]}},
+				{"l3extLIfP":{"attributes":{"name":"uplinks"},"children":[
+					{"ospfIfP":{"attributes":{}}},
+					{"l3extRsPathL3OutAtt":{"attributes":{"encap":"vlan-100","ifInstT":"ext-svi","tDn":"topology/pod-1/protpaths-101-102/pathep-[vpc-fw1]"},"children":[
+						{"l3extMember":{"attributes":{"addr":"198.51.100.2/24","side":"A"}}},
+						{"l3extMember":{"attributes":{"addr":"198.51.100.3/24","side":"B"}}}
+					]}},
+					{"l3extRsPathL3OutAtt":{"attributes":{"ifInstT":"l3-port","tDn":"topology/pod-1/node-101"}}}
+				]}}
+			]}},
+			{"l3extInstP":{"attributes":{"name":"all"},"children":[
+				{"l3extSubnet":{"attributes":{"ip":"0.0.0.0/0","scope":"import-security,shared-rtctrl"}}},
+				{"fvRsProv":{"attributes":{"tnVzBrCPName":"dns"}}}
+			]}}
+		]}}]}`,
+	})
+
+	l3Out, err := c.L3Out.Get(context.Background(), "t1", "out1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l3Out.String() != "t1/out1" || l3Out.Description() != "internet" || l3Out.VRF() != "v1" || l3Out.Domain() != "l3dom1" || !l3Out.BGP() {
+		t.Errorf("got %s %q vrf %s domain %s bgp %t", l3Out, l3Out.Description(), l3Out.VRF(), l3Out.Domain(), l3Out.BGP())
+	}
+	if area, _ := l3Out.OSPF(); area != "" {
+		t.Errorf("got ospf area %q, want none", area)
+	}
+
+	np := l3Out.NodeProfile("border")
+	if np == nil || len(np.Nodes()) != 1 || len(np.BGPPeers()) != 1 {
+		t.Fatalf("got node profile %+v", np)
+	}
+	if n := np.Nodes()[0]; n.TDN() != "topology/pod-1/node-101" || n.RouterID() != "1.1.1.101" || len(n.StaticRoutes()) != 1 {
+		t.Errorf("got node %s with routes %v", n, n.StaticRoutes())
+	}
+	if peer := np.BGPPeers()[0]; peer.Address() != "10.0.0.1" || peer.RemoteAS() != 65002 {
+		t.Errorf("got peer %s", peer)
+	}
+
+	// the interface that isn't on a path is skipped
+	ifaces := np.InterfaceProfiles()[0].Interfaces()
+	if len(ifaces) != 1 {
+		t.Fatalf("got %d interfaces, want 1", len(ifaces))
+	}
+	iface := ifaces[0]
+	if iface.PathType() != PathTypeVPC || iface.VLAN() != 100 || iface.Address() != "198.51.100.2/24" || iface.AddressB() != "198.51.100.3/24" {
+		t.Errorf("got interface %s b %s", iface, iface.AddressB())
+	}
+	if want := []string{"101", "102"}; !reflect.DeepEqual(iface.Nodes(), want) {
+		t.Errorf("got nodes %v, want %v", iface.Nodes(), want)
+	}
+
+	epg := l3Out.ExternalEPG("all")
+	if epg == nil || len(epg.Subnets()) != 1 || !reflect.DeepEqual(epg.ProvidedContracts(), []string{"dns"}) {
+		t.Fatalf("got external epg %+v", epg)
+	}
+	if want := []string{"import-security", "shared-rtctrl"}; !reflect.DeepEqual(epg.Subnets()[0].Scope(), want) {
+		t.Errorf("got scope %v, want %v", epg.Subnets()[0].Scope(), want)
+	}
+}
@@ -14,6 +14,9 @@ type Node struct {
 	serial string
 	role   string
 	status string
+
+	// fabricState is the state of the node as reported by the APIC.
+	fabricState string
 }
 
 // ID returns the node ID
@@ -113,6 +116,12 @@ func (n *Node) Status() string {
 	return n.status
 }
 
+// FabricState returns the state of the node in the fabric,
+// such as "active" or "inactive", as last reported by the APIC.
+func (n *Node) FabricState() string {
+	return n.fabricState
+}
+
 // String returns the string representation of an ACI node
 func (n *Node) String() string {
 	return fmt.Sprintf("%s %s %s", n.name, n.id, n.serial)
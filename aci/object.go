@@ -0,0 +1,110 @@
+package aci
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+)
+
+// object holds the attributes common to named ACI objects, such as
+// tenants and the objects within them, and geolocation sites.
+type object struct {
+	name        string
+	description string
+	status      string
+}
+
+// SetName validates and sets the name of an object.
+//
+// A name can be up to 64 characters and must begin with an
+// alphanumeric character. It can only contain alphanumeric
+// characters and the following symbols: -.:_
+func (o *object) SetName(name string) error {
+	if err := validateName("name", name); err != nil {
+		return err
+	}
+	o.name = name
+	return nil
+}
+
+// validateName validates the name of an object, or of an object
+// referred to by a relation, following the same rules as SetName.
+// The kind of name is used to describe the error.
+func validateName(kind, name string) error {
+	valid := regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-.:_]{0,63}$`)
+	if !valid.MatchString(name) {
+		return fmt.Errorf("invalid %s: %s", kind, name)
+	}
+	return nil
+}
+
+// validateHost validates a hostname or IP address, such as that of
+// a server. The kind of host is used to describe the error.
+func validateHost(kind, host string) error {
+	valid := regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,62}\.?)*$`)
+	if net.ParseIP(host) == nil && (!valid.MatchString(host) || len(host) > 253) {
+		return fmt.Errorf("invalid %s: %s", kind, host)
+	}
+	return nil
+}
+
+// appendName appends a name to names, unless it is already present.
+func appendName(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+// removeName returns names without name.
+func removeName(names []string, name string) []string {
+	for i, n := range names {
+		if n == name {
+			return append(names[:i:i], names[i+1:]...)
+		}
+	}
+	return names
+}
+
+// Name returns the name of an object.
+func (o *object) Name() string {
+	return o.name
+}
+
+// SetDescription validates and sets the description of an object.
+//
+// A description can be up to 128 characters. It can only
+// contain alphanumeric characters and the following symbols:
+// !#$%()*,-.:;@_{|}~?&+
+func (o *object) SetDescription(description string) error {
+	valid := regexp.MustCompile(`^[a-zA-Z0-9!#$%()*,-.:;@_{|}~?&+\s]{0,128}$`)
+	if !valid.MatchString(description) {
+		return fmt.Errorf("invalid description: %s", description)
+	}
+	o.description = description
+	return nil
+}
+
+// Description returns the description of an object.
+func (o *object) Description() string {
+	return o.description
+}
+
+// SetCreated sets the status of the object to "created,modified".
+func (o *object) SetCreated() string {
+	o.status = createdModified
+	return o.status
+}
+
+// SetDeleted sets the status of the object to "deleted".
+func (o *object) SetDeleted() string {
+	o.status = deleted
+	return o.status
+}
+
+// Status returns the status of the object.
+func (o *object) Status() string {
+	return o.status
+}
@@ -0,0 +1,239 @@
+package aci
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+)
+
+// Pod is an ACI fabric pod, described by its pod setup policy.
+type Pod struct {
+	id      string
+	tepPool string
+	podType string
+	status  string
+
+	// discovered is whether the APIC has a fabricPod object for the pod.
+	discovered bool
+}
+
+// ID returns the pod id.
+func (p *Pod) ID() string {
+	return p.id
+}
+
+// SetID validates and sets the pod id.
+//
+// A pod id must be a number between 1 and 255.
+func (p *Pod) SetID(id string) error {
+	idN, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid pod id: %s", id)
+	}
+	if idN < 1 || idN > 255 {
+		return fmt.Errorf("invalid pod id: %s", id)
+	}
+	p.id = id
+	return nil
+}
+
+// TEPPool returns the TEP address pool of the pod.
+func (p *Pod) TEPPool() string {
+	return p.tepPool
+}
+
+// SetTEPPool validates and sets the TEP address pool of the pod.
+//
+// A TEP pool must be an IPv4 network in CIDR notation, such as
+// "10.0.0.0/16", with a prefix length between 16 and 23.
+func (p *Pod) SetTEPPool(pool string) error {
+	ip, ipNet, err := net.ParseCIDR(pool)
+	if err != nil || ip.To4() == nil || !ip.Equal(ipNet.IP) {
+		return fmt.Errorf("invalid tep pool: %s", pool)
+	}
+	if ones, _ := ipNet.Mask.Size(); ones < 16 || ones > 23 {
+		return fmt.Errorf("invalid tep pool: %s", pool)
+	}
+	p.tepPool = pool
+	return nil
+}
+
+// Type returns the type of the pod.
+func (p *Pod) Type() string {
+	return p.podType
+}
+
+// SetType sets the type of the pod.
+// Can only be "physical" or "virtual"
+func (p *Pod) SetType(podType string) error {
+	if podType != "physical" && podType != "virtual" {
+		return fmt.Errorf("invalid pod type: %s", podType)
+	}
+	p.podType = podType
+	return nil
+}
+
+// Discovered returns whether the pod has been discovered by the fabric.
+func (p *Pod) Discovered() bool {
+	return p.discovered
+}
+
+// SetCreated sets the status of the pod to "created,modified".
+func (p *Pod) SetCreated() string {
+	p.status = createdModified
+	return p.status
+}
+
+// SetDeleted sets the status of the pod to "deleted".
+func (p *Pod) SetDeleted() string {
+	p.status = deleted
+	return p.status
+}
+
+// Status returns the status of the pod.
+func (p *Pod) Status() string {
+	return p.status
+}
+
+// String returns the string representation of a pod
+func (p *Pod) String() string {
+	return fmt.Sprintf("pod-%s %s", p.id, p.tepPool)
+}
+
+// PodConnection is the multipod connection of a single pod,
+// with the data plane TEP it is reachable on from the IPN.
+type PodConnection struct {
+	Pod          string
+	DataPlaneTEP string
+}
+
+// Multipod describes the multipod IPN settings of the fabric,
+// the fabric external connection policy in the infra tenant.
+type Multipod struct {
+	id          string
+	community   string
+	peeringType string
+	pods        []PodConnection
+	subnets     []string
+	status      string
+}
+
+// ID returns the id of the fabric external connection policy.
+func (m *Multipod) ID() string {
+	return m.id
+}
+
+// Community returns the BGP extended community of the fabric.
+func (m *Multipod) Community() string {
+	return m.community
+}
+
+// SetCommunity validates and sets the BGP extended community of the fabric.
+//
+// A community must be of the form "extended:as2-nn4:<asn>:<nn>".
+func (m *Multipod) SetCommunity(community string) error {
+	valid := regexp.MustCompile(`^extended:as2-nn4:[0-9]{1,5}:[0-9]{1,10}$`)
+	if !valid.MatchString(community) {
+		return fmt.Errorf("invalid community: %s", community)
+	}
+	m.community = community
+	return nil
+}
+
+// PeeringType returns the spine BGP peering type across pods.
+func (m *Multipod) PeeringType() string {
+	return m.peeringType
+}
+
+// SetPeeringType sets the spine BGP peering type across pods.
+// Can only be "automatic_with_full_mesh" or "automatic_with_rr"
+func (m *Multipod) SetPeeringType(peeringType string) error {
+	if peeringType != "automatic_with_full_mesh" && peeringType != "automatic_with_rr" {
+		return fmt.Errorf("invalid peering type: %s", peeringType)
+	}
+	m.peeringType = peeringType
+	return nil
+}
+
+// Pods returns the pod connections of the fabric.
+func (m *Multipod) Pods() []PodConnection {
+	return m.pods
+}
+
+// AddPod validates and adds the data plane TEP of a pod.
+//
+// The data plane TEP must be an IPv4 address, optionally with
+// a /32 prefix length.
+func (m *Multipod) AddPod(pod, dataPlaneTEP string) error {
+	var p Pod
+	if err := p.SetID(pod); err != nil {
+		return err
+	}
+	ip := net.ParseIP(dataPlaneTEP)
+	if ip == nil {
+		var ipNet *net.IPNet
+		var err error
+		ip, ipNet, err = net.ParseCIDR(dataPlaneTEP)
+		if err != nil {
+			return fmt.Errorf("invalid data plane tep: %s", dataPlaneTEP)
+		}
+		if ones, _ := ipNet.Mask.Size(); ones != 32 {
+			return fmt.Errorf("invalid data plane tep: %s", dataPlaneTEP)
+		}
+	}
+	if ip.To4() == nil {
+		return fmt.Errorf("invalid data plane tep: %s", dataPlaneTEP)
+	}
+	if ip.String() == dataPlaneTEP {
+		dataPlaneTEP += "/32"
+	}
+	for i, pc := range m.pods {
+		if pc.Pod == pod {
+			m.pods[i].DataPlaneTEP = dataPlaneTEP
+			return nil
+		}
+	}
+	m.pods = append(m.pods, PodConnection{Pod: pod, DataPlaneTEP: dataPlaneTEP})
+	return nil
+}
+
+// Subnets returns the fabric external routing subnets,
+// the IPN subnets used between the spines and the IPN.
+func (m *Multipod) Subnets() []string {
+	return m.subnets
+}
+
+// AddSubnet validates and adds a fabric external routing subnet.
+//
+// A subnet must be an IPv4 network in CIDR notation.
+func (m *Multipod) AddSubnet(subnet string) error {
+	ip, _, err := net.ParseCIDR(subnet)
+	if err != nil || ip.To4() == nil {
+		return fmt.Errorf("invalid subnet: %s", subnet)
+	}
+	for _, s := range m.subnets {
+		if s == subnet {
+			return nil
+		}
+	}
+	m.subnets = append(m.subnets, subnet)
+	return nil
+}
+
+// SetCreated sets the status of the multipod settings to "created,modified".
+func (m *Multipod) SetCreated() string {
+	m.status = createdModified
+	return m.status
+}
+
+// SetDeleted sets the status of the multipod settings to "deleted".
+func (m *Multipod) SetDeleted() string {
+	m.status = deleted
+	return m.status
+}
+
+// Status returns the status of the multipod settings.
+func (m *Multipod) Status() string {
+	return m.status
+}
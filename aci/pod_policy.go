@@ -0,0 +1,328 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// SetupPolContainer is a container for the pod setup policies
+type SetupPolContainer struct {
+	SetupPol `json:"fabricSetupPol"`
+}
+
+// SetupPol is the parent of all pod setup policies
+type SetupPol struct {
+	PodAttrs `json:"attributes"`
+	Children []SetupPContainer `json:"children,omitempty"`
+}
+
+// SetupPContainer is a container for a pod setup policy
+type SetupPContainer struct {
+	SetupP `json:"fabricSetupP"`
+}
+
+// SetupP is the setup policy of a single pod
+type SetupP struct {
+	PodAttrs `json:"attributes"`
+}
+
+// FabricPodContainer is a container for a discovered fabric pod
+type FabricPodContainer struct {
+	FabricPod `json:"fabricPod"`
+}
+
+// FabricPod is a pod discovered by the fabric
+type FabricPod struct {
+	PodAttrs `json:"attributes"`
+}
+
+// PodAttrs contains the attributes of the pod policy objects
+type PodAttrs struct {
+	DN      string `json:"dn,omitempty"`
+	ID      string `json:"id,omitempty"`
+	PodID   string `json:"podId,omitempty"`
+	PodType string `json:"podType,omitempty"`
+	RN      string `json:"rn,omitempty"`
+	Status  string `json:"status,omitempty"`
+	TEPPool string `json:"tepPool,omitempty"`
+}
+
+// PodsResponse contains the response for pod setup policy requests
+type PodsResponse struct {
+	Imdata []SetupPContainer `json:"imdata"`
+}
+
+// FabricExtConnPContainer is a container for the fabric external
+// connection policy, which holds the multipod IPN settings
+type FabricExtConnPContainer struct {
+	FabricExtConnP MultipodObject `json:"fvFabricExtConnP"`
+}
+
+// MultipodObject is any object of the fabric external connection policy subtree
+type MultipodObject struct {
+	MultipodAttrs `json:"attributes"`
+	Children      []MultipodChild `json:"children,omitempty"`
+}
+
+// MultipodChild is a child of an object in the fabric external connection
+// policy subtree. Only one of its fields is set.
+type MultipodChild struct {
+	PodConnP    *MultipodObject `json:"fvPodConnP,omitempty"`
+	IP          *MultipodObject `json:"fvIp,omitempty"`
+	PeeringP    *MultipodObject `json:"fvPeeringP,omitempty"`
+	ExtRoutingP *MultipodObject `json:"l3extFabricExtRoutingP,omitempty"`
+	Subnet      *MultipodObject `json:"l3extSubnet,omitempty"`
+}
+
+// MultipodAttrs contains the attributes of the fabric external
+// connection policy objects
+type MultipodAttrs struct {
+	Addr   string `json:"addr,omitempty"`
+	DN     string `json:"dn,omitempty"`
+	ID     string `json:"id,omitempty"`
+	IP     string `json:"ip,omitempty"`
+	Name   string `json:"name,omitempty"`
+	RN     string `json:"rn,omitempty"`
+	Rt     string `json:"rt,omitempty"`
+	SiteID string `json:"siteId,omitempty"`
+	Status string `json:"status,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+// MultipodResponse contains the response for multipod settings requests
+type MultipodResponse struct {
+	Imdata []FabricExtConnPContainer `json:"imdata"`
+}
+
+// PodService handles communication with the pod and multipod related
+// methods of the APIC API.
+type PodService service
+
+// NewPod instantiates a valid physical pod with the given TEP pool.
+func (s *PodService) NewPod(id, tepPool string) (*Pod, error) {
+	pod := &Pod{podType: "physical"}
+
+	if err := pod.SetID(id); err != nil {
+		return pod, err
+	}
+
+	if err := pod.SetTEPPool(tepPool); err != nil {
+		return pod, err
+	}
+
+	return pod, nil
+}
+
+// Update creates or deletes pod setup policies according to each pod's status.
+func (s *PodService) Update(ctx context.Context, pods ...*Pod) (PodsResponse, error) {
+
+	path := "api/node/mo/uni/controller/setuppol.json"
+	payload := newSetupPolContainer(pods)
+
+	var pr PodsResponse
+	err := s.client.post(ctx, path, payload, &pr)
+	return pr, err
+}
+
+func newSetupPolContainer(pods []*Pod) SetupPolContainer {
+
+	var children []SetupPContainer
+
+	for _, pod := range pods {
+		rn := fmt.Sprintf("setupp-%s", pod.ID())
+		children = append(children, SetupPContainer{
+			SetupP: SetupP{
+				PodAttrs: PodAttrs{
+					DN:      fmt.Sprintf("uni/controller/setuppol/%s", rn),
+					RN:      rn,
+					PodID:   pod.ID(),
+					PodType: pod.Type(),
+					TEPPool: pod.TEPPool(),
+					Status:  pod.Status(),
+				},
+			},
+		})
+	}
+
+	return SetupPolContainer{
+		SetupPol: SetupPol{
+			PodAttrs: PodAttrs{
+				Status: createdModified,
+			},
+			Children: children,
+		},
+	}
+}
+
+// List lists all pods of the ACI fabric, both those with a pod setup
+// policy and those discovered by the fabric, ordered by pod id.
+func (s *PodService) List(ctx context.Context) ([]*Pod, error) {
+
+	pods := make(map[string]*Pod)
+
+	var pr PodsResponse
+	if err := s.client.get(ctx, "api/node/class/fabricSetupP.json", &pr); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+	for _, p := range pr.Imdata {
+		pods[p.PodID] = &Pod{
+			id:      p.PodID,
+			tepPool: p.TEPPool,
+			podType: p.PodType,
+			status:  p.Status,
+		}
+	}
+
+	// structure of expected response
+	var fr struct {
+		Imdata []FabricPodContainer `json:"imdata"`
+	}
+	if err := s.client.get(ctx, "api/node/class/fabricPod.json", &fr); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+	for _, p := range fr.Imdata {
+		pod, ok := pods[p.ID]
+		if !ok {
+			pod = &Pod{id: p.ID, podType: p.PodType}
+			pods[p.ID] = pod
+		}
+		pod.discovered = true
+	}
+
+	var ps []*Pod
+	for _, pod := range pods {
+		ps = append(ps, pod)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		a, _ := strconv.Atoi(ps[i].id)
+		b, _ := strconv.Atoi(ps[j].id)
+		return a < b
+	})
+	return ps, nil
+}
+
+// Exists reports whether a pod with the given id exists in the ACI fabric.
+func (s *PodService) Exists(ctx context.Context, id string) (bool, error) {
+	pods, err := s.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		if pod.ID() == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// multipodPath is the path of the fabric external connection policy.
+const multipodPath = "api/node/mo/uni/tn-infra/fabricExtConnP-1.json"
+
+// NewMultipod instantiates valid multipod settings with the given
+// BGP extended community and full mesh spine peering.
+func (s *PodService) NewMultipod(community string) (*Multipod, error) {
+	m := &Multipod{id: "1", peeringType: "automatic_with_full_mesh"}
+
+	if err := m.SetCommunity(community); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// UpdateMultipod creates, modifies or deletes the multipod IPN settings
+// according to their status.
+func (s *PodService) UpdateMultipod(ctx context.Context, m *Multipod) (MultipodResponse, error) {
+
+	payload := newFabricExtConnPContainer(m)
+
+	var mr MultipodResponse
+	err := s.client.post(ctx, multipodPath, payload, &mr)
+	return mr, err
+}
+
+func newFabricExtConnPContainer(m *Multipod) FabricExtConnPContainer {
+	conn := MultipodObject{
+		MultipodAttrs: MultipodAttrs{
+			DN:     fmt.Sprintf("uni/tn-infra/fabricExtConnP-%s", m.ID()),
+			ID:     m.ID(),
+			Rt:     m.Community(),
+			SiteID: "0",
+			Status: m.Status(),
+		},
+	}
+
+	if m.Status() == deleted {
+		return FabricExtConnPContainer{FabricExtConnP: conn}
+	}
+
+	for _, pc := range m.Pods() {
+		conn.Children = append(conn.Children, MultipodChild{
+			PodConnP: &MultipodObject{
+				MultipodAttrs: MultipodAttrs{ID: pc.Pod},
+				Children: []MultipodChild{
+					{IP: &MultipodObject{MultipodAttrs: MultipodAttrs{Addr: pc.DataPlaneTEP}}},
+				},
+			},
+		})
+	}
+
+	conn.Children = append(conn.Children, MultipodChild{
+		PeeringP: &MultipodObject{MultipodAttrs: MultipodAttrs{Type: m.PeeringType()}},
+	})
+
+	if len(m.Subnets()) > 0 {
+		routing := &MultipodObject{MultipodAttrs: MultipodAttrs{Name: "default"}}
+		for _, subnet := range m.Subnets() {
+			routing.Children = append(routing.Children, MultipodChild{
+				Subnet: &MultipodObject{MultipodAttrs: MultipodAttrs{IP: subnet}},
+			})
+		}
+		conn.Children = append(conn.Children, MultipodChild{ExtRoutingP: routing})
+	}
+
+	return FabricExtConnPContainer{FabricExtConnP: conn}
+}
+
+// GetMultipod returns the current multipod IPN settings of the fabric,
+// or nil if none are configured.
+func (s *PodService) GetMultipod(ctx context.Context) (*Multipod, error) {
+
+	var mr MultipodResponse
+	if err := s.client.get(ctx, multipodPath+"?rsp-subtree=full", &mr); err != nil {
+		return nil, fmt.Errorf("get multipod: %v", err)
+	}
+	if len(mr.Imdata) == 0 {
+		return nil, nil
+	}
+
+	conn := mr.Imdata[0].FabricExtConnP
+	m := &Multipod{
+		id:        conn.ID,
+		community: conn.Rt,
+		status:    conn.Status,
+	}
+	for _, child := range conn.Children {
+		switch {
+		case child.PodConnP != nil:
+			pc := PodConnection{Pod: child.PodConnP.ID}
+			for _, ip := range child.PodConnP.Children {
+				if ip.IP != nil {
+					pc.DataPlaneTEP = ip.IP.Addr
+				}
+			}
+			m.pods = append(m.pods, pc)
+		case child.PeeringP != nil:
+			m.peeringType = child.PeeringP.Type
+		case child.ExtRoutingP != nil:
+			for _, subnet := range child.ExtRoutingP.Children {
+				if subnet.Subnet != nil {
+					m.subnets = append(m.subnets, subnet.Subnet.IP)
+				}
+			}
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,138 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// podFixtures has pod 1 set up and discovered, pod 2 only set up
+// and pod 3 only discovered.
+var podFixtures = map[string]string{
+	"/api/node/class/fabricSetupP.json": `{"imdata":[
+		{"fabricSetupP":{"attributes":{"podId":"2","podType":"physical","tepPool":"10.2.0.0/16"}}},
+		{"fabricSetupP":{"attributes":{"podId":"1","podType":"physical","tepPool":"10.1.0.0/16"}}}
+	]}`,
+	"/api/node/class/fabricPod.json": `{"imdata":[
+		{"fabricPod":{"attributes":{"id":"1","podType":"physical"}}},
+		{"fabricPod":{"attributes":{"id":"3","podType":"physical"}}}
+	]}`,
+}
+
+func TestPodList(t *testing.T) {
+	_, c := newFakeAPIC(t, podFixtures)
+
+	pods, err := c.Pod.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range pods {
+		got = append(got, p.ID()+" "+p.TEPPool()+" "+map[bool]string{true: "discovered", false: "undiscovered"}[p.Discovered()])
+	}
+	want := []string{"1 10.1.0.0/16 discovered", "2 10.2.0.0/16 undiscovered", "3  discovered"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got pods %q, want %q", got, want)
+	}
+
+	for id, want := range map[string]bool{"2": true, "4": false} {
+		exists, err := c.Pod.Exists(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != want {
+			t.Errorf("pod %s: got exists %t, want %t", id, exists, want)
+		}
+	}
+}
+
+func TestPodUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	pod, err := c.Pod.NewPod("2", "10.2.0.0/16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod.SetCreated()
+	if _, err := c.Pod.Update(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted("/api/node/mo/uni/controller/setuppol.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"fabricSetupPol":{"attributes":{"status":"created,modified"},"children":[
+		{"fabricSetupP":{"attributes":{"dn":"uni/controller/setuppol/setupp-2","rn":"setupp-2","podId":"2","podType":"physical","tepPool":"10.2.0.0/16","status":"created,modified"}}}
+	]}}`)
+}
+
+func TestNewPod(t *testing.T) {
+	tests := []struct {
+		id, pool string
+		valid    bool
+	}{
+		{"1", "10.0.0.0/16", true},
+		{"255", "10.0.0.0/23", true},
+		{"0", "10.0.0.0/16", false},
+		{"256", "10.0.0.0/16", false},
+		{"1", "10.0.0.0/24", false},
+		{"1", "10.0.0.0/15", false},
+		{"1", "10.0.0.1", false},
+	}
+	s := &PodService{}
+	for _, tt := range tests {
+		_, err := s.NewPod(tt.id, tt.pool)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("pod %s %s: got valid %t, want %t (%v)", tt.id, tt.pool, valid, tt.valid, err)
+		}
+	}
+}
+
+func TestMultipod(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	m, err := c.Pod.NewMultipod("extended:as2-nn4:5:16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddPod("1", "10.1.0.1/32"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddPod("2", "10.2.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddSubnet("192.168.0.0/30"); err != nil {
+		t.Fatal(err)
+	}
+	m.SetCreated()
+	if _, err := c.Pod.UpdateMultipod(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := f.posted("/api/node/mo/uni/tn-infra/fabricExtConnP-1.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+
+	// reading back what was posted gives the same settings
+	f.setFixture("/api/node/mo/uni/tn-infra/fabricExtConnP-1.json", `{"imdata":[`+posts[0]+`]}`)
+	got, err := c.Pod.GetMultipod(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID() != "1" || got.Community() != m.Community() || got.PeeringType() != m.PeeringType() {
+		t.Errorf("got multipod %s %s %s", got.ID(), got.Community(), got.PeeringType())
+	}
+	if !reflect.DeepEqual(got.Pods(), m.Pods()) {
+		t.Errorf("got pods %v, want %v", got.Pods(), m.Pods())
+	}
+	if !reflect.DeepEqual(got.Subnets(), m.Subnets()) {
+		t.Errorf("got subnets %v, want %v", got.Subnets(), m.Subnets())
+	}
+}
+
+func TestGetMultipodNone(t *testing.T) {
+	_, c := newFakeAPIC(t, nil)
+	m, err := c.Pod.GetMultipod(context.Background())
+	if err != nil || m != nil {
+		t.Errorf("got multipod %v, error %v, want neither", m, err)
+	}
+}
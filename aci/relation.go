@@ -0,0 +1,44 @@
+package aci
+
+// Relation is a relation from one ACI object to another
+type Relation struct {
+	RelationAttrs `json:"attributes"`
+}
+
+// RelationAttrs contains the attributes of a relation.
+// Only the attributes of the relation's own class are set.
+type RelationAttrs struct {
+	DN                     string `json:"dn,omitempty"`
+	Encap                  string `json:"encap,omitempty"`
+	InstrImedcy            string `json:"instrImedcy,omitempty"`
+	Mode                   string `json:"mode,omitempty"`
+	Status                 string `json:"status,omitempty"`
+	TDN                    string `json:"tDn,omitempty"`
+	TnBgpCtxPolName        string `json:"tnBgpCtxPolName,omitempty"`
+	TnBgpInstPolName       string `json:"tnBgpInstPolName,omitempty"`
+	TnCdpIfPolName         string `json:"tnCdpIfPolName,omitempty"`
+	TnCommPolName          string `json:"tnCommPolName,omitempty"`
+	TnCoopPolName          string `json:"tnCoopPolName,omitempty"`
+	TnDatetimePolName      string `json:"tnDatetimePolName,omitempty"`
+	TnFabricHIfPolName     string `json:"tnFabricHIfPolName,omitempty"`
+	TnFvBDName             string `json:"tnFvBDName,omitempty"`
+	TnFvCtxName            string `json:"tnFvCtxName,omitempty"`
+	TnIsisDomPolName       string `json:"tnIsisDomPolName,omitempty"`
+	TnL3extOutName         string `json:"tnL3extOutName,omitempty"`
+	TnL3extRouteTagPolName string `json:"tnL3extRouteTagPolName,omitempty"`
+	TnLacpLagPolName       string `json:"tnLacpLagPolName,omitempty"`
+	TnLldpIfPolName        string `json:"tnLldpIfPolName,omitempty"`
+	TnMacsecFabIfPolName   string `json:"tnMacsecFabIfPolName,omitempty"`
+	TnMcpIfPolName         string `json:"tnMcpIfPolName,omitempty"`
+	TnSnmpPolName          string `json:"tnSnmpPolName,omitempty"`
+	TnStormctrlIfPolName   string `json:"tnStormctrlIfPolName,omitempty"`
+	TnStpIfPolName         string `json:"tnStpIfPolName,omitempty"`
+	TnVzBrCPName           string `json:"tnVzBrCPName,omitempty"`
+	TnVzFilterName         string `json:"tnVzFilterName,omitempty"`
+}
+
+// newRelation returns a relation with the given attributes,
+// to be created along with its parent.
+func newRelation(attrs RelationAttrs) *Relation {
+	return &Relation{RelationAttrs: attrs}
+}
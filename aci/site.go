@@ -2,223 +2,602 @@ package aci
 
 import (
 	"fmt"
-	"regexp"
+	"strings"
 )
 
-// location
+// location holds the attributes common to every level
+// of the geolocation hierarchy.
 type location struct {
-	name        string
-	description string
-	locations   []*location
-	status      string
+	object
 }
 
-func (l *location) String() string {
-	if l == nil {
-		return ""
-	}
-	s := ""
-	s += l.name
-	s += "\n"
-	for _, location := range l.locations {
-		s += "\t"
-		s += location.String()
-		s += "\n"
+// tree returns the name of a location followed by its children,
+// each indented beneath it.
+func (l *location) tree(children []string) string {
+	s := l.name + "\n"
+	for _, child := range children {
+		for _, line := range strings.Split(strings.TrimSuffix(child, "\n"), "\n") {
+			s += "\t" + line + "\n"
+		}
 	}
 	return s
 }
 
-// SetName validates and sets the name of a location
-//
-// A site name can be up to 64 characters and must begin with an
-// alphanumeric character. It can only contain alphanumeric
-// characters and the following symbols: -.:_
-func (l *location) SetName(name string) error {
-	valid := regexp.MustCompile(`(^[a-zA-Z0-9]{1,2}$|^[a-zA-Z0-9][a-zA-Z0-9-.:_]{0,63}$)`)
-	if !valid.MatchString(name) {
-		return fmt.Errorf("invalid name: %s", name)
-	}
-	l.name = name
-	return nil
+// Site is a geolocation site, holding its buildings by pointer.
+type Site struct {
+	location
+	buildings []*Building
 }
 
-// Name returns the name of a location.
-func (l *location) Name() string {
-	return l.name
+// Buildings returns the buildings of the site.
+//
+// The buildings returned are those held by the site, so changes
+// made to them are reflected in the site.
+func (site *Site) Buildings() []*Building {
+	if site == nil {
+		return nil
+	}
+	return append([]*Building(nil), site.buildings...)
 }
 
-// SetDescription validates and sets the description of a location
-//
-// A site description can be up to 128 characters. It can only
-// contain alphanumeric characters and the following symbols:
-// !#$%()*,-.:;@_{|}~?&+
-func (l *location) SetDescription(description string) error {
-	valid := regexp.MustCompile(`^[a-zA-Z0-9!#$%()*,-.:;@_{|}~?&+\s]{0,128}$`)
-	if !valid.MatchString(description) {
-		return fmt.Errorf("invalid description: %s", description)
-	}
-	l.description = description
+// Building returns the building with the given name, or nil if there is none.
+func (site *Site) Building(name string) *Building {
+	if i := site.indexBuilding(name); i >= 0 {
+		return site.buildings[i]
+	}
 	return nil
 }
 
-// Description resturns a location description.
-func (l *location) Description() string {
-	return l.description
+func (site *Site) indexBuilding(name string) int {
+	if site == nil {
+		return -1
+	}
+	for i, building := range site.buildings {
+		if building.Name() == name {
+			return i
+		}
+	}
+	return -1
 }
 
-// SetCreated sets the status of the location to "created,modified".
-func (l *location) SetCreated() string {
-	l.status = createdModified
-	return l.status
+// AddBuilding adds a building to the site, replacing any building of the same name.
+func (site *Site) AddBuilding(building *Building) {
+	if i := site.indexBuilding(building.Name()); i >= 0 {
+		site.buildings[i] = building
+		return
+	}
+	site.buildings = append(site.buildings, building)
 }
 
-// SetDeleted sets the status of the location to "deleted".
-func (l *location) SetDeleted() string {
-	l.status = deleted
-	return l.status
+// DeleteBuilding removes the building of the same name from the site.
+func (site *Site) DeleteBuilding(building *Building) {
+	if i := site.indexBuilding(building.Name()); i >= 0 {
+		site.buildings = append(site.buildings[:i], site.buildings[i+1:]...)
+	}
 }
 
-// Status returns the status of the location.
-func (l *location) Status() string {
-	return l.status
+// RenameBuilding validates and changes the name of a building in the site.
+// The new name must not already be used by another building.
+func (site *Site) RenameBuilding(name, newName string) error {
+	i := site.indexBuilding(name)
+	if i < 0 {
+		return fmt.Errorf("no such building: %s", name)
+	}
+	if name == newName {
+		return nil
+	}
+	if site.indexBuilding(newName) >= 0 {
+		return fmt.Errorf("building already exists: %s", newName)
+	}
+	return site.buildings[i].SetName(newName)
 }
 
-func (l *location) addLocation(loc location) {
-	l.locations = append(l.locations, &loc)
+// MoveBuilding moves a building from the site to another site.
+// The other site must not already have a building of the same name.
+func (site *Site) MoveBuilding(name string, to *Site) error {
+	i := site.indexBuilding(name)
+	if i < 0 {
+		return fmt.Errorf("no such building: %s", name)
+	}
+	if to == nil {
+		return fmt.Errorf("no site to move building %s to", name)
+	}
+	if to == site {
+		return nil
+	}
+	if to.indexBuilding(name) >= 0 {
+		return fmt.Errorf("building already exists: %s", name)
+	}
+	building := site.buildings[i]
+	site.buildings = append(site.buildings[:i], site.buildings[i+1:]...)
+	to.buildings = append(to.buildings, building)
+	return nil
 }
 
-func (l *location) deleteLocation(loc location) {
-	var index int
-	for i, ll := range l.locations {
-		if ll == &loc {
-			index = i
-			break
-		}
+// String returns the site and everything within it as an indented tree.
+func (site *Site) String() string {
+	if site == nil {
+		return ""
+	}
+	var children []string
+	for _, building := range site.buildings {
+		children = append(children, building.String())
 	}
-	l.locations = append(l.locations[:index], l.locations[index+1:]...)
+	return site.tree(children)
 }
 
-// Site ...
-type Site struct {
+// Building is a geolocation building, holding its floors by pointer.
+type Building struct {
 	location
+	floors []*Floor
 }
 
-// Buildings ...
-func (site *Site) Buildings() []*Building {
-	var buildings []*Building
-	for _, location := range site.locations {
-		buildings = append(buildings, &Building{*location})
+// Floors returns the floors of the building.
+//
+// The floors returned are those held by the building, so changes
+// made to them are reflected in the building.
+func (building *Building) Floors() []*Floor {
+	if building == nil {
+		return nil
 	}
-	return buildings
+	return append([]*Floor(nil), building.floors...)
 }
 
-// AddBuilding ...
-func (site *Site) AddBuilding(building *Building) {
-	site.addLocation(building.location)
+// Floor returns the floor with the given name, or nil if there is none.
+func (building *Building) Floor(name string) *Floor {
+	if i := building.indexFloor(name); i >= 0 {
+		return building.floors[i]
+	}
+	return nil
 }
 
-// DeleteBuilding ...
-func (site *Site) DeleteBuilding(building *Building) {
-	site.deleteLocation(building.location)
+func (building *Building) indexFloor(name string) int {
+	if building == nil {
+		return -1
+	}
+	for i, floor := range building.floors {
+		if floor.Name() == name {
+			return i
+		}
+	}
+	return -1
 }
 
-// Building ...
-type Building struct {
-	location
+// AddFloor adds a floor to the building, replacing any floor of the same name.
+func (building *Building) AddFloor(floor *Floor) {
+	if i := building.indexFloor(floor.Name()); i >= 0 {
+		building.floors[i] = floor
+		return
+	}
+	building.floors = append(building.floors, floor)
 }
 
-// Floors ...
-func (building *Building) Floors() []*Floor {
-	var floors []*Floor
-	for _, location := range building.locations {
-		floors = append(floors, &Floor{*location})
+// DeleteFloor removes the floor of the same name from the building.
+func (building *Building) DeleteFloor(floor *Floor) {
+	if i := building.indexFloor(floor.Name()); i >= 0 {
+		building.floors = append(building.floors[:i], building.floors[i+1:]...)
 	}
-	return floors
 }
 
-// AddFloor ...
-func (building *Building) AddFloor(floor *Floor) {
-	building.addLocation(floor.location)
+// RenameFloor validates and changes the name of a floor in the building.
+// The new name must not already be used by another floor.
+func (building *Building) RenameFloor(name, newName string) error {
+	i := building.indexFloor(name)
+	if i < 0 {
+		return fmt.Errorf("no such floor: %s", name)
+	}
+	if name == newName {
+		return nil
+	}
+	if building.indexFloor(newName) >= 0 {
+		return fmt.Errorf("floor already exists: %s", newName)
+	}
+	return building.floors[i].SetName(newName)
 }
 
-// DeleteFloor ...
-func (building *Building) DeleteFloor(floor *Floor) {
-	building.deleteLocation(floor.location)
+// MoveFloor moves a floor from the building to another building.
+// The other building must not already have a floor of the same name.
+func (building *Building) MoveFloor(name string, to *Building) error {
+	i := building.indexFloor(name)
+	if i < 0 {
+		return fmt.Errorf("no such floor: %s", name)
+	}
+	if to == nil {
+		return fmt.Errorf("no building to move floor %s to", name)
+	}
+	if to == building {
+		return nil
+	}
+	if to.indexFloor(name) >= 0 {
+		return fmt.Errorf("floor already exists: %s", name)
+	}
+	floor := building.floors[i]
+	building.floors = append(building.floors[:i], building.floors[i+1:]...)
+	to.floors = append(to.floors, floor)
+	return nil
 }
 
-// Floor ...
+// String returns the building and everything within it as an indented tree.
+func (building *Building) String() string {
+	if building == nil {
+		return ""
+	}
+	var children []string
+	for _, floor := range building.floors {
+		children = append(children, floor.String())
+	}
+	return building.tree(children)
+}
+
+// Floor is a geolocation floor, holding its rooms by pointer.
 type Floor struct {
 	location
+	rooms []*Room
 }
 
-// Rooms ...
+// Rooms returns the rooms of the floor.
+//
+// The rooms returned are those held by the floor, so changes
+// made to them are reflected in the floor.
 func (floor *Floor) Rooms() []*Room {
-	var rooms []*Room
-	for _, location := range floor.locations {
-		rooms = append(rooms, &Room{*location})
+	if floor == nil {
+		return nil
 	}
-	return rooms
+	return append([]*Room(nil), floor.rooms...)
 }
 
-// AddRoom ...
+// Room returns the room with the given name, or nil if there is none.
+func (floor *Floor) Room(name string) *Room {
+	if i := floor.indexRoom(name); i >= 0 {
+		return floor.rooms[i]
+	}
+	return nil
+}
+
+func (floor *Floor) indexRoom(name string) int {
+	if floor == nil {
+		return -1
+	}
+	for i, room := range floor.rooms {
+		if room.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddRoom adds a room to the floor, replacing any room of the same name.
 func (floor *Floor) AddRoom(room *Room) {
-	floor.addLocation(room.location)
+	if i := floor.indexRoom(room.Name()); i >= 0 {
+		floor.rooms[i] = room
+		return
+	}
+	floor.rooms = append(floor.rooms, room)
 }
 
-// DeleteRoom ...
+// DeleteRoom removes the room of the same name from the floor.
 func (floor *Floor) DeleteRoom(room *Room) {
-	floor.deleteLocation(room.location)
+	if i := floor.indexRoom(room.Name()); i >= 0 {
+		floor.rooms = append(floor.rooms[:i], floor.rooms[i+1:]...)
+	}
 }
 
-// Room ...
+// RenameRoom validates and changes the name of a room in the floor.
+// The new name must not already be used by another room.
+func (floor *Floor) RenameRoom(name, newName string) error {
+	i := floor.indexRoom(name)
+	if i < 0 {
+		return fmt.Errorf("no such room: %s", name)
+	}
+	if name == newName {
+		return nil
+	}
+	if floor.indexRoom(newName) >= 0 {
+		return fmt.Errorf("room already exists: %s", newName)
+	}
+	return floor.rooms[i].SetName(newName)
+}
+
+// MoveRoom moves a room from the floor to another floor.
+// The other floor must not already have a room of the same name.
+func (floor *Floor) MoveRoom(name string, to *Floor) error {
+	i := floor.indexRoom(name)
+	if i < 0 {
+		return fmt.Errorf("no such room: %s", name)
+	}
+	if to == nil {
+		return fmt.Errorf("no floor to move room %s to", name)
+	}
+	if to == floor {
+		return nil
+	}
+	if to.indexRoom(name) >= 0 {
+		return fmt.Errorf("room already exists: %s", name)
+	}
+	room := floor.rooms[i]
+	floor.rooms = append(floor.rooms[:i], floor.rooms[i+1:]...)
+	to.rooms = append(to.rooms, room)
+	return nil
+}
+
+// String returns the floor and everything within it as an indented tree.
+func (floor *Floor) String() string {
+	if floor == nil {
+		return ""
+	}
+	var children []string
+	for _, room := range floor.rooms {
+		children = append(children, room.String())
+	}
+	return floor.tree(children)
+}
+
+// Room is a geolocation room, holding its rows by pointer.
 type Room struct {
 	location
+	rows []*Row
 }
 
-// Rows ...
+// Rows returns the rows of the room.
+//
+// The rows returned are those held by the room, so changes
+// made to them are reflected in the room.
 func (room *Room) Rows() []*Row {
-	var rows []*Row
-	for _, location := range room.locations {
-		rows = append(rows, &Row{*location})
+	if room == nil {
+		return nil
+	}
+	return append([]*Row(nil), room.rows...)
+}
+
+// Row returns the row with the given name, or nil if there is none.
+func (room *Room) Row(name string) *Row {
+	if i := room.indexRow(name); i >= 0 {
+		return room.rows[i]
+	}
+	return nil
+}
+
+func (room *Room) indexRow(name string) int {
+	if room == nil {
+		return -1
 	}
-	return rows
+	for i, row := range room.rows {
+		if row.Name() == name {
+			return i
+		}
+	}
+	return -1
 }
 
-// AddRow ...
+// AddRow adds a row to the room, replacing any row of the same name.
 func (room *Room) AddRow(row *Row) {
-	room.addLocation(row.location)
+	if i := room.indexRow(row.Name()); i >= 0 {
+		room.rows[i] = row
+		return
+	}
+	room.rows = append(room.rows, row)
 }
 
-// DeleteRow ...
+// DeleteRow removes the row of the same name from the room.
 func (room *Room) DeleteRow(row *Row) {
-	room.deleteLocation(row.location)
+	if i := room.indexRow(row.Name()); i >= 0 {
+		room.rows = append(room.rows[:i], room.rows[i+1:]...)
+	}
 }
 
-// Row ...
+// RenameRow validates and changes the name of a row in the room.
+// The new name must not already be used by another row.
+func (room *Room) RenameRow(name, newName string) error {
+	i := room.indexRow(name)
+	if i < 0 {
+		return fmt.Errorf("no such row: %s", name)
+	}
+	if name == newName {
+		return nil
+	}
+	if room.indexRow(newName) >= 0 {
+		return fmt.Errorf("row already exists: %s", newName)
+	}
+	return room.rows[i].SetName(newName)
+}
+
+// MoveRow moves a row from the room to another room.
+// The other room must not already have a row of the same name.
+func (room *Room) MoveRow(name string, to *Room) error {
+	i := room.indexRow(name)
+	if i < 0 {
+		return fmt.Errorf("no such row: %s", name)
+	}
+	if to == nil {
+		return fmt.Errorf("no room to move row %s to", name)
+	}
+	if to == room {
+		return nil
+	}
+	if to.indexRow(name) >= 0 {
+		return fmt.Errorf("row already exists: %s", name)
+	}
+	row := room.rows[i]
+	room.rows = append(room.rows[:i], room.rows[i+1:]...)
+	to.rows = append(to.rows, row)
+	return nil
+}
+
+// String returns the room and everything within it as an indented tree.
+func (room *Room) String() string {
+	if room == nil {
+		return ""
+	}
+	var children []string
+	for _, row := range room.rows {
+		children = append(children, row.String())
+	}
+	return room.tree(children)
+}
+
+// Row is a geolocation row, holding its racks by pointer.
 type Row struct {
 	location
+	racks []*Rack
 }
 
-// Racks ...
+// Racks returns the racks of the row.
+//
+// The racks returned are those held by the row, so changes
+// made to them are reflected in the row.
 func (row *Row) Racks() []*Rack {
-	var racks []*Rack
-	for _, location := range row.locations {
-		racks = append(racks, &Rack{*location})
+	if row == nil {
+		return nil
+	}
+	return append([]*Rack(nil), row.racks...)
+}
+
+// Rack returns the rack with the given name, or nil if there is none.
+func (row *Row) Rack(name string) *Rack {
+	if i := row.indexRack(name); i >= 0 {
+		return row.racks[i]
 	}
-	return racks
+	return nil
 }
 
-// AddRack ...
+func (row *Row) indexRack(name string) int {
+	if row == nil {
+		return -1
+	}
+	for i, rack := range row.racks {
+		if rack.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddRack adds a rack to the row, replacing any rack of the same name.
 func (row *Row) AddRack(rack *Rack) {
-	row.addLocation(rack.location)
+	if i := row.indexRack(rack.Name()); i >= 0 {
+		row.racks[i] = rack
+		return
+	}
+	row.racks = append(row.racks, rack)
 }
 
-// DeleteRack ...
+// DeleteRack removes the rack of the same name from the row.
 func (row *Row) DeleteRack(rack *Rack) {
-	row.deleteLocation(rack.location)
+	if i := row.indexRack(rack.Name()); i >= 0 {
+		row.racks = append(row.racks[:i], row.racks[i+1:]...)
+	}
+}
+
+// RenameRack validates and changes the name of a rack in the row.
+// The new name must not already be used by another rack.
+func (row *Row) RenameRack(name, newName string) error {
+	i := row.indexRack(name)
+	if i < 0 {
+		return fmt.Errorf("no such rack: %s", name)
+	}
+	if name == newName {
+		return nil
+	}
+	if row.indexRack(newName) >= 0 {
+		return fmt.Errorf("rack already exists: %s", newName)
+	}
+	return row.racks[i].SetName(newName)
+}
+
+// MoveRack moves a rack from the row to another row.
+// The other row must not already have a rack of the same name.
+func (row *Row) MoveRack(name string, to *Row) error {
+	i := row.indexRack(name)
+	if i < 0 {
+		return fmt.Errorf("no such rack: %s", name)
+	}
+	if to == nil {
+		return fmt.Errorf("no row to move rack %s to", name)
+	}
+	if to == row {
+		return nil
+	}
+	if to.indexRack(name) >= 0 {
+		return fmt.Errorf("rack already exists: %s", name)
+	}
+	rack := row.racks[i]
+	row.racks = append(row.racks[:i], row.racks[i+1:]...)
+	to.racks = append(to.racks, rack)
+	return nil
+}
+
+// String returns the row and everything within it as an indented tree.
+func (row *Row) String() string {
+	if row == nil {
+		return ""
+	}
+	var children []string
+	for _, rack := range row.racks {
+		children = append(children, rack.String())
+	}
+	return row.tree(children)
 }
 
-// Rack ...
+// Rack is a geolocation rack, holding the fabric nodes installed in it.
 type Rack struct {
 	location
+
+	// nodes are the fabric nodes installed in the rack, and removed
+	// those taken out of it, whose relations are to be deleted
+	nodes   []*Node
+	removed []*Node
+}
+
+// Nodes returns the fabric nodes installed in the rack.
+func (rack *Rack) Nodes() []*Node {
+	if rack == nil {
+		return nil
+	}
+	return append([]*Node(nil), rack.nodes...)
+}
+
+// AddNode records a fabric node as being installed in the rack.
+// Adding a node that is already in the rack has no effect.
+func (rack *Rack) AddNode(node *Node) {
+	rack.removed = deleteNode(rack.removed, node)
+	for _, n := range rack.nodes {
+		if n.ID() == node.ID() {
+			return
+		}
+	}
+	rack.nodes = append(rack.nodes, node)
+}
+
+// DeleteNode removes a fabric node from the rack. The relation of the
+// rack to the node is deleted when the site is next updated.
+func (rack *Rack) DeleteNode(node *Node) {
+	for _, n := range rack.nodes {
+		if n.ID() == node.ID() {
+			rack.nodes = deleteNode(rack.nodes, n)
+			rack.removed = append(rack.removed, n)
+			return
+		}
+	}
+}
+
+// deleteNode returns nodes without the node of the same id as node.
+func deleteNode(nodes []*Node, node *Node) []*Node {
+	for i, n := range nodes {
+		if n.ID() == node.ID() {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
+// String returns the rack and the nodes installed in it as an indented tree.
+func (rack *Rack) String() string {
+	if rack == nil {
+		return ""
+	}
+	var children []string
+	for _, node := range rack.nodes {
+		children = append(children, node.String())
+	}
+	return rack.tree(children)
 }
@@ -0,0 +1,418 @@
+package aci
+
+import (
+	"reflect"
+	"testing"
+)
+
+// siteLevel drives the tests of one level of the geolocation hierarchy
+// through its parent, so that every level is tested the same way.
+type siteLevel struct {
+	kind   string
+	parent func(t *testing.T) interface{}
+	add    func(t *testing.T, parent interface{}, name, description string)
+	get    func(parent interface{}, name string) (description string, ok bool)
+	names  func(parent interface{}) []string
+	del    func(parent interface{}, name string)
+	rename func(parent interface{}, name, newName string) error
+	move   func(parent, to interface{}, name string) error
+	// describe changes the description of the child at index i
+	// through the slice returned by the parent.
+	describe func(parent interface{}, i int, description string) error
+}
+
+var geo = &GeolocationService{}
+
+var siteLevels = []siteLevel{
+	{
+		kind: "building",
+		parent: func(t *testing.T) interface{} {
+			site, err := geo.NewSite("site", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return site
+		},
+		add: func(t *testing.T, p interface{}, name, description string) {
+			b, err := geo.NewBuilding(name, description)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.(*Site).AddBuilding(b)
+		},
+		get: func(p interface{}, name string) (string, bool) {
+			b := p.(*Site).Building(name)
+			if b == nil {
+				return "", false
+			}
+			return b.Description(), true
+		},
+		names: func(p interface{}) []string {
+			var names []string
+			for _, b := range p.(*Site).Buildings() {
+				names = append(names, b.Name())
+			}
+			return names
+		},
+		del: func(p interface{}, name string) {
+			b, _ := geo.NewBuilding(name, "")
+			p.(*Site).DeleteBuilding(b)
+		},
+		rename: func(p interface{}, name, newName string) error {
+			return p.(*Site).RenameBuilding(name, newName)
+		},
+		move: func(p, to interface{}, name string) error {
+			site, _ := to.(*Site)
+			return p.(*Site).MoveBuilding(name, site)
+		},
+		describe: func(p interface{}, i int, description string) error {
+			return p.(*Site).Buildings()[i].SetDescription(description)
+		},
+	},
+	{
+		kind: "floor",
+		parent: func(t *testing.T) interface{} {
+			b, err := geo.NewBuilding("building", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		},
+		add: func(t *testing.T, p interface{}, name, description string) {
+			f, err := geo.NewFloor(name, description)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.(*Building).AddFloor(f)
+		},
+		get: func(p interface{}, name string) (string, bool) {
+			f := p.(*Building).Floor(name)
+			if f == nil {
+				return "", false
+			}
+			return f.Description(), true
+		},
+		names: func(p interface{}) []string {
+			var names []string
+			for _, f := range p.(*Building).Floors() {
+				names = append(names, f.Name())
+			}
+			return names
+		},
+		del: func(p interface{}, name string) {
+			f, _ := geo.NewFloor(name, "")
+			p.(*Building).DeleteFloor(f)
+		},
+		rename: func(p interface{}, name, newName string) error {
+			return p.(*Building).RenameFloor(name, newName)
+		},
+		move: func(p, to interface{}, name string) error {
+			b, _ := to.(*Building)
+			return p.(*Building).MoveFloor(name, b)
+		},
+		describe: func(p interface{}, i int, description string) error {
+			return p.(*Building).Floors()[i].SetDescription(description)
+		},
+	},
+	{
+		kind: "room",
+		parent: func(t *testing.T) interface{} {
+			f, err := geo.NewFloor("floor", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return f
+		},
+		add: func(t *testing.T, p interface{}, name, description string) {
+			r, err := geo.NewRoom(name, description)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.(*Floor).AddRoom(r)
+		},
+		get: func(p interface{}, name string) (string, bool) {
+			r := p.(*Floor).Room(name)
+			if r == nil {
+				return "", false
+			}
+			return r.Description(), true
+		},
+		names: func(p interface{}) []string {
+			var names []string
+			for _, r := range p.(*Floor).Rooms() {
+				names = append(names, r.Name())
+			}
+			return names
+		},
+		del: func(p interface{}, name string) {
+			r, _ := geo.NewRoom(name, "")
+			p.(*Floor).DeleteRoom(r)
+		},
+		rename: func(p interface{}, name, newName string) error {
+			return p.(*Floor).RenameRoom(name, newName)
+		},
+		move: func(p, to interface{}, name string) error {
+			f, _ := to.(*Floor)
+			return p.(*Floor).MoveRoom(name, f)
+		},
+		describe: func(p interface{}, i int, description string) error {
+			return p.(*Floor).Rooms()[i].SetDescription(description)
+		},
+	},
+	{
+		kind: "row",
+		parent: func(t *testing.T) interface{} {
+			r, err := geo.NewRoom("room", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r
+		},
+		add: func(t *testing.T, p interface{}, name, description string) {
+			r, err := geo.NewRow(name, description)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.(*Room).AddRow(r)
+		},
+		get: func(p interface{}, name string) (string, bool) {
+			r := p.(*Room).Row(name)
+			if r == nil {
+				return "", false
+			}
+			return r.Description(), true
+		},
+		names: func(p interface{}) []string {
+			var names []string
+			for _, r := range p.(*Room).Rows() {
+				names = append(names, r.Name())
+			}
+			return names
+		},
+		del: func(p interface{}, name string) {
+			r, _ := geo.NewRow(name, "")
+			p.(*Room).DeleteRow(r)
+		},
+		rename: func(p interface{}, name, newName string) error {
+			return p.(*Room).RenameRow(name, newName)
+		},
+		move: func(p, to interface{}, name string) error {
+			r, _ := to.(*Room)
+			return p.(*Room).MoveRow(name, r)
+		},
+		describe: func(p interface{}, i int, description string) error {
+			return p.(*Room).Rows()[i].SetDescription(description)
+		},
+	},
+	{
+		kind: "rack",
+		parent: func(t *testing.T) interface{} {
+			r, err := geo.NewRow("row", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r
+		},
+		add: func(t *testing.T, p interface{}, name, description string) {
+			r, err := geo.NewRack(name, description)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.(*Row).AddRack(r)
+		},
+		get: func(p interface{}, name string) (string, bool) {
+			r := p.(*Row).Rack(name)
+			if r == nil {
+				return "", false
+			}
+			return r.Description(), true
+		},
+		names: func(p interface{}) []string {
+			var names []string
+			for _, r := range p.(*Row).Racks() {
+				names = append(names, r.Name())
+			}
+			return names
+		},
+		del: func(p interface{}, name string) {
+			r, _ := geo.NewRack(name, "")
+			p.(*Row).DeleteRack(r)
+		},
+		rename: func(p interface{}, name, newName string) error {
+			return p.(*Row).RenameRack(name, newName)
+		},
+		move: func(p, to interface{}, name string) error {
+			r, _ := to.(*Row)
+			return p.(*Row).MoveRack(name, r)
+		},
+		describe: func(p interface{}, i int, description string) error {
+			return p.(*Row).Racks()[i].SetDescription(description)
+		},
+	},
+}
+
+// newSiteLevel returns a parent of the level holding the children a, b and c.
+func newSiteLevel(t *testing.T, l siteLevel) interface{} {
+	p := l.parent(t)
+	for _, name := range []string{"a", "b", "c"} {
+		l.add(t, p, name, "first "+name)
+	}
+	return p
+}
+
+func checkNames(t *testing.T, l siteLevel, p interface{}, want ...string) {
+	t.Helper()
+	if got := l.names(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("%ss: got %v, want %v", l.kind, got, want)
+	}
+}
+
+func TestSiteLookup(t *testing.T) {
+	for _, l := range siteLevels {
+		t.Run(l.kind, func(t *testing.T) {
+			p := newSiteLevel(t, l)
+			for _, name := range []string{"a", "b", "c"} {
+				if d, ok := l.get(p, name); !ok || d != "first "+name {
+					t.Errorf("%s %s: got %q, %v", l.kind, name, d, ok)
+				}
+			}
+			if _, ok := l.get(p, "missing"); ok {
+				t.Errorf("found a %s that was never added", l.kind)
+			}
+		})
+	}
+}
+
+func TestSiteAddReplaces(t *testing.T) {
+	for _, l := range siteLevels {
+		t.Run(l.kind, func(t *testing.T) {
+			p := newSiteLevel(t, l)
+			l.add(t, p, "b", "second b")
+			checkNames(t, l, p, "a", "b", "c")
+			if d, _ := l.get(p, "b"); d != "second b" {
+				t.Errorf("%s b was not replaced: %q", l.kind, d)
+			}
+		})
+	}
+}
+
+func TestSiteDelete(t *testing.T) {
+	tests := []struct {
+		del  []string
+		want []string
+	}{
+		{[]string{"a"}, []string{"b", "c"}},
+		{[]string{"b"}, []string{"a", "c"}},
+		{[]string{"c"}, []string{"a", "b"}},
+		{[]string{"missing"}, []string{"a", "b", "c"}},
+		{[]string{"a", "b", "c"}, nil},
+	}
+	for _, l := range siteLevels {
+		for _, tt := range tests {
+			p := newSiteLevel(t, l)
+			for _, name := range tt.del {
+				l.del(p, name)
+			}
+			checkNames(t, l, p, tt.want...)
+		}
+	}
+}
+
+func TestSiteRename(t *testing.T) {
+	tests := []struct {
+		name, newName string
+		wantErr       bool
+		want          []string
+	}{
+		{"a", "d", false, []string{"d", "b", "c"}},
+		{"a", "a", false, []string{"a", "b", "c"}},
+		{"a", "b", true, []string{"a", "b", "c"}},
+		{"a", "-invalid", true, []string{"a", "b", "c"}},
+		{"missing", "d", true, []string{"a", "b", "c"}},
+	}
+	for _, l := range siteLevels {
+		for _, tt := range tests {
+			p := newSiteLevel(t, l)
+			err := l.rename(p, tt.name, tt.newName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("rename %s %s to %s: got error %v", l.kind, tt.name, tt.newName, err)
+			}
+			checkNames(t, l, p, tt.want...)
+		}
+	}
+}
+
+func TestSiteMove(t *testing.T) {
+	for _, l := range siteLevels {
+		t.Run(l.kind, func(t *testing.T) {
+			p := newSiteLevel(t, l)
+
+			if err := l.move(p, nil, "a"); err == nil {
+				t.Error("moved to a nil parent")
+			}
+			checkNames(t, l, p, "a", "b", "c")
+
+			if err := l.move(p, p, "a"); err != nil {
+				t.Errorf("move to the same parent: %v", err)
+			}
+			checkNames(t, l, p, "a", "b", "c")
+
+			to := l.parent(t)
+			l.add(t, to, "b", "other b")
+			if err := l.move(p, to, "b"); err == nil {
+				t.Error("moved onto a child of the same name")
+			}
+			checkNames(t, l, p, "a", "b", "c")
+			checkNames(t, l, to, "b")
+
+			if err := l.move(p, to, "missing"); err == nil {
+				t.Error("moved a missing child")
+			}
+
+			if err := l.move(p, to, "a"); err != nil {
+				t.Errorf("move: %v", err)
+			}
+			checkNames(t, l, p, "b", "c")
+			checkNames(t, l, to, "b", "a")
+			if d, _ := l.get(to, "a"); d != "first a" {
+				t.Errorf("moved %s has description %q", l.kind, d)
+			}
+		})
+	}
+}
+
+func TestSiteChildrenShared(t *testing.T) {
+	for _, l := range siteLevels {
+		t.Run(l.kind, func(t *testing.T) {
+			p := newSiteLevel(t, l)
+			if err := l.describe(p, 1, "changed"); err != nil {
+				t.Fatal(err)
+			}
+			if d, _ := l.get(p, "b"); d != "changed" {
+				t.Errorf("change to %s b not reflected in its parent: %q", l.kind, d)
+			}
+		})
+	}
+}
+
+func TestRackNodes(t *testing.T) {
+	rack, err := geo.NewRack("rack", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm := &FabricMembershipService{}
+	leaf1, _ := fm.NewNode("leaf1", "101", "1", "FDO1", "leaf")
+	leaf2, _ := fm.NewNode("leaf2", "102", "1", "FDO2", "leaf")
+
+	rack.AddNode(leaf1)
+	rack.AddNode(leaf2)
+	rack.AddNode(leaf1)
+	if got := rack.Nodes(); len(got) != 2 {
+		t.Fatalf("got %d nodes, want 2", len(got))
+	}
+
+	rack.DeleteNode(leaf1)
+	if got := rack.Nodes(); len(got) != 1 || got[0] != leaf2 {
+		t.Errorf("got nodes %v, want leaf2", got)
+	}
+}
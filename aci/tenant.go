@@ -0,0 +1,11 @@
+package aci
+
+// Tenant is an ACI tenant, the container for application policies.
+type Tenant struct {
+	object
+}
+
+// String returns the string representation of a tenant
+func (t *Tenant) String() string {
+	return t.name
+}
@@ -0,0 +1,141 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+)
+
+// TenantContainer is a container for a tenant
+type TenantContainer struct {
+	FvTenant `json:"fvTenant"`
+}
+
+// FvTenant is a tenant
+type FvTenant struct {
+	TenantAttrs `json:"attributes"`
+}
+
+// TenantAttrs contains the attributes of a tenant
+type TenantAttrs struct {
+	Descr  *string `json:"descr,omitempty"` // nil when deleting
+	DN     string  `json:"dn,omitempty"`
+	Name   string  `json:"name,omitempty"`
+	RN     string  `json:"rn,omitempty"`
+	Status string  `json:"status,omitempty"`
+}
+
+// TenantResponse contains the response for tenant requests
+type TenantResponse struct {
+	TotalCount string            `json:"totalCount"`
+	Imdata     []TenantContainer `json:"imdata"`
+}
+
+// TenantService handles communication with the tenant related
+// methods of the APIC API.
+type TenantService service
+
+// tenantDN returns the distinguished name of a tenant.
+func tenantDN(tenant string) string {
+	return fmt.Sprintf("uni/tn-%s", tenant)
+}
+
+// NewTenant instantiates a valid tenant.
+func (s *TenantService) NewTenant(name, description string) (*Tenant, error) {
+	tenant := &Tenant{}
+
+	if err := tenant.SetName(name); err != nil {
+		return tenant, err
+	}
+
+	if err := tenant.SetDescription(description); err != nil {
+		return tenant, err
+	}
+
+	return tenant, nil
+}
+
+func newTenantContainer(tenant *Tenant) TenantContainer {
+	tc := TenantContainer{
+		FvTenant: FvTenant{
+			TenantAttrs: TenantAttrs{
+				DN:     tenantDN(tenant.Name()),
+				Name:   tenant.Name(),
+				Status: tenant.Status(),
+			},
+		},
+	}
+	// the description is always sent, even when empty,
+	// so that it can be cleared
+	if tenant.Status() != deleted {
+		descr := tenant.Description()
+		tc.Descr = &descr
+	}
+	return tc
+}
+
+// Create creates a tenant.
+func (s *TenantService) Create(ctx context.Context, tenant *Tenant) (TenantResponse, error) {
+	tenant.SetCreated()
+	return s.Update(ctx, tenant)
+}
+
+// Delete deletes a tenant and everything within it.
+func (s *TenantService) Delete(ctx context.Context, tenant *Tenant) (TenantResponse, error) {
+	tenant.SetDeleted()
+	return s.Update(ctx, tenant)
+}
+
+// Update creates, modifies or deletes a tenant according to its status.
+func (s *TenantService) Update(ctx context.Context, tenant *Tenant) (TenantResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", tenantDN(tenant.Name()))
+	payload := newTenantContainer(tenant)
+
+	var tr TenantResponse
+	err := s.client.post(ctx, path, payload, &tr)
+	return tr, err
+}
+
+// Get retrieves a tenant.
+func (s *TenantService) Get(ctx context.Context, name string) (*Tenant, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", tenantDN(name))
+
+	var tr TenantResponse
+	if err := s.client.get(ctx, path, &tr); err != nil {
+		return nil, fmt.Errorf("get tenant: %v", err)
+	}
+	if len(tr.Imdata) == 0 {
+		return nil, fmt.Errorf("get tenant: %s not found", name)
+	}
+	return tenantFromResponse(tr.Imdata[0]), nil
+}
+
+// List lists all tenants.
+func (s *TenantService) List(ctx context.Context) ([]*Tenant, error) {
+	path := "api/node/class/fvTenant.json"
+
+	var tr TenantResponse
+	if err := s.client.get(ctx, path, &tr); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var tenants []*Tenant
+	for _, t := range tr.Imdata {
+		tenants = append(tenants, tenantFromResponse(t))
+	}
+	return tenants, nil
+}
+
+func tenantFromResponse(t TenantContainer) *Tenant {
+	// as with fabric nodes, we trust the APIC
+	// to have already validated the tenant.
+	tenant := &Tenant{
+		object: object{
+			name:   t.Name,
+			status: t.Status,
+		},
+	}
+	if t.Descr != nil {
+		tenant.description = *t.Descr
+	}
+	return tenant
+}
@@ -0,0 +1,67 @@
+package aci
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTenantUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	tenant, err := c.Tenant.NewTenant("t1", "first tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Tenant.Create(context.Background(), tenant); err != nil {
+		t.Fatal(err)
+	}
+	// an empty description is sent, clearing the current one
+	if err := tenant.SetDescription(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Tenant.Update(context.Background(), tenant); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Tenant.Delete(context.Background(), tenant); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := f.posted("/api/node/mo/uni/tn-t1.json")
+	want := []string{
+		`{"fvTenant":{"attributes":{"dn":"uni/tn-t1","name":"t1","descr":"first tenant","status":"created,modified"}}}`,
+		`{"fvTenant":{"attributes":{"dn":"uni/tn-t1","name":"t1","descr":"","status":"created,modified"}}}`,
+		`{"fvTenant":{"attributes":{"dn":"uni/tn-t1","name":"t1","status":"deleted"}}}`,
+	}
+	if len(posts) != len(want) {
+		t.Fatalf("got %d posts, want %d", len(posts), len(want))
+	}
+	for i := range want {
+		jsonEqual(t, posts[i], want[i])
+	}
+}
+
+func TestTenantGet(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/tn-t1.json":   `{"imdata":[{"fvTenant":{"attributes":{"name":"t1","descr":"first tenant"}}}]}`,
+		"/api/node/class/fvTenant.json": `{"imdata":[{"fvTenant":{"attributes":{"name":"common"}}},{"fvTenant":{"attributes":{"name":"t1","descr":"first tenant"}}}]}`,
+	})
+
+	tenant, err := c.Tenant.Get(context.Background(), "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant.Name() != "t1" || tenant.Description() != "first tenant" {
+		t.Errorf("got tenant %s (%s)", tenant.Name(), tenant.Description())
+	}
+	if _, err := c.Tenant.Get(context.Background(), "t2"); err == nil {
+		t.Error("expected tenant t2 not to be found")
+	}
+
+	tenants, err := c.Tenant.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants) != 2 || tenants[0].Name() != "common" || tenants[1].Description() != "first tenant" {
+		t.Errorf("got tenants %v", tenants)
+	}
+}
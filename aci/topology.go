@@ -0,0 +1,165 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FabricLinkContainer is a container for a link between two fabric nodes
+type FabricLinkContainer struct {
+	FabricLink `json:"fabricLink"`
+}
+
+// FabricLink is a link between two fabric nodes
+type FabricLink struct {
+	FabricLinkAttrs `json:"attributes"`
+}
+
+// FabricLinkAttrs contains the attributes of a fabric link
+type FabricLinkAttrs struct {
+	DN string `json:"dn"`
+	N1 string `json:"n1"` // node id
+	S1 string `json:"s1"` // slot
+	P1 string `json:"p1"` // port
+	N2 string `json:"n2"`
+	S2 string `json:"s2"`
+	P2 string `json:"p2"`
+}
+
+// AdjacencyContainer is a container for an LLDP or CDP adjacency.
+// Only one of its fields is set.
+type AdjacencyContainer struct {
+	LLDPAdjEp *Adjacency `json:"lldpAdjEp,omitempty"`
+	CDPAdjEp  *Adjacency `json:"cdpAdjEp,omitempty"`
+}
+
+// Adjacency is a neighbor discovered by LLDP or CDP
+type Adjacency struct {
+	AdjacencyAttrs `json:"attributes"`
+}
+
+// AdjacencyAttrs contains the attributes of an LLDP or CDP adjacency
+type AdjacencyAttrs struct {
+	DN      string `json:"dn"`
+	SysName string `json:"sysName"`
+	DevID   string `json:"devId"`   // cdp only
+	PortID  string `json:"portId"`  // cdp only
+	PortIDV string `json:"portIdV"` // lldp only
+	MgmtIP  string `json:"mgmtIp"`
+}
+
+// TopologyService handles communication with the physical topology
+// related methods of the APIC API.
+type TopologyService service
+
+// Discover builds the physical topology of the ACI fabric from the fabric
+// links between nodes and the LLDP and CDP adjacencies of every node.
+func (s *TopologyService) Discover(ctx context.Context) (*Topology, error) {
+	nodes, err := s.client.FabricMembership.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discover: %v", err)
+	}
+	t := newTopology(nodes)
+
+	// structure of expected response
+	var fr struct {
+		Imdata []FabricLinkContainer `json:"imdata"`
+	}
+	if err := s.client.get(ctx, "api/node/class/fabricLink.json", &fr); err != nil {
+		return nil, fmt.Errorf("discover: %v", err)
+	}
+	for _, l := range fr.Imdata {
+		t.addLink(&Link{
+			Local:  t.endpoint(l.N1, fmt.Sprintf("eth%s/%s", l.S1, l.P1)),
+			Remote: t.endpoint(l.N2, fmt.Sprintf("eth%s/%s", l.S2, l.P2)),
+			Source: LinkSourceFabric,
+		})
+	}
+
+	for _, class := range []string{"lldpAdjEp", "cdpAdjEp"} {
+		// structure of expected response
+		var ar struct {
+			Imdata []AdjacencyContainer `json:"imdata"`
+		}
+		path := fmt.Sprintf("api/node/class/%s.json", class)
+		if err := s.client.get(ctx, path, &ar); err != nil {
+			return nil, fmt.Errorf("discover: %v", err)
+		}
+		for _, a := range ar.Imdata {
+			if l := t.adjacencyLink(a); l != nil {
+				t.addLink(l)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// endpoint returns the endpoint of a fabric node interface.
+func (t *Topology) endpoint(nodeID, intf string) Endpoint {
+	e := Endpoint{NodeID: nodeID, Interface: intf}
+	if node, ok := t.nodes[nodeID]; ok {
+		e.Name = node.Name()
+	}
+	return e
+}
+
+// adjacencyLink returns the link described by an LLDP or CDP adjacency,
+// or nil if the adjacency is not of a fabric node.
+func (t *Topology) adjacencyLink(a AdjacencyContainer) *Link {
+	var adj *Adjacency
+	var l Link
+	switch {
+	case a.LLDPAdjEp != nil:
+		adj = a.LLDPAdjEp
+		l.Source = LinkSourceLLDP
+		l.Remote.Interface = adj.PortIDV
+		l.Remote.Name = adj.SysName
+	case a.CDPAdjEp != nil:
+		adj = a.CDPAdjEp
+		l.Source = LinkSourceCDP
+		l.Remote.Interface = adj.PortID
+		l.Remote.Name = adj.DevID
+	default:
+		return nil
+	}
+
+	nodeID, intf := adjacencyLocal(adj.DN)
+	if _, ok := t.nodes[nodeID]; !ok {
+		return nil
+	}
+	l.Local = t.endpoint(nodeID, intf)
+
+	// neighbors that are themselves fabric nodes are
+	// linked to the node rather than named externally
+	for _, node := range t.nodes {
+		if node.Name() != "" && node.Name() == l.Remote.Name {
+			l.Remote.NodeID = node.ID()
+			break
+		}
+	}
+	return &l
+}
+
+// adjacencyLocal returns the node id and interface of an adjacency
+// distinguished name, such as
+// "topology/pod-1/node-101/sys/lldp/inst/if-[eth1/49]/adj-1".
+func adjacencyLocal(dn string) (string, string) {
+	var nodeID, intf string
+	if i := strings.Index(dn, "/node-"); i >= 0 {
+		rest := dn[i+len("/node-"):]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			rest = rest[:j]
+		}
+		nodeID = rest
+	}
+	if i := strings.Index(dn, "/if-["); i >= 0 {
+		rest := dn[i+len("/if-["):]
+		if j := strings.Index(rest, "]"); j >= 0 {
+			rest = rest[:j]
+		}
+		intf = rest
+	}
+	return nodeID, intf
+}
@@ -0,0 +1,178 @@
+package aci
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Sources of a topology link.
+const (
+	LinkSourceFabric = "fabric"
+	LinkSourceLLDP   = "lldp"
+	LinkSourceCDP    = "cdp"
+)
+
+// Endpoint is one end of a topology link.
+type Endpoint struct {
+	NodeID    string // fabric node id, empty for external neighbors
+	Interface string // interface name, such as "eth1/49"
+	Name      string // system name of the device
+}
+
+// External reports whether the endpoint is outside of the ACI fabric.
+func (e Endpoint) External() bool {
+	return e.NodeID == ""
+}
+
+// String returns the string representation of an endpoint
+func (e Endpoint) String() string {
+	if e.External() {
+		return fmt.Sprintf("%s %s", e.Name, e.Interface)
+	}
+	return fmt.Sprintf("node-%s %s", e.NodeID, e.Interface)
+}
+
+// Link is an interface level connection between two endpoints.
+type Link struct {
+	Local  Endpoint
+	Remote Endpoint
+	Source string // how the link was discovered
+}
+
+// String returns the string representation of a link
+func (l *Link) String() string {
+	return fmt.Sprintf("%s -> %s (%s)", l.Local, l.Remote, l.Source)
+}
+
+// reverse returns the link as seen from its remote endpoint.
+func (l *Link) reverse() *Link {
+	return &Link{Local: l.Remote, Remote: l.Local, Source: l.Source}
+}
+
+// Topology is the physical topology of the ACI fabric, a graph of
+// fabric nodes keyed by node id with interface level links.
+type Topology struct {
+	nodes map[string]*Node
+	links map[string][]*Link // keyed by local node id
+	ports map[string]bool    // local endpoints already linked
+}
+
+func newTopology(nodes []*Node) *Topology {
+	t := &Topology{
+		nodes: make(map[string]*Node),
+		links: make(map[string][]*Link),
+		ports: make(map[string]bool),
+	}
+	for _, node := range nodes {
+		t.nodes[node.ID()] = node
+	}
+	return t
+}
+
+// addLink adds a link to the topology, unless its local endpoint already has one.
+// Links between two fabric nodes are added in both directions.
+func (t *Topology) addLink(l *Link) {
+	key := l.Local.NodeID + " " + l.Local.Interface
+	if t.ports[key] {
+		return
+	}
+	t.ports[key] = true
+	t.links[l.Local.NodeID] = append(t.links[l.Local.NodeID], l)
+
+	if l.Remote.External() {
+		return
+	}
+	r := l.reverse()
+	rkey := r.Local.NodeID + " " + r.Local.Interface
+	if t.ports[rkey] {
+		return
+	}
+	t.ports[rkey] = true
+	t.links[r.Local.NodeID] = append(t.links[r.Local.NodeID], r)
+}
+
+// Node returns the fabric node with the given id, or nil if there is none.
+func (t *Topology) Node(id string) *Node {
+	return t.nodes[id]
+}
+
+// Nodes returns all fabric nodes of the topology, ordered by node id.
+func (t *Topology) Nodes() []*Node {
+	var nodes []*Node
+	for _, node := range t.nodes {
+		nodes = append(nodes, node)
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// Links returns the links of a fabric node, ordered by local interface.
+func (t *Topology) Links(id string) []*Link {
+	links := append([]*Link(nil), t.links[id]...)
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].Local.Interface < links[j].Local.Interface
+	})
+	return links
+}
+
+// Neighbors returns the fabric nodes directly connected to a fabric node,
+// ordered by node id.
+func (t *Topology) Neighbors(id string) []*Node {
+	seen := make(map[string]bool)
+	var nodes []*Node
+	for _, l := range t.links[id] {
+		if l.Remote.External() || seen[l.Remote.NodeID] {
+			continue
+		}
+		seen[l.Remote.NodeID] = true
+		if node, ok := t.nodes[l.Remote.NodeID]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// ExternalNeighbors returns the links of a fabric node to devices
+// outside of the ACI fabric.
+func (t *Topology) ExternalNeighbors(id string) []*Link {
+	var links []*Link
+	for _, l := range t.Links(id) {
+		if l.Remote.External() {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// Unconnected returns the fabric nodes of the given role, "leaf" or
+// "spine", that have no links to any other fabric node.
+func (t *Topology) Unconnected(role string) []*Node {
+	var nodes []*Node
+	for _, node := range t.nodes {
+		if node.Role() != role {
+			continue
+		}
+		if len(t.Neighbors(node.ID())) == 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// UnconnectedSpines returns the spines that have no links to any
+// other fabric node.
+func (t *Topology) UnconnectedSpines() []*Node {
+	return t.Unconnected("spine")
+}
+
+// sortNodes sorts nodes by numeric node id.
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, _ := strconv.Atoi(nodes[i].ID())
+		b, _ := strconv.Atoi(nodes[j].ID())
+		return a < b
+	})
+}
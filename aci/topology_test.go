@@ -0,0 +1,96 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// topologyFixtures describe two leaves linked to spine 201, spine 202
+// left unconnected, and a server and a router attached to the leaves.
+var topologyFixtures = map[string]string{
+	"/api/node/class/fabricNode.json": `{"imdata":[
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-101","id":"101","name":"leaf-101","role":"leaf"}}},
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-102","id":"102","name":"leaf-102","role":"leaf"}}},
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-201","id":"201","name":"spine-201","role":"spine"}}},
+		{"fabricNode":{"attributes":{"dn":"topology/pod-1/node-202","id":"202","name":"spine-202","role":"spine"}}}
+	]}`,
+	"/api/node/class/fabricLink.json": `{"imdata":[
+		{"fabricLink":{"attributes":{"n1":"101","s1":"1","p1":"49","n2":"201","s2":"1","p2":"1"}}},
+		{"fabricLink":{"attributes":{"n1":"201","s1":"1","p1":"1","n2":"101","s2":"1","p2":"49"}}},
+		{"fabricLink":{"attributes":{"n1":"102","s1":"1","p1":"49","n2":"201","s2":"1","p2":"2"}}}
+	]}`,
+	"/api/node/class/lldpAdjEp.json": `{"imdata":[
+		{"lldpAdjEp":{"attributes":{"dn":"topology/pod-1/node-101/sys/lldp/inst/if-[eth1/49]/adj-1","sysName":"spine-201","portIdV":"eth1/1"}}},
+		{"lldpAdjEp":{"attributes":{"dn":"topology/pod-1/node-101/sys/lldp/inst/if-[eth1/10]/adj-1","sysName":"srv-1","portIdV":"eth0"}}},
+		{"lldpAdjEp":{"attributes":{"dn":"topology/pod-1/node-999/sys/lldp/inst/if-[eth1/10]/adj-1","sysName":"srv-2","portIdV":"eth0"}}}
+	]}`,
+	"/api/node/class/cdpAdjEp.json": `{"imdata":[
+		{"cdpAdjEp":{"attributes":{"dn":"topology/pod-1/node-102/sys/cdp/inst/if-[eth1/10]/adj-1","devId":"router-1","portId":"Gi0/1"}}}
+	]}`,
+}
+
+func TestDiscover(t *testing.T) {
+	_, c := newFakeAPIC(t, topologyFixtures)
+
+	topo, err := c.Topology.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links := func(id string) []string {
+		var ls []string
+		for _, l := range topo.Links(id) {
+			ls = append(ls, l.String())
+		}
+		return ls
+	}
+	tests := []struct {
+		id    string
+		links []string
+	}{
+		{"101", []string{"node-101 eth1/10 -> srv-1 eth0 (lldp)", "node-101 eth1/49 -> node-201 eth1/1 (fabric)"}},
+		{"102", []string{"node-102 eth1/10 -> router-1 Gi0/1 (cdp)", "node-102 eth1/49 -> node-201 eth1/2 (fabric)"}},
+		{"201", []string{"node-201 eth1/1 -> node-101 eth1/49 (fabric)", "node-201 eth1/2 -> node-102 eth1/49 (fabric)"}},
+		{"202", nil},
+		{"999", nil},
+	}
+	for _, tt := range tests {
+		if got := links(tt.id); !reflect.DeepEqual(got, tt.links) {
+			t.Errorf("node %s: got links %q, want %q", tt.id, got, tt.links)
+		}
+	}
+
+	ids := func(nodes []*Node) []string {
+		var s []string
+		for _, n := range nodes {
+			s = append(s, n.ID())
+		}
+		return s
+	}
+	if got := ids(topo.Neighbors("201")); !reflect.DeepEqual(got, []string{"101", "102"}) {
+		t.Errorf("got neighbors of 201 %v, want 101 102", got)
+	}
+	if got := ids(topo.UnconnectedSpines()); !reflect.DeepEqual(got, []string{"202"}) {
+		t.Errorf("got unconnected spines %v, want 202", got)
+	}
+	if got := topo.ExternalNeighbors("101"); len(got) != 1 || got[0].Remote.Name != "srv-1" {
+		t.Errorf("got external neighbors of 101 %v, want srv-1", got)
+	}
+}
+
+func TestAdjacencyLocal(t *testing.T) {
+	tests := []struct {
+		dn, node, intf string
+	}{
+		{"topology/pod-1/node-101/sys/lldp/inst/if-[eth1/49]/adj-1", "101", "eth1/49"},
+		{"topology/pod-2/node-2101/sys/cdp/inst/if-[eth1/1/2]/adj-1", "2101", "eth1/1/2"},
+		{"topology/pod-1", "", ""},
+	}
+	for _, tt := range tests {
+		node, intf := adjacencyLocal(tt.dn)
+		if node != tt.node || intf != tt.intf {
+			t.Errorf("%s: got %q %q, want %q %q", tt.dn, node, intf, tt.node, tt.intf)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package aci
+
+import "fmt"
+
+// VMMDomain is a VMware VMM domain, integrating the fabric with the
+// vCenter controllers managing the hypervisors attached to it.
+type VMMDomain struct {
+	object
+	pool           string
+	poolAllocation string
+	controllers    []*VMMController
+	credentials    []*VMMCredential
+}
+
+// DN returns the distinguished name of the VMM domain.
+func (d *VMMDomain) DN() string {
+	return vmmDomainDN(d.name)
+}
+
+// VLANPool returns the name and allocation mode of the VLAN pool
+// of the VMM domain.
+func (d *VMMDomain) VLANPool() (string, string) {
+	return d.pool, d.poolAllocation
+}
+
+// SetVLANPool sets the VLAN pool of the VMM domain.
+func (d *VMMDomain) SetVLANPool(pool *VLANPool) error {
+	if pool == nil || pool.Name() == "" || pool.Allocation() == "" {
+		return fmt.Errorf("invalid vlan pool: pool must have a name and allocation mode")
+	}
+	d.pool, d.poolAllocation = pool.Name(), pool.Allocation()
+	return nil
+}
+
+// Controllers returns the vCenter controllers of the VMM domain.
+func (d *VMMDomain) Controllers() []*VMMController {
+	return d.controllers
+}
+
+// Controller returns the controller with the given name,
+// or nil if there is none.
+func (d *VMMDomain) Controller(name string) *VMMController {
+	for _, c := range d.controllers {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// AddController adds a controller to the VMM domain,
+// replacing any controller of the same name.
+func (d *VMMDomain) AddController(c *VMMController) {
+	for i, ctrlr := range d.controllers {
+		if ctrlr.Name() == c.Name() {
+			d.controllers[i] = c
+			return
+		}
+	}
+	d.controllers = append(d.controllers, c)
+}
+
+// Credentials returns the credentials of the VMM domain.
+func (d *VMMDomain) Credentials() []*VMMCredential {
+	return d.credentials
+}
+
+// Credential returns the credential with the given name,
+// or nil if there is none.
+func (d *VMMDomain) Credential(name string) *VMMCredential {
+	for _, c := range d.credentials {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// AddCredential adds a credential to the VMM domain,
+// replacing any credential of the same name.
+func (d *VMMDomain) AddCredential(c *VMMCredential) {
+	for i, cred := range d.credentials {
+		if cred.Name() == c.Name() {
+			d.credentials[i] = c
+			return
+		}
+	}
+	d.credentials = append(d.credentials, c)
+}
+
+// String returns the string representation of a VMM domain
+func (d *VMMDomain) String() string {
+	return d.name
+}
+
+// VMMController is a vCenter controller of a VMM domain, and the
+// datacenter the fabric is integrated with.
+type VMMController struct {
+	object
+	host       string
+	datacenter string
+	credential string
+}
+
+// Host returns the hostname or IP address of the controller.
+func (c *VMMController) Host() string {
+	return c.host
+}
+
+// SetHost validates and sets the hostname or IP address of the controller.
+func (c *VMMController) SetHost(host string) error {
+	if err := validateHost("host", host); err != nil {
+		return err
+	}
+	c.host = host
+	return nil
+}
+
+// Datacenter returns the name of the vCenter datacenter of the controller.
+func (c *VMMController) Datacenter() string {
+	return c.datacenter
+}
+
+// SetDatacenter validates and sets the name of the vCenter datacenter
+// of the controller, which must be exactly as it is named in vCenter.
+func (c *VMMController) SetDatacenter(datacenter string) error {
+	if datacenter == "" || len(datacenter) > 80 {
+		return fmt.Errorf("invalid datacenter: %s", datacenter)
+	}
+	c.datacenter = datacenter
+	return nil
+}
+
+// Credential returns the name of the credential the controller uses.
+func (c *VMMController) Credential() string {
+	return c.credential
+}
+
+// SetCredential validates and sets the name of the credential
+// the controller uses, one of the credentials of its VMM domain.
+func (c *VMMController) SetCredential(credential string) error {
+	if err := validateName("credential name", credential); err != nil {
+		return err
+	}
+	c.credential = credential
+	return nil
+}
+
+// String returns the string representation of a VMM controller
+func (c *VMMController) String() string {
+	return fmt.Sprintf("%s (%s)", c.name, c.host)
+}
+
+// VMMCredential is the username and password a VMM domain
+// uses to connect to its controllers.
+//
+// The APIC never returns passwords, so the password of a
+// retrieved credential is always empty.
+type VMMCredential struct {
+	object
+	username string
+	password string
+}
+
+// Username returns the username of the credential.
+func (c *VMMCredential) Username() string {
+	return c.username
+}
+
+// SetUsername validates and sets the username of the credential.
+func (c *VMMCredential) SetUsername(username string) error {
+	if username == "" || len(username) > 128 {
+		return fmt.Errorf("invalid username: %s", username)
+	}
+	c.username = username
+	return nil
+}
+
+// SetPassword sets the password of the credential.
+func (c *VMMCredential) SetPassword(password string) {
+	c.password = password
+}
+
+// String returns the string representation of a VMM credential,
+// which never includes its password.
+func (c *VMMCredential) String() string {
+	return fmt.Sprintf("%s (%s)", c.name, c.username)
+}
+
+// VirtualMachine is a virtual machine in the inventory of a VMM domain,
+// and the EPGs its virtual NICs are attached to.
+type VirtualMachine struct {
+	Name       string
+	DN         string
+	State      string
+	Domain     string
+	Controller string
+	Hypervisor string
+	// EPGs are the distinguished names of the EPGs of the VM.
+	EPGs []string
+}
+
+// String returns the string representation of a virtual machine
+func (vm *VirtualMachine) String() string {
+	return fmt.Sprintf("%s (%s/%s)", vm.Name, vm.Domain, vm.Controller)
+}
+
+// Hypervisor is a hypervisor in the inventory of a VMM domain.
+type Hypervisor struct {
+	Name       string
+	DN         string
+	State      string
+	Domain     string
+	Controller string
+}
+
+// String returns the string representation of a hypervisor
+func (hv *Hypervisor) String() string {
+	return fmt.Sprintf("%s (%s/%s)", hv.Name, hv.Domain, hv.Controller)
+}
@@ -0,0 +1,424 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// VMMContainer is a container for any object of the VMM domains
+// and their inventory. Only one of its fields is set.
+type VMMContainer struct {
+	DomP     *VMMObject `json:"vmmDomP,omitempty"`
+	CtrlrP   *VMMObject `json:"vmmCtrlrP,omitempty"`
+	UsrAccP  *VMMObject `json:"vmmUsrAccP,omitempty"`
+	RsAcc    *Relation  `json:"vmmRsAcc,omitempty"`
+	RsVlanNs *Relation  `json:"infraRsVlanNs,omitempty"`
+	CompVM   *VMMObject `json:"compVm,omitempty"`
+	CompHv   *VMMObject `json:"compHv,omitempty"`
+	CEp      *VMMObject `json:"fvCEp,omitempty"`
+	RsToVM   *Relation  `json:"fvRsToVm,omitempty"`
+	RsHyper  *Relation  `json:"fvRsHyper,omitempty"`
+}
+
+// VMMObject is any object of the VMM domains and their inventory
+type VMMObject struct {
+	VMMAttrs `json:"attributes"`
+	Children []VMMContainer `json:"children,omitempty"`
+}
+
+// VMMAttrs contains the attributes of the VMM objects
+type VMMAttrs struct {
+	Descr        string `json:"descr,omitempty"`
+	DN           string `json:"dn,omitempty"`
+	HostOrIP     string `json:"hostOrIp,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Pwd          string `json:"pwd,omitempty"`
+	RootContName string `json:"rootContName,omitempty"`
+	State        string `json:"state,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Usr          string `json:"usr,omitempty"`
+}
+
+// VMMResponse contains the response for VMM requests
+type VMMResponse struct {
+	TotalCount string         `json:"totalCount"`
+	Imdata     []VMMContainer `json:"imdata"`
+}
+
+// VMMService handles communication with the VMM domain
+// related methods of the APIC API.
+type VMMService service
+
+// vmmDomainDN returns the distinguished name of a VMware VMM domain.
+func vmmDomainDN(name string) string {
+	return fmt.Sprintf("uni/vmmp-VMware/dom-%s", name)
+}
+
+// vmmCredentialDN returns the distinguished name of a credential
+// of a VMware VMM domain.
+func vmmCredentialDN(domain, name string) string {
+	return fmt.Sprintf("%s/usracc-%s", vmmDomainDN(domain), name)
+}
+
+// NewVMMDomain instantiates a valid VMware VMM domain using
+// the given VLAN pool.
+func (s *VMMService) NewVMMDomain(name string, pool *VLANPool) (*VMMDomain, error) {
+	d := &VMMDomain{}
+
+	if err := d.SetName(name); err != nil {
+		return d, err
+	}
+
+	if err := d.SetVLANPool(pool); err != nil {
+		return d, err
+	}
+
+	return d, nil
+}
+
+// NewVMMController instantiates a valid vCenter controller of the given
+// datacenter, connecting with the named credential of its VMM domain.
+func (s *VMMService) NewVMMController(name, host, datacenter, credential string) (*VMMController, error) {
+	c := &VMMController{}
+
+	if err := c.SetName(name); err != nil {
+		return c, err
+	}
+
+	if err := c.SetHost(host); err != nil {
+		return c, err
+	}
+
+	if err := c.SetDatacenter(datacenter); err != nil {
+		return c, err
+	}
+
+	if err := c.SetCredential(credential); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// NewVMMCredential instantiates a valid VMM credential.
+func (s *VMMService) NewVMMCredential(name, username, password string) (*VMMCredential, error) {
+	c := &VMMCredential{}
+
+	if err := c.SetName(name); err != nil {
+		return c, err
+	}
+
+	if err := c.SetUsername(username); err != nil {
+		return c, err
+	}
+
+	c.SetPassword(password)
+
+	return c, nil
+}
+
+// getVMM retrieves the VMM objects of the given path.
+func (s *VMMService) getVMM(ctx context.Context, path string) ([]VMMContainer, error) {
+	var vr VMMResponse
+	if err := s.client.get(ctx, path, &vr); err != nil {
+		return nil, err
+	}
+	return vr.Imdata, nil
+}
+
+func newVMMDomainContainer(d *VMMDomain) VMMContainer {
+	o := &VMMObject{
+		VMMAttrs: VMMAttrs{
+			DN:     d.DN(),
+			Name:   d.Name(),
+			Status: d.Status(),
+		},
+	}
+
+	// children are implicitly removed with their domain
+	if d.Status() == deleted {
+		return VMMContainer{DomP: o}
+	}
+
+	pool, allocation := d.VLANPool()
+	o.Children = append(o.Children, VMMContainer{
+		RsVlanNs: newRelation(RelationAttrs{TDN: vlanPoolDN(pool, allocation)}),
+	})
+
+	for _, c := range d.Credentials() {
+		o.Children = append(o.Children, VMMContainer{
+			UsrAccP: &VMMObject{
+				VMMAttrs: VMMAttrs{
+					Name:   c.Name(),
+					Descr:  c.Description(),
+					Usr:    c.Username(),
+					Pwd:    c.password,
+					Status: c.Status(),
+				},
+			},
+		})
+	}
+
+	for _, c := range d.Controllers() {
+		ctrlr := &VMMObject{
+			VMMAttrs: VMMAttrs{
+				Name:         c.Name(),
+				Descr:        c.Description(),
+				HostOrIP:     c.Host(),
+				RootContName: c.Datacenter(),
+				Status:       c.Status(),
+			},
+		}
+		if c.Status() != deleted {
+			ctrlr.Children = append(ctrlr.Children, VMMContainer{
+				RsAcc: newRelation(RelationAttrs{TDN: vmmCredentialDN(d.Name(), c.Credential())}),
+			})
+		}
+		o.Children = append(o.Children, VMMContainer{CtrlrP: ctrlr})
+	}
+
+	return VMMContainer{DomP: o}
+}
+
+// CreateVMMDomain creates a VMM domain.
+func (s *VMMService) CreateVMMDomain(ctx context.Context, d *VMMDomain) (VMMResponse, error) {
+	d.SetCreated()
+	return s.UpdateVMMDomain(ctx, d)
+}
+
+// DeleteVMMDomain deletes a VMM domain.
+func (s *VMMService) DeleteVMMDomain(ctx context.Context, d *VMMDomain) (VMMResponse, error) {
+	d.SetDeleted()
+	return s.UpdateVMMDomain(ctx, d)
+}
+
+// UpdateVMMDomain creates, modifies or deletes a VMM domain according to
+// its status, along with each of its controllers and credentials according
+// to theirs.
+//
+// Every controller must use a credential of the VMM domain that is not
+// being deleted.
+func (s *VMMService) UpdateVMMDomain(ctx context.Context, d *VMMDomain) (VMMResponse, error) {
+	if d.Status() != deleted {
+		for _, c := range d.Controllers() {
+			if c.Status() == deleted {
+				continue
+			}
+			cred := d.Credential(c.Credential())
+			if cred == nil || cred.Status() == deleted {
+				return VMMResponse{}, fmt.Errorf("update vmm domain: controller %s: credential %s not found in %s", c.Name(), c.Credential(), d.Name())
+			}
+		}
+	}
+
+	path := fmt.Sprintf("api/node/mo/%s.json", d.DN())
+	var vr VMMResponse
+	err := s.client.post(ctx, path, newVMMDomainContainer(d), &vr)
+	return vr, err
+}
+
+// GetVMMDomain retrieves a VMM domain, with its controllers and credentials.
+func (s *VMMService) GetVMMDomain(ctx context.Context, name string) (*VMMDomain, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", vmmDomainDN(name))
+	vc, err := s.getVMM(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("get vmm domain: %v", err)
+	}
+	if len(vc) == 0 || vc[0].DomP == nil {
+		return nil, fmt.Errorf("get vmm domain: %s not found", name)
+	}
+	return vmmDomainFromResponse(vc[0].DomP), nil
+}
+
+// ListVMMDomains lists all VMware VMM domains.
+func (s *VMMService) ListVMMDomains(ctx context.Context) ([]*VMMDomain, error) {
+	path := "api/node/mo/uni/vmmp-VMware.json?query-target=children&target-subtree-class=vmmDomP&rsp-subtree=full"
+	vc, err := s.getVMM(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list vmm domains: %v", err)
+	}
+
+	var domains []*VMMDomain
+	for _, c := range vc {
+		if c.DomP != nil {
+			domains = append(domains, vmmDomainFromResponse(c.DomP))
+		}
+	}
+	return domains, nil
+}
+
+func vmmDomainFromResponse(o *VMMObject) *VMMDomain {
+	d := &VMMDomain{
+		object: object{
+			name:        o.Name,
+			description: o.Descr,
+			status:      o.Status,
+		},
+	}
+	for _, child := range o.Children {
+		switch {
+		case child.RsVlanNs != nil:
+			d.pool, d.poolAllocation = vlanPoolFromDN(child.RsVlanNs.TDN)
+		case child.UsrAccP != nil:
+			d.credentials = append(d.credentials, &VMMCredential{
+				object: object{
+					name:        child.UsrAccP.Name,
+					description: child.UsrAccP.Descr,
+					status:      child.UsrAccP.Status,
+				},
+				username: child.UsrAccP.Usr,
+			})
+		case child.CtrlrP != nil:
+			c := &VMMController{
+				object: object{
+					name:        child.CtrlrP.Name,
+					description: child.CtrlrP.Descr,
+					status:      child.CtrlrP.Status,
+				},
+				host:       child.CtrlrP.HostOrIP,
+				datacenter: child.CtrlrP.RootContName,
+			}
+			for _, cc := range child.CtrlrP.Children {
+				if cc.RsAcc != nil {
+					c.credential = strings.TrimPrefix(cc.RsAcc.TDN, vmmCredentialDN(o.Name, ""))
+				}
+			}
+			d.controllers = append(d.controllers, c)
+		}
+	}
+	return d
+}
+
+// controllerFromDN returns the names of the VMM domain and controller
+// of an inventory object from its distinguished name, such as
+// comp/prov-VMware/ctrlr-[domain]-controller/vm-vm-123.
+func controllerFromDN(dn string) (string, string) {
+	i := strings.Index(dn, "/ctrlr-[")
+	if i < 0 {
+		return "", ""
+	}
+	dn = dn[i+len("/ctrlr-["):]
+	j := strings.Index(dn, "]-")
+	if j < 0 {
+		return "", ""
+	}
+	domain, controller := dn[:j], dn[j+len("]-"):]
+	if k := strings.Index(controller, "/"); k >= 0 {
+		controller = controller[:k]
+	}
+	return domain, controller
+}
+
+// ListHypervisors lists the hypervisors in the inventory
+// of every VMware VMM domain.
+func (s *VMMService) ListHypervisors(ctx context.Context) ([]*Hypervisor, error) {
+	vc, err := s.getVMM(ctx, "api/node/class/compHv.json")
+	if err != nil {
+		return nil, fmt.Errorf("list hypervisors: %v", err)
+	}
+
+	var hvs []*Hypervisor
+	for _, c := range vc {
+		if c.CompHv == nil || !strings.HasPrefix(c.CompHv.DN, "comp/prov-VMware/") {
+			continue
+		}
+		domain, controller := controllerFromDN(c.CompHv.DN)
+		hvs = append(hvs, &Hypervisor{
+			Name:       c.CompHv.Name,
+			DN:         c.CompHv.DN,
+			State:      c.CompHv.State,
+			Domain:     domain,
+			Controller: controller,
+		})
+	}
+	return hvs, nil
+}
+
+// ListVirtualMachines lists the virtual machines in the inventory of every
+// VMware VMM domain, along with their hypervisors and the EPGs the fabric
+// has learned their endpoints in.
+func (s *VMMService) ListVirtualMachines(ctx context.Context) ([]*VirtualMachine, error) {
+	hvs, err := s.ListHypervisors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list virtual machines: %v", err)
+	}
+	hvNames := make(map[string]string)
+	for _, hv := range hvs {
+		hvNames[hv.DN] = hv.Name
+	}
+
+	vc, err := s.getVMM(ctx, "api/node/class/compVm.json")
+	if err != nil {
+		return nil, fmt.Errorf("list virtual machines: %v", err)
+	}
+	var vms []*VirtualMachine
+	byDN := make(map[string]*VirtualMachine)
+	for _, c := range vc {
+		if c.CompVM == nil || !strings.HasPrefix(c.CompVM.DN, "comp/prov-VMware/") {
+			continue
+		}
+		domain, controller := controllerFromDN(c.CompVM.DN)
+		vm := &VirtualMachine{
+			Name:       c.CompVM.Name,
+			DN:         c.CompVM.DN,
+			State:      c.CompVM.State,
+			Domain:     domain,
+			Controller: controller,
+		}
+		vms = append(vms, vm)
+		byDN[vm.DN] = vm
+	}
+
+	// the endpoints of an EPG relate to the VM and hypervisor they are on
+	path := "api/node/class/fvCEp.json?rsp-subtree=children&rsp-subtree-class=fvRsToVm,fvRsHyper"
+	vc, err = s.getVMM(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list virtual machines: %v", err)
+	}
+	for _, c := range vc {
+		if c.CEp == nil {
+			continue
+		}
+		i := strings.LastIndex(c.CEp.DN, "/cep-")
+		if i < 0 || !strings.Contains(c.CEp.DN[:i], "/epg-") {
+			continue
+		}
+		epg := c.CEp.DN[:i]
+		var vm *VirtualMachine
+		var hv string
+		for _, child := range c.CEp.Children {
+			switch {
+			case child.RsToVM != nil:
+				vm = byDN[child.RsToVM.TDN]
+			case child.RsHyper != nil:
+				hv = hvNames[child.RsHyper.TDN]
+			}
+		}
+		if vm == nil {
+			continue
+		}
+		vm.EPGs = appendName(vm.EPGs, epg)
+		if vm.Hypervisor == "" {
+			vm.Hypervisor = hv
+		}
+	}
+
+	return vms, nil
+}
+
+// VirtualMachinesByEPG returns the virtual machines of every VMware VMM
+// domain keyed by the distinguished name of each EPG they are attached to.
+func (s *VMMService) VirtualMachinesByEPG(ctx context.Context) (map[string][]*VirtualMachine, error) {
+	vms, err := s.ListVirtualMachines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byEPG := make(map[string][]*VirtualMachine)
+	for _, vm := range vms {
+		for _, epg := range vm.EPGs {
+			byEPG[epg] = append(byEPG[epg], vm)
+		}
+	}
+	return byEPG, nil
+}
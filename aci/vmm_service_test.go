@@ -0,0 +1,193 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestVMMDomain(t *testing.T, c *Client, credential string) *VMMDomain {
+	t.Helper()
+	pool, err := c.AccessPolicy.NewVLANPool("vmm", "dynamic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := c.VMM.NewVMMDomain("vds", pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := c.VMM.NewVMMCredential("admin-cred", "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrlr, err := c.VMM.NewVMMController("vc1", "10.0.0.10", "DC1", credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.AddCredential(cred)
+	d.AddController(ctrlr)
+	return d
+}
+
+func TestCreateVMMDomain(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+
+	if _, err := c.VMM.CreateVMMDomain(context.Background(), newTestVMMDomain(t, c, "admin-cred")); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted("/api/node/mo/uni/vmmp-VMware/dom-vds.json")
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"vmmDomP":{"attributes":{"dn":"uni/vmmp-VMware/dom-vds","name":"vds","status":"created,modified"},"children":[
+		{"infraRsVlanNs":{"attributes":{"tDn":"uni/infra/vlanns-[vmm]-dynamic"}}},
+		{"vmmUsrAccP":{"attributes":{"name":"admin-cred","usr":"admin","pwd":"secret"}}},
+		{"vmmCtrlrP":{"attributes":{"name":"vc1","hostOrIp":"10.0.0.10","rootContName":"DC1"},"children":[
+			{"vmmRsAcc":{"attributes":{"tDn":"uni/vmmp-VMware/dom-vds/usracc-admin-cred"}}}
+		]}}
+	]}}`)
+}
+
+func TestCreateVMMDomainMissingCredential(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+
+	_, err := c.VMM.CreateVMMDomain(context.Background(), newTestVMMDomain(t, c, "other-cred"))
+	if err == nil || !strings.Contains(err.Error(), "credential other-cred not found") {
+		t.Errorf("got error %v, want credential other-cred not found", err)
+	}
+	if n := f.postCount(); n != 0 {
+		t.Errorf("got %d posts, want none", n)
+	}
+}
+
+func TestGetVMMDomain(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/uni/vmmp-VMware/dom-vds.json": `{"totalCount":"1","imdata":[{"vmmDomP":{"attributes":{
+			"accessMode":"read-write","dn":"uni/vmmp-VMware/dom-vds","enfPref":"hw","mode":"default",
+			"name":"vds","status":""},"children":[
+			{"vmmRsDefaultLacpLagPol":{"attributes":{"tDn":"uni/infra/lacplagp-default"}}},
+			{"infraRsVlanNs":{"attributes":{"state":"formed","tCl":"fvnsVlanInstP","tDn":"uni/infra/vlanns-[vmm]-dynamic"}}},
+			{"vmmUsrAccP":{"attributes":{"descr":"","name":"admin-cred","rn":"usracc-admin-cred","usr":"admin"}}},
+			{"vmmCtrlrP":{"attributes":{"descr":"","hostOrIp":"10.0.0.10","mode":"default","name":"vc1",
+				"rn":"ctrlr-vc1","rootContName":"DC1","scope":"vm"},"children":[
+				{"vmmRsVmmCtrlrP":{"attributes":{"tDn":"uni/vmmp-VMware/dom-vds/ctrlr-vc1"}}},
+				{"vmmRsAcc":{"attributes":{"state":"formed","tCl":"vmmUsrAccP","tDn":"uni/vmmp-VMware/dom-vds/usracc-admin-cred"}}}
+			]}}
+		]}}]}`,
+	})
+
+	d, err := c.VMM.GetVMMDomain(context.Background(), "vds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool, allocation := d.VLANPool(); d.Name() != "vds" || pool != "vmm" || allocation != "dynamic" {
+		t.Errorf("got domain %s with pool %s (%s)", d.Name(), pool, allocation)
+	}
+	cred := d.Credential("admin-cred")
+	if len(d.Credentials()) != 1 || cred == nil || cred.Username() != "admin" {
+		t.Errorf("got credentials %v", d.Credentials())
+	}
+	ctrlr := d.Controller("vc1")
+	if len(d.Controllers()) != 1 || ctrlr == nil {
+		t.Fatalf("got controllers %v", d.Controllers())
+	}
+	if ctrlr.Host() != "10.0.0.10" || ctrlr.Datacenter() != "DC1" || ctrlr.Credential() != "admin-cred" {
+		t.Errorf("got controller %s", ctrlr)
+	}
+}
+
+func TestGetVMMDomainNotFound(t *testing.T) {
+	_, c := newFakeAPIC(t, nil)
+	if _, err := c.VMM.GetVMMDomain(context.Background(), "vds"); err == nil {
+		t.Error("expected an error for a missing domain")
+	}
+}
+
+// inventory is a VMM inventory with the VMware VMs web1 and db1 on host-10,
+// and a Microsoft VM and hypervisor, along with the endpoints learned
+// for web1 in the EPGs web and app and in an L2Out, and for the
+// Microsoft VM in the EPG web.
+var inventory = map[string]string{
+	"/api/node/class/compHv.json": `{"imdata":[
+		{"compHv":{"attributes":{"dn":"comp/prov-VMware/ctrlr-[vds]-vc1/hv-host-10","name":"esx1","state":"poweredOn"}}},
+		{"compHv":{"attributes":{"dn":"comp/prov-Microsoft/ctrlr-[scvmm]-sc1/hv-host-20","name":"hyperv1","state":"poweredOn"}}}
+	]}`,
+	"/api/node/class/compVm.json": `{"imdata":[
+		{"compVm":{"attributes":{"dn":"comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-101","name":"web1","state":"poweredOn"}}},
+		{"compVm":{"attributes":{"dn":"comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-102","name":"db1","state":"poweredOff"}}},
+		{"compVm":{"attributes":{"dn":"comp/prov-Microsoft/ctrlr-[scvmm]-sc1/vm-vm-201","name":"app1","state":"poweredOn"}}}
+	]}`,
+	"/api/node/class/fvCEp.json": `{"imdata":[
+		{"fvCEp":{"attributes":{"dn":"uni/tn-t1/ap-app/epg-web/cep-00:50:56:00:00:01","mac":"00:50:56:00:00:01"},"children":[
+			{"fvRsToVm":{"attributes":{"tDn":"comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-101"}}},
+			{"fvRsHyper":{"attributes":{"tDn":"comp/prov-VMware/ctrlr-[vds]-vc1/hv-host-10"}}}
+		]}},
+		{"fvCEp":{"attributes":{"dn":"uni/tn-t1/ap-app/epg-app/cep-00:50:56:00:00:02","mac":"00:50:56:00:00:02"},"children":[
+			{"fvRsHyper":{"attributes":{"tDn":"comp/prov-VMware/ctrlr-[vds]-vc1/hv-host-10"}}},
+			{"fvRsToVm":{"attributes":{"tDn":"comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-101"}}}
+		]}},
+		{"fvCEp":{"attributes":{"dn":"uni/tn-t1/l2out-ext/instP-ext/cep-00:50:56:00:00:03","mac":"00:50:56:00:00:03"},"children":[
+			{"fvRsToVm":{"attributes":{"tDn":"comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-101"}}}
+		]}},
+		{"fvCEp":{"attributes":{"dn":"uni/tn-t1/ap-app/epg-web/cep-00:15:5D:00:00:04","mac":"00:15:5D:00:00:04"},"children":[
+			{"fvRsToVm":{"attributes":{"tDn":"comp/prov-Microsoft/ctrlr-[scvmm]-sc1/vm-vm-201"}}},
+			{"fvRsHyper":{"attributes":{"tDn":"comp/prov-Microsoft/ctrlr-[scvmm]-sc1/hv-host-20"}}}
+		]}}
+	]}`,
+}
+
+func TestListVirtualMachines(t *testing.T) {
+	_, c := newFakeAPIC(t, inventory)
+
+	vms, err := c.VMM.ListVirtualMachines(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*VirtualMachine{
+		{
+			Name:       "web1",
+			DN:         "comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-101",
+			State:      "poweredOn",
+			Domain:     "vds",
+			Controller: "vc1",
+			Hypervisor: "esx1",
+			EPGs:       []string{"uni/tn-t1/ap-app/epg-web", "uni/tn-t1/ap-app/epg-app"},
+		},
+		{
+			Name:       "db1",
+			DN:         "comp/prov-VMware/ctrlr-[vds]-vc1/vm-vm-102",
+			State:      "poweredOff",
+			Domain:     "vds",
+			Controller: "vc1",
+		},
+	}
+	if !reflect.DeepEqual(vms, want) {
+		t.Errorf("got %d virtual machines:", len(vms))
+		for _, vm := range vms {
+			t.Errorf("%+v", *vm)
+		}
+	}
+}
+
+func TestVirtualMachinesByEPG(t *testing.T) {
+	_, c := newFakeAPIC(t, inventory)
+
+	byEPG, err := c.VMM.VirtualMachinesByEPG(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string][]string)
+	for epg, vms := range byEPG {
+		for _, vm := range vms {
+			got[epg] = append(got[epg], vm.Name)
+		}
+	}
+	want := map[string][]string{
+		"uni/tn-t1/ap-app/epg-web": {"web1"},
+		"uni/tn-t1/ap-app/epg-app": {"web1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,269 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ProtPolContainer is a container for a vPC protection policy
+type ProtPolContainer struct {
+	ProtPol `json:"fabricProtPol"`
+}
+
+// ProtPol is the vPC protection policy, the parent of
+// all explicit protection groups.
+type ProtPol struct {
+	VPCAttrs `json:"attributes"`
+	Children []ExplicitGEpContainer `json:"children,omitempty"`
+}
+
+// ExplicitGEpContainer is a container for an explicit protection group
+type ExplicitGEpContainer struct {
+	ExplicitGEp `json:"fabricExplicitGEp"`
+}
+
+// ExplicitGEp is a vPC explicit protection group
+type ExplicitGEp struct {
+	VPCAttrs `json:"attributes"`
+	Children []NodePEpContainer `json:"children,omitempty"`
+}
+
+// NodePEpContainer is a container for a protection group node
+type NodePEpContainer struct {
+	NodePEp `json:"fabricNodePEp"`
+}
+
+// NodePEp is a node member of an explicit protection group
+type NodePEp struct {
+	VPCAttrs `json:"attributes"`
+}
+
+// VPCAttrs contains the attributes of the vPC protection policy objects
+type VPCAttrs struct {
+	DN        string `json:"dn,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	PodID     string `json:"podId,omitempty"`
+	PeerIP    string `json:"peerIp,omitempty"`
+	RN        string `json:"rn,omitempty"`
+	Status    string `json:"status,omitempty"`
+	VirtualIP string `json:"virtualIp,omitempty"`
+}
+
+// VPCResponse contains the response for vPC protection group requests
+type VPCResponse struct {
+	Imdata []ExplicitGEpContainer `json:"imdata"`
+}
+
+// VPCDomainStatus is the operational status of one node of a vPC domain,
+// as reported by its vpcDom object.
+type VPCDomainStatus struct {
+	NodeID       string
+	VirtualIP    string
+	PeerIP       string
+	PeerState    string
+	OperRole     string
+	DualActiveSt string
+}
+
+// vpcDomAttrs contains the attributes of a vpcDom response
+type vpcDomAttrs struct {
+	DN           string `json:"dn"`
+	ID           string `json:"id"`
+	VirtualIP    string `json:"virtualIp"`
+	PeerIP       string `json:"peerIp"`
+	PeerSt       string `json:"peerSt"`
+	OperRole     string `json:"operRole"`
+	DualActiveSt string `json:"dualActiveSt"`
+}
+
+// VPCService handles communication with the vPC protection group related
+// methods of the APIC API.
+type VPCService service
+
+// NewPair instantiates a valid vPC pair of two leaf nodes.
+func (s *VPCService) NewPair(name, id string, a, b *Node) (*VPCPair, error) {
+	pair := &VPCPair{}
+
+	if err := pair.SetName(name); err != nil {
+		return pair, err
+	}
+
+	if err := pair.SetID(id); err != nil {
+		return pair, err
+	}
+
+	if err := pair.SetNodes(a, b); err != nil {
+		return pair, err
+	}
+
+	return pair, nil
+}
+
+// Create creates the vPC protection groups of the given pairs.
+func (s *VPCService) Create(ctx context.Context, pairs ...*VPCPair) (VPCResponse, error) {
+	for _, pair := range pairs {
+		pair.SetCreated()
+	}
+	return s.Update(ctx, pairs...)
+}
+
+// Delete deletes the vPC protection groups of the given pairs.
+func (s *VPCService) Delete(ctx context.Context, pairs ...*VPCPair) (VPCResponse, error) {
+	for _, pair := range pairs {
+		pair.SetDeleted()
+	}
+	return s.Update(ctx, pairs...)
+}
+
+// Update creates or deletes vPC protection groups according to each pair's status.
+func (s *VPCService) Update(ctx context.Context, pairs ...*VPCPair) (VPCResponse, error) {
+
+	path := "api/node/mo/uni/fabric/protpol.json"
+	payload := newProtPolContainer(pairs)
+
+	var vr VPCResponse
+
+	req, err := s.client.NewRequest(http.MethodPost, path, payload)
+	if err != nil {
+		return vr, err
+	}
+
+	_, err = s.client.Do(ctx, req, &vr)
+	return vr, err
+}
+
+func newProtPolContainer(pairs []*VPCPair) ProtPolContainer {
+
+	var children []ExplicitGEpContainer
+
+	for _, pair := range pairs {
+		rn := fmt.Sprintf("expgep-%s", pair.Name())
+		group := ExplicitGEpContainer{
+			ExplicitGEp: ExplicitGEp{
+				VPCAttrs: VPCAttrs{
+					DN:     fmt.Sprintf("uni/fabric/protpol/%s", rn),
+					RN:     rn,
+					Name:   pair.Name(),
+					ID:     pair.ID(),
+					Status: pair.Status(),
+				},
+			},
+		}
+		// children are implicitly removed with their group
+		if pair.Status() != deleted {
+			for _, node := range pair.nodes {
+				if node == nil {
+					continue
+				}
+				group.Children = append(group.Children, NodePEpContainer{
+					NodePEp: NodePEp{
+						VPCAttrs: VPCAttrs{
+							ID:    node.ID(),
+							PodID: node.Pod(),
+							RN:    fmt.Sprintf("nodepep-%s", node.ID()),
+						},
+					},
+				})
+			}
+		}
+		children = append(children, group)
+	}
+
+	return ProtPolContainer{
+		ProtPol: ProtPol{
+			VPCAttrs: VPCAttrs{
+				Status: createdModified,
+			},
+			Children: children,
+		},
+	}
+}
+
+// List lists all vPC protection groups of the ACI fabric
+func (s *VPCService) List(ctx context.Context) ([]*VPCPair, error) {
+
+	path := "api/node/class/fabricExplicitGEp.json?rsp-subtree=children&rsp-subtree-class=fabricNodePEp"
+
+	var vr VPCResponse
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	_, err = s.client.Do(ctx, req, &vr)
+	if err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var pairs []*VPCPair
+	for _, g := range vr.Imdata {
+		// as with fabric nodes, we trust the APIC to
+		// have already validated the protection groups.
+		pair := &VPCPair{
+			name:      g.Name,
+			id:        g.ID,
+			status:    g.Status,
+			virtualIP: g.VirtualIP,
+		}
+		for i, c := range g.Children {
+			if i >= len(pair.nodes) {
+				break
+			}
+			pair.nodes[i] = &Node{
+				id:   c.ID,
+				pod:  c.PodID,
+				role: "leaf",
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// DomainStatus reads the operational status of the vPC domain of a pair
+// from the vpcDom object of each of its nodes.
+func (s *VPCService) DomainStatus(ctx context.Context, pair *VPCPair) ([]VPCDomainStatus, error) {
+
+	var statuses []VPCDomainStatus
+
+	for _, node := range pair.nodes {
+		if node == nil {
+			continue
+		}
+		path := fmt.Sprintf("api/node/mo/topology/pod-%s/node-%s/sys/vpc/inst/dom-%s.json", node.Pod(), node.ID(), pair.ID())
+
+		req, err := s.client.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("domain status: %v", err)
+		}
+
+		// structure of expected response
+		var vr struct {
+			Imdata []struct {
+				VPCDom struct {
+					vpcDomAttrs `json:"attributes"`
+				} `json:"vpcDom"`
+			} `json:"imdata"`
+		}
+
+		_, err = s.client.Do(ctx, req, &vr)
+		if err != nil {
+			return nil, fmt.Errorf("domain status: %v", err)
+		}
+
+		status := VPCDomainStatus{NodeID: node.ID()}
+		if len(vr.Imdata) > 0 {
+			dom := vr.Imdata[0].VPCDom
+			status.VirtualIP = dom.VirtualIP
+			status.PeerIP = dom.PeerIP
+			status.PeerState = dom.PeerSt
+			status.OperRole = dom.OperRole
+			status.DualActiveSt = dom.DualActiveSt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,128 @@
+package aci
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// VPCPair is a vPC explicit protection group, pairing
+// two leaf nodes into a vPC domain.
+type VPCPair struct {
+	name   string
+	id     string
+	nodes  [2]*Node
+	status string
+
+	// virtualIP is the vPC virtual IP as reported by the APIC.
+	virtualIP string
+}
+
+// Name returns the name of the protection group.
+func (p *VPCPair) Name() string {
+	return p.name
+}
+
+// SetName validates and sets the name of the protection group.
+//
+// A protection group name can be up to 64 characters and must begin
+// with an alphanumeric character. It can only contain alphanumeric
+// characters and the following symbols: -.:_
+func (p *VPCPair) SetName(name string) error {
+	valid := regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-.:_]{0,63}$`)
+	if !valid.MatchString(name) {
+		return fmt.Errorf("invalid name: %s", name)
+	}
+	p.name = name
+	return nil
+}
+
+// ID returns the logical pair ID of the protection group.
+func (p *VPCPair) ID() string {
+	return p.id
+}
+
+// SetID validates and sets the logical pair ID of the protection group.
+//
+// A logical pair ID must be a number between 1 and 1000 inclusive.
+func (p *VPCPair) SetID(id string) error {
+	idN, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid logical pair id: %s", id)
+	}
+	if idN < 1 || idN > 1000 {
+		return fmt.Errorf("invalid logical pair id: %s", id)
+	}
+	p.id = id
+	return nil
+}
+
+// Nodes returns the two nodes of the protection group.
+func (p *VPCPair) Nodes() (*Node, *Node) {
+	return p.nodes[0], p.nodes[1]
+}
+
+// SetNodes validates and sets the two nodes of the protection group.
+//
+// Both nodes must be distinct leaves in the same pod.
+func (p *VPCPair) SetNodes(a, b *Node) error {
+	if a == nil || b == nil {
+		return fmt.Errorf("invalid vpc pair: two nodes are required")
+	}
+	for _, n := range []*Node{a, b} {
+		if n.Role() != "leaf" {
+			return fmt.Errorf("invalid vpc pair: node %s is not a leaf", n.ID())
+		}
+	}
+	if a.ID() == b.ID() {
+		return fmt.Errorf("invalid vpc pair: node %s is paired with itself", a.ID())
+	}
+	if a.Pod() != b.Pod() {
+		return fmt.Errorf("invalid vpc pair: nodes %s and %s are in different pods", a.ID(), b.ID())
+	}
+	p.nodes = [2]*Node{a, b}
+	return nil
+}
+
+// Pod returns the id of the pod the protection group is in.
+func (p *VPCPair) Pod() string {
+	if p.nodes[0] == nil {
+		return ""
+	}
+	return p.nodes[0].Pod()
+}
+
+// VirtualIP returns the virtual IP of the vPC domain,
+// as last reported by the APIC.
+func (p *VPCPair) VirtualIP() string {
+	return p.virtualIP
+}
+
+// SetCreated sets the status of the protection group to "created,modified".
+func (p *VPCPair) SetCreated() string {
+	p.status = createdModified
+	return p.status
+}
+
+// SetDeleted sets the status of the protection group to "deleted".
+func (p *VPCPair) SetDeleted() string {
+	p.status = deleted
+	return p.status
+}
+
+// Status returns the status of the protection group.
+func (p *VPCPair) Status() string {
+	return p.status
+}
+
+// String returns the string representation of a vPC pair
+func (p *VPCPair) String() string {
+	var a, b string
+	if p.nodes[0] != nil {
+		a = p.nodes[0].ID()
+	}
+	if p.nodes[1] != nil {
+		b = p.nodes[1].ID()
+	}
+	return fmt.Sprintf("%s %s %s-%s", p.name, p.id, a, b)
+}
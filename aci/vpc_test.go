@@ -0,0 +1,124 @@
+package aci
+
+import (
+	"context"
+	"testing"
+)
+
+const protPolPath = "/api/node/mo/uni/fabric/protpol.json"
+
+func TestNewPair(t *testing.T) {
+	leaf101 := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	leaf102 := newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf")
+	leaf201 := newTestNode(t, "leaf-201", "201", "2", "FDO3", "leaf")
+	spine := newTestNode(t, "spine-301", "301", "1", "FDO4", "spine")
+
+	tests := []struct {
+		name  string
+		id    string
+		a, b  *Node
+		valid bool
+	}{
+		{"vpc-101-102", "101", leaf101, leaf102, true},
+		{"-vpc", "101", leaf101, leaf102, false},
+		{"vpc-101-102", "0", leaf101, leaf102, false},
+		{"vpc-101-102", "1001", leaf101, leaf102, false},
+		{"vpc-101-102", "one", leaf101, leaf102, false},
+		{"vpc-101", "101", leaf101, nil, false},
+		{"vpc-101-101", "101", leaf101, leaf101, false},
+		{"vpc-101-201", "101", leaf101, leaf201, false},
+		{"vpc-101-301", "101", leaf101, spine, false},
+	}
+	s := &VPCService{}
+	for _, tt := range tests {
+		_, err := s.NewPair(tt.name, tt.id, tt.a, tt.b)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%s %s: got valid %t, want %t (%v)", tt.name, tt.id, valid, tt.valid, err)
+		}
+	}
+}
+
+func TestVPCUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	a := newTestNode(t, "leaf-201", "201", "2", "FDO1", "leaf")
+	b := newTestNode(t, "leaf-202", "202", "2", "FDO2", "leaf")
+	pair, err := c.VPC.NewPair("vpc-201-202", "201", a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.VPC.Create(context.Background(), pair); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VPC.Delete(context.Background(), pair); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := f.posted(protPolPath)
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"fabricProtPol":{"attributes":{"status":"created,modified"},"children":[
+		{"fabricExplicitGEp":{"attributes":{"dn":"uni/fabric/protpol/expgep-vpc-201-202","id":"201","name":"vpc-201-202","rn":"expgep-vpc-201-202","status":"created,modified"},"children":[
+			{"fabricNodePEp":{"attributes":{"id":"201","podId":"2","rn":"nodepep-201"}}},
+			{"fabricNodePEp":{"attributes":{"id":"202","podId":"2","rn":"nodepep-202"}}}
+		]}}
+	]}}`)
+	jsonEqual(t, posts[1], `{"fabricProtPol":{"attributes":{"status":"created,modified"},"children":[
+		{"fabricExplicitGEp":{"attributes":{"dn":"uni/fabric/protpol/expgep-vpc-201-202","id":"201","name":"vpc-201-202","rn":"expgep-vpc-201-202","status":"deleted"}}}
+	]}}`)
+}
+
+func TestVPCList(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/class/fabricExplicitGEp.json": `{"imdata":[{"fabricExplicitGEp":{"attributes":{"id":"101","name":"vpc-101-102","virtualIp":"10.0.0.1/32"},"children":[
+			{"fabricNodePEp":{"attributes":{"id":"101","podId":"1"}}},
+			{"fabricNodePEp":{"attributes":{"id":"102","podId":"1"}}}
+		]}}]}`,
+	})
+
+	pairs, err := c.VPC.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 {
+		t.Fatalf("got %d pairs, want 1", len(pairs))
+	}
+	p := pairs[0]
+	a, b := p.Nodes()
+	if p.Name() != "vpc-101-102" || p.ID() != "101" || p.VirtualIP() != "10.0.0.1/32" || p.Pod() != "1" {
+		t.Errorf("got pair %s id %s virtual ip %s pod %s", p.Name(), p.ID(), p.VirtualIP(), p.Pod())
+	}
+	if a == nil || b == nil || a.ID() != "101" || b.ID() != "102" {
+		t.Errorf("got nodes %v and %v, want 101 and 102", a, b)
+	}
+}
+
+func TestVPCDomainStatus(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		"/api/node/mo/topology/pod-1/node-101/sys/vpc/inst/dom-101.json": `{"imdata":[{"vpcDom":{"attributes":{"virtualIp":"10.0.0.1/32","peerIp":"10.0.0.2/32","peerSt":"up","operRole":"primary","dualActiveSt":"no"}}}]}`,
+	})
+	a := newTestNode(t, "leaf-101", "101", "1", "FDO1", "leaf")
+	b := newTestNode(t, "leaf-102", "102", "1", "FDO2", "leaf")
+	pair, err := c.VPC.NewPair("vpc-101-102", "101", a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := c.VPC.DomainStatus(context.Background(), pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []VPCDomainStatus{
+		{NodeID: "101", VirtualIP: "10.0.0.1/32", PeerIP: "10.0.0.2/32", PeerState: "up", OperRole: "primary", DualActiveSt: "no"},
+		{NodeID: "102"},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(want))
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("got status %+v, want %+v", statuses[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package aci
+
+import "fmt"
+
+// VRF is a tenant VRF, also known as a private network or context.
+type VRF struct {
+	object
+	tenant          string
+	enforcement     string
+	direction       string
+	bdEnforced      bool
+	provided        []string
+	consumed        []string
+	routeTagPolicy  string
+	bgpTimersPolicy string
+
+	// relations removed from the VRF,
+	// deleted whenever it is updated
+	unprovided             []string
+	unconsumed             []string
+	routeTagPolicyRemoved  bool
+	bgpTimersPolicyRemoved bool
+}
+
+// Tenant returns the name of the tenant the VRF belongs to.
+func (v *VRF) Tenant() string {
+	return v.tenant
+}
+
+// Enforcement returns the policy control enforcement preference of the VRF.
+func (v *VRF) Enforcement() string {
+	return v.enforcement
+}
+
+// SetEnforcement sets the policy control enforcement preference of the VRF.
+// Can only be "enforced" or "unenforced"
+func (v *VRF) SetEnforcement(enforcement string) error {
+	if enforcement != "enforced" && enforcement != "unenforced" {
+		return fmt.Errorf("invalid enforcement preference: %s", enforcement)
+	}
+	v.enforcement = enforcement
+	return nil
+}
+
+// EnforcementDirection returns the policy control enforcement direction of the VRF.
+func (v *VRF) EnforcementDirection() string {
+	return v.direction
+}
+
+// SetEnforcementDirection sets the policy control enforcement direction of the VRF.
+// Can only be "ingress" or "egress"
+func (v *VRF) SetEnforcementDirection(direction string) error {
+	if direction != "ingress" && direction != "egress" {
+		return fmt.Errorf("invalid enforcement direction: %s", direction)
+	}
+	v.direction = direction
+	return nil
+}
+
+// BDEnforced returns whether bridge domain enforcement is enabled for the VRF.
+func (v *VRF) BDEnforced() bool {
+	return v.bdEnforced
+}
+
+// SetBDEnforced sets whether bridge domain enforcement is enabled for the VRF.
+func (v *VRF) SetBDEnforced(enforced bool) {
+	v.bdEnforced = enforced
+}
+
+// ProvidedContracts returns the contracts provided by every EPG
+// of the VRF, through its vzAny.
+func (v *VRF) ProvidedContracts() []string {
+	return v.provided
+}
+
+// ProvideContract validates and adds a contract to be provided by
+// every EPG of the VRF, through its vzAny.
+func (v *VRF) ProvideContract(contract string) error {
+	if err := validateName("contract name", contract); err != nil {
+		return err
+	}
+	v.provided = appendName(v.provided, contract)
+	v.unprovided = removeName(v.unprovided, contract)
+	return nil
+}
+
+// RemoveProvidedContract removes a contract provided through the vzAny
+// of the VRF.
+func (v *VRF) RemoveProvidedContract(contract string) {
+	for _, c := range v.provided {
+		if c == contract {
+			v.provided = removeName(v.provided, contract)
+			v.unprovided = appendName(v.unprovided, contract)
+			return
+		}
+	}
+}
+
+// ConsumedContracts returns the contracts consumed by every EPG
+// of the VRF, through its vzAny.
+func (v *VRF) ConsumedContracts() []string {
+	return v.consumed
+}
+
+// ConsumeContract validates and adds a contract to be consumed by
+// every EPG of the VRF, through its vzAny.
+func (v *VRF) ConsumeContract(contract string) error {
+	if err := validateName("contract name", contract); err != nil {
+		return err
+	}
+	v.consumed = appendName(v.consumed, contract)
+	v.unconsumed = removeName(v.unconsumed, contract)
+	return nil
+}
+
+// RemoveConsumedContract removes a contract consumed through the vzAny
+// of the VRF.
+func (v *VRF) RemoveConsumedContract(contract string) {
+	for _, c := range v.consumed {
+		if c == contract {
+			v.consumed = removeName(v.consumed, contract)
+			v.unconsumed = appendName(v.unconsumed, contract)
+			return
+		}
+	}
+}
+
+// RouteTagPolicy returns the name of the route tag policy of the VRF.
+func (v *VRF) RouteTagPolicy() string {
+	return v.routeTagPolicy
+}
+
+// SetRouteTagPolicy validates and sets the name of the route tag policy of the VRF.
+func (v *VRF) SetRouteTagPolicy(policy string) error {
+	if err := validateName("route tag policy name", policy); err != nil {
+		return err
+	}
+	v.routeTagPolicy = policy
+	v.routeTagPolicyRemoved = false
+	return nil
+}
+
+// RemoveRouteTagPolicy removes the route tag policy of the VRF,
+// so that the default policy applies.
+func (v *VRF) RemoveRouteTagPolicy() {
+	if v.routeTagPolicy == "" {
+		return
+	}
+	v.routeTagPolicy = ""
+	v.routeTagPolicyRemoved = true
+}
+
+// BGPTimersPolicy returns the name of the BGP timers policy of the VRF.
+func (v *VRF) BGPTimersPolicy() string {
+	return v.bgpTimersPolicy
+}
+
+// SetBGPTimersPolicy validates and sets the name of the BGP timers policy of the VRF.
+func (v *VRF) SetBGPTimersPolicy(policy string) error {
+	if err := validateName("bgp timers policy name", policy); err != nil {
+		return err
+	}
+	v.bgpTimersPolicy = policy
+	v.bgpTimersPolicyRemoved = false
+	return nil
+}
+
+// RemoveBGPTimersPolicy removes the BGP timers policy of the VRF,
+// so that the default policy applies.
+func (v *VRF) RemoveBGPTimersPolicy() {
+	if v.bgpTimersPolicy == "" {
+		return
+	}
+	v.bgpTimersPolicy = ""
+	v.bgpTimersPolicyRemoved = true
+}
+
+// String returns the string representation of a VRF
+func (v *VRF) String() string {
+	return fmt.Sprintf("%s/%s", v.tenant, v.name)
+}
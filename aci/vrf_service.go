@@ -0,0 +1,241 @@
+package aci
+
+import (
+	"context"
+	"fmt"
+)
+
+// VRFContainer is a container for a VRF
+type VRFContainer struct {
+	FvCtx `json:"fvCtx"`
+}
+
+// FvCtx is a VRF
+type FvCtx struct {
+	VRFAttrs `json:"attributes"`
+	Children []VRFChild `json:"children,omitempty"`
+}
+
+// VRFAttrs contains the attributes of a VRF
+type VRFAttrs struct {
+	BDEnforcedEnable string `json:"bdEnforcedEnable,omitempty"`
+	Descr            string `json:"descr,omitempty"`
+	DN               string `json:"dn,omitempty"`
+	Name             string `json:"name,omitempty"`
+	PcEnfDir         string `json:"pcEnfDir,omitempty"`
+	PcEnfPref        string `json:"pcEnfPref,omitempty"`
+	RN               string `json:"rn,omitempty"`
+	Status           string `json:"status,omitempty"`
+}
+
+// VRFChild is a child of a VRF. Only one of its fields is set.
+type VRFChild struct {
+	VzAny                 *VzAny    `json:"vzAny,omitempty"`
+	RsCtxToExtRouteTagPol *Relation `json:"fvRsCtxToExtRouteTagPol,omitempty"`
+	RsBgpCtxPol           *Relation `json:"fvRsBgpCtxPol,omitempty"`
+}
+
+// VzAny is the collection of every EPG of a VRF
+type VzAny struct {
+	RelationAttrs `json:"attributes"`
+	Children      []VzAnyChild `json:"children,omitempty"`
+}
+
+// VzAnyChild is a contract relation of a vzAny. Only one of its fields is set.
+type VzAnyChild struct {
+	RsAnyToProv *Relation `json:"vzRsAnyToProv,omitempty"`
+	RsAnyToCons *Relation `json:"vzRsAnyToCons,omitempty"`
+}
+
+// VRFResponse contains the response for VRF requests
+type VRFResponse struct {
+	TotalCount string         `json:"totalCount"`
+	Imdata     []VRFContainer `json:"imdata"`
+}
+
+// VRFService handles communication with the VRF related
+// methods of the APIC API.
+type VRFService service
+
+// vrfDN returns the distinguished name of a VRF.
+func vrfDN(tenant, vrf string) string {
+	return fmt.Sprintf("%s/ctx-%s", tenantDN(tenant), vrf)
+}
+
+// yesNo returns the APIC representation of a boolean attribute.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// NewVRF instantiates a valid VRF within a tenant, enforcing
+// policy in the ingress direction.
+func (s *VRFService) NewVRF(tenant, name string) (*VRF, error) {
+	vrf := &VRF{enforcement: "enforced", direction: "ingress"}
+
+	if err := validateName("tenant name", tenant); err != nil {
+		return vrf, err
+	}
+	vrf.tenant = tenant
+
+	if err := vrf.SetName(name); err != nil {
+		return vrf, err
+	}
+
+	return vrf, nil
+}
+
+func newVRFContainer(vrf *VRF) VRFContainer {
+	c := VRFContainer{
+		FvCtx: FvCtx{
+			VRFAttrs: VRFAttrs{
+				DN:               vrfDN(vrf.Tenant(), vrf.Name()),
+				Name:             vrf.Name(),
+				Descr:            vrf.Description(),
+				PcEnfPref:        vrf.Enforcement(),
+				PcEnfDir:         vrf.EnforcementDirection(),
+				BDEnforcedEnable: yesNo(vrf.BDEnforced()),
+				Status:           vrf.Status(),
+			},
+		},
+	}
+
+	// children are implicitly removed with their VRF
+	if vrf.Status() == deleted {
+		return c
+	}
+
+	vzAny := &VzAny{}
+	for _, contract := range vrf.ProvidedContracts() {
+		vzAny.Children = append(vzAny.Children, VzAnyChild{
+			RsAnyToProv: newRelation(RelationAttrs{TnVzBrCPName: contract}),
+		})
+	}
+	for _, contract := range vrf.ConsumedContracts() {
+		vzAny.Children = append(vzAny.Children, VzAnyChild{
+			RsAnyToCons: newRelation(RelationAttrs{TnVzBrCPName: contract}),
+		})
+	}
+	for _, contract := range vrf.unprovided {
+		vzAny.Children = append(vzAny.Children, VzAnyChild{
+			RsAnyToProv: newRelation(RelationAttrs{TnVzBrCPName: contract, Status: deleted}),
+		})
+	}
+	for _, contract := range vrf.unconsumed {
+		vzAny.Children = append(vzAny.Children, VzAnyChild{
+			RsAnyToCons: newRelation(RelationAttrs{TnVzBrCPName: contract, Status: deleted}),
+		})
+	}
+	if len(vzAny.Children) > 0 {
+		c.Children = append(c.Children, VRFChild{VzAny: vzAny})
+	}
+
+	switch {
+	case vrf.RouteTagPolicy() != "":
+		c.Children = append(c.Children, VRFChild{
+			RsCtxToExtRouteTagPol: newRelation(RelationAttrs{TnL3extRouteTagPolName: vrf.RouteTagPolicy()}),
+		})
+	case vrf.routeTagPolicyRemoved:
+		c.Children = append(c.Children, VRFChild{
+			RsCtxToExtRouteTagPol: newRelation(RelationAttrs{Status: deleted}),
+		})
+	}
+	switch {
+	case vrf.BGPTimersPolicy() != "":
+		c.Children = append(c.Children, VRFChild{
+			RsBgpCtxPol: newRelation(RelationAttrs{TnBgpCtxPolName: vrf.BGPTimersPolicy()}),
+		})
+	case vrf.bgpTimersPolicyRemoved:
+		c.Children = append(c.Children, VRFChild{
+			RsBgpCtxPol: newRelation(RelationAttrs{Status: deleted}),
+		})
+	}
+
+	return c
+}
+
+// Create creates a VRF.
+func (s *VRFService) Create(ctx context.Context, vrf *VRF) (VRFResponse, error) {
+	vrf.SetCreated()
+	return s.Update(ctx, vrf)
+}
+
+// Delete deletes a VRF.
+func (s *VRFService) Delete(ctx context.Context, vrf *VRF) (VRFResponse, error) {
+	vrf.SetDeleted()
+	return s.Update(ctx, vrf)
+}
+
+// Update creates, modifies or deletes a VRF according to its status.
+func (s *VRFService) Update(ctx context.Context, vrf *VRF) (VRFResponse, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json", vrfDN(vrf.Tenant(), vrf.Name()))
+	payload := newVRFContainer(vrf)
+
+	var vr VRFResponse
+	err := s.client.post(ctx, path, payload, &vr)
+	return vr, err
+}
+
+// Get retrieves a VRF of a tenant.
+func (s *VRFService) Get(ctx context.Context, tenant, name string) (*VRF, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?rsp-subtree=full", vrfDN(tenant, name))
+
+	var vr VRFResponse
+	if err := s.client.get(ctx, path, &vr); err != nil {
+		return nil, fmt.Errorf("get vrf: %v", err)
+	}
+	if len(vr.Imdata) == 0 {
+		return nil, fmt.Errorf("get vrf: %s/%s not found", tenant, name)
+	}
+	return vrfFromResponse(tenant, vr.Imdata[0]), nil
+}
+
+// List lists all VRFs of a tenant.
+func (s *VRFService) List(ctx context.Context, tenant string) ([]*VRF, error) {
+	path := fmt.Sprintf("api/node/mo/%s.json?query-target=children&target-subtree-class=fvCtx&rsp-subtree=full", tenantDN(tenant))
+
+	var vr VRFResponse
+	if err := s.client.get(ctx, path, &vr); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+
+	var vrfs []*VRF
+	for _, v := range vr.Imdata {
+		vrfs = append(vrfs, vrfFromResponse(tenant, v))
+	}
+	return vrfs, nil
+}
+
+func vrfFromResponse(tenant string, v VRFContainer) *VRF {
+	vrf := &VRF{
+		object: object{
+			name:        v.Name,
+			description: v.Descr,
+			status:      v.Status,
+		},
+		tenant:      tenant,
+		enforcement: v.PcEnfPref,
+		direction:   v.PcEnfDir,
+		bdEnforced:  v.BDEnforcedEnable == "yes",
+	}
+	for _, c := range v.Children {
+		switch {
+		case c.VzAny != nil:
+			for _, r := range c.VzAny.Children {
+				switch {
+				case r.RsAnyToProv != nil:
+					vrf.provided = appendName(vrf.provided, r.RsAnyToProv.TnVzBrCPName)
+				case r.RsAnyToCons != nil:
+					vrf.consumed = appendName(vrf.consumed, r.RsAnyToCons.TnVzBrCPName)
+				}
+			}
+		case c.RsCtxToExtRouteTagPol != nil:
+			vrf.routeTagPolicy = c.RsCtxToExtRouteTagPol.TnL3extRouteTagPolName
+		case c.RsBgpCtxPol != nil:
+			vrf.bgpTimersPolicy = c.RsBgpCtxPol.TnBgpCtxPolName
+		}
+	}
+	return vrf
+}
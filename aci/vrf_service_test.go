@@ -0,0 +1,126 @@
+package aci
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const vrfPath = "/api/node/mo/uni/tn-t1/ctx-v1.json"
+
+func TestVRFUpdate(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	vrf, err := c.VRF.NewVRF("t1", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range []error{
+		vrf.ProvideContract("web"),
+		vrf.ProvideContract("db"),
+		vrf.ConsumeContract("dns"),
+		vrf.SetRouteTagPolicy("tags"),
+		vrf.SetBGPTimersPolicy("timers"),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.VRF.Create(context.Background(), vrf); err != nil {
+		t.Fatal(err)
+	}
+
+	vrf.RemoveProvidedContract("web")
+	vrf.RemoveConsumedContract("dns")
+	vrf.RemoveRouteTagPolicy()
+	vrf.RemoveBGPTimersPolicy()
+	if _, err := c.VRF.Update(context.Background(), vrf); err != nil {
+		t.Fatal(err)
+	}
+
+	// a removed contract provided again is no longer deleted
+	if err := vrf.ProvideContract("web"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VRF.Update(context.Background(), vrf); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := f.posted(vrfPath)
+	if len(posts) != 3 {
+		t.Fatalf("got %d posts, want 3", len(posts))
+	}
+	attrs := `"attributes":{"dn":"uni/tn-t1/ctx-v1","name":"v1","pcEnfPref":"enforced","pcEnfDir":"ingress","bdEnforcedEnable":"no","status":"created,modified"}`
+	jsonEqual(t, posts[0], `{"fvCtx":{`+attrs+`,"children":[
+		{"vzAny":{"attributes":{},"children":[
+			{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"web"}}},
+			{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"db"}}},
+			{"vzRsAnyToCons":{"attributes":{"tnVzBrCPName":"dns"}}}
+		]}},
+		{"fvRsCtxToExtRouteTagPol":{"attributes":{"tnL3extRouteTagPolName":"tags"}}},
+		{"fvRsBgpCtxPol":{"attributes":{"tnBgpCtxPolName":"timers"}}}
+	]}}`)
+	jsonEqual(t, posts[1], `{"fvCtx":{`+attrs+`,"children":[
+		{"vzAny":{"attributes":{},"children":[
+			{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"db"}}},
+			{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"web","status":"deleted"}}},
+			{"vzRsAnyToCons":{"attributes":{"tnVzBrCPName":"dns","status":"deleted"}}}
+		]}},
+		{"fvRsCtxToExtRouteTagPol":{"attributes":{"status":"deleted"}}},
+		{"fvRsBgpCtxPol":{"attributes":{"status":"deleted"}}}
+	]}}`)
+	jsonEqual(t, posts[2], `{"fvCtx":{`+attrs+`,"children":[
+		{"vzAny":{"attributes":{},"children":[
+			{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"db"}}},
+			{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"web"}}},
+			{"vzRsAnyToCons":{"attributes":{"tnVzBrCPName":"dns","status":"deleted"}}}
+		]}},
+		{"fvRsCtxToExtRouteTagPol":{"attributes":{"status":"deleted"}}},
+		{"fvRsBgpCtxPol":{"attributes":{"status":"deleted"}}}
+	]}}`)
+}
+
+func TestVRFDelete(t *testing.T) {
+	f, c := newFakeAPIC(t, nil)
+	vrf, err := c.VRF.NewVRF("t1", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vrf.ProvideContract("web"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VRF.Delete(context.Background(), vrf); err != nil {
+		t.Fatal(err)
+	}
+	posts := f.posted(vrfPath)
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+	jsonEqual(t, posts[0], `{"fvCtx":{"attributes":{"dn":"uni/tn-t1/ctx-v1","name":"v1","pcEnfPref":"enforced","pcEnfDir":"ingress","bdEnforcedEnable":"no","status":"deleted"}}}`)
+}
+
+func TestVRFGet(t *testing.T) {
+	_, c := newFakeAPIC(t, map[string]string{
+		vrfPath: `{"imdata":[{"fvCtx":{"attributes":{"name":"v1","pcEnfPref":"unenforced","pcEnfDir":"egress","bdEnforcedEnable":"yes"},"children":[
+			{"vzAny":{"attributes":{},"children":[
+				{"vzRsAnyToProv":{"attributes":{"tnVzBrCPName":"web"}}},
+				{"vzRsAnyToCons":{"attributes":{"tnVzBrCPName":"dns"}}}
+			]}},
+			{"fvRsCtxToExtRouteTagPol":{"attributes":{"tnL3extRouteTagPolName":"tags"}}},
+			{"fvRsBgpCtxPol":{"attributes":{"tnBgpCtxPolName":"timers"}}}
+		]}}]}`,
+	})
+
+	vrf, err := c.VRF.Get(context.Background(), "t1", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vrf.String() != "t1/v1" || vrf.Enforcement() != "unenforced" || vrf.EnforcementDirection() != "egress" || !vrf.BDEnforced() {
+		t.Errorf("got vrf %s %s %s %t", vrf, vrf.Enforcement(), vrf.EnforcementDirection(), vrf.BDEnforced())
+	}
+	if !reflect.DeepEqual(vrf.ProvidedContracts(), []string{"web"}) || !reflect.DeepEqual(vrf.ConsumedContracts(), []string{"dns"}) {
+		t.Errorf("got contracts provided %v consumed %v", vrf.ProvidedContracts(), vrf.ConsumedContracts())
+	}
+	if vrf.RouteTagPolicy() != "tags" || vrf.BGPTimersPolicy() != "timers" {
+		t.Errorf("got policies %s %s", vrf.RouteTagPolicy(), vrf.BGPTimersPolicy())
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/robphoenix/go-aci
+
+go 1.17
+
+require gopkg.in/yaml.v3 v3.0.1